@@ -0,0 +1,186 @@
+// alias.go implements index aliases for zero-downtime reindexing.
+//
+// The service has no alias feature, so aliases live in a client-side
+// registry persisted in a well-known encrypted index. Each alias is an item
+// whose metadata names its target index; updates use UpsertConditional so
+// concurrent swaps of the same alias cannot silently overwrite each other.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AliasRegistryIndexName is the name of the index that stores aliases.
+const AliasRegistryIndexName = "_cyborgdb_aliases"
+
+// Alias registry item metadata keys.
+const (
+	aliasTargetKey    = "target"
+	aliasUpdatedAtKey = "updated_at"
+)
+
+var (
+	// ErrAliasNotFound is returned when an alias does not exist.
+	ErrAliasNotFound = errors.New("alias not found")
+	// ErrAliasExists is returned by CreateAlias when the alias already exists.
+	ErrAliasExists = errors.New("alias already exists")
+	// ErrAliasConflict is returned by SwapAlias when the alias changed
+	// between reading and writing it; re-resolve and retry.
+	ErrAliasConflict = errors.New("alias was modified concurrently")
+)
+
+// aliasVector is the placeholder vector stored with every alias item.
+var aliasVector = []float32{1, 0}
+
+// AliasRegistry manages index aliases. Applications resolve a stable alias
+// at startup (or on an alias-swap event) instead of hard-coding index names.
+type AliasRegistry struct {
+	client *Client
+	index  *EncryptedIndex
+}
+
+// AliasRegistry opens the alias registry, creating it on first use. All
+// clients sharing aliases must use the same registry key.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - key: 32-byte key protecting the registry index
+//
+// Returns:
+//   - *AliasRegistry: The registry
+//   - error: Any error encountered
+func (c *Client) AliasRegistry(ctx context.Context, key []byte) (*AliasRegistry, error) {
+	names, err := c.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if name == AliasRegistryIndexName {
+			index, err := c.LoadIndex(ctx, AliasRegistryIndexName, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load alias registry: %w", err)
+			}
+			return &AliasRegistry{client: c, index: index}, nil
+		}
+	}
+
+	index, err := c.CreateIndex(ctx, &CreateIndexParams{
+		IndexName:   AliasRegistryIndexName,
+		IndexKey:    key,
+		IndexConfig: IndexIVFFlat(int32(len(aliasVector))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alias registry: %w", err)
+	}
+	return &AliasRegistry{client: c, index: index}, nil
+}
+
+// CreateAlias points a new alias at target. It returns ErrAliasExists if
+// the alias is already defined.
+func (r *AliasRegistry) CreateAlias(ctx context.Context, alias, target string) error {
+	res, err := r.index.UpsertConditional(ctx, []VectorItem{aliasItem(alias, target, nil)}, UpsertInsertOnly)
+	if err != nil {
+		return err
+	}
+	if len(res.Conflicts) > 0 {
+		return fmt.Errorf("%w: %q", ErrAliasExists, alias)
+	}
+	return nil
+}
+
+// SwapAlias atomically repoints an existing alias to newTarget and returns
+// the previous target. It returns ErrAliasConflict if another writer swapped
+// the alias in the meantime.
+//
+// Example:
+//
+//	// build docs_v2 while the app queries "docs" -> docs_v1, then:
+//	old, err := registry.SwapAlias(ctx, "docs", "docs_v2")
+func (r *AliasRegistry) SwapAlias(ctx context.Context, alias, newTarget string) (string, error) {
+	current, version, err := r.lookup(ctx, alias)
+	if err != nil {
+		return "", err
+	}
+	res, err := r.index.UpsertConditional(ctx, []VectorItem{aliasItem(alias, newTarget, &version)}, UpsertIfVersionMatches)
+	if err != nil {
+		return "", err
+	}
+	if len(res.Conflicts) > 0 {
+		return "", fmt.Errorf("%w: %q", ErrAliasConflict, alias)
+	}
+	return current, nil
+}
+
+// ResolveAlias returns the index name alias points to.
+func (r *AliasRegistry) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	target, _, err := r.lookup(ctx, alias)
+	return target, err
+}
+
+// DeleteAlias removes alias. The target index is not affected.
+func (r *AliasRegistry) DeleteAlias(ctx context.Context, alias string) error {
+	if _, _, err := r.lookup(ctx, alias); err != nil {
+		return err
+	}
+	return r.index.Delete(ctx, []string{alias})
+}
+
+// ListAliases returns every alias and its target.
+func (r *AliasRegistry) ListAliases(ctx context.Context) (map[string]string, error) {
+	out := make(map[string]string)
+	it := r.index.Scan(ctx, ScanOptions{Include: []string{"metadata"}})
+	for it.Next() {
+		if target, ok := it.Item().Metadata[aliasTargetKey].(string); ok {
+			out[it.Item().Id] = target
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoadIndex resolves alias and loads its target index with key.
+func (r *AliasRegistry) LoadIndex(ctx context.Context, alias string, key []byte) (*EncryptedIndex, error) {
+	target, err := r.ResolveAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.LoadIndex(ctx, target, key)
+}
+
+// lookup returns the alias target and its registry version.
+func (r *AliasRegistry) lookup(ctx context.Context, alias string) (string, int64, error) {
+	resp, err := r.index.Get(ctx, []string{alias}, []string{"metadata"})
+	if err != nil {
+		return "", 0, err
+	}
+	for _, item := range resp.Results {
+		if item.Id != alias {
+			continue
+		}
+		target, ok := item.Metadata[aliasTargetKey].(string)
+		if !ok {
+			break
+		}
+		version, _ := metadataVersion(item.Metadata)
+		return target, version, nil
+	}
+	return "", 0, fmt.Errorf("%w: %q", ErrAliasNotFound, alias)
+}
+
+// aliasItem builds the registry item for alias. version, if set, is the
+// version the writer expects to replace.
+func aliasItem(alias, target string, version *int64) VectorItem {
+	metadata := map[string]interface{}{
+		aliasTargetKey:    target,
+		aliasUpdatedAtKey: time.Now().UTC().Format(time.RFC3339),
+	}
+	if version != nil {
+		metadata[VersionMetadataKey] = *version
+	}
+	return VectorItem{Id: alias, Vector: aliasVector, Metadata: metadata}
+}
@@ -0,0 +1,294 @@
+// apply.go implements declarative index management: callers describe the
+// indexes that should exist and Apply converges the service to that state.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidIndexSpec is returned when an IndexSpec is incomplete or invalid.
+var ErrInvalidIndexSpec = errors.New("invalid index spec")
+
+// IndexSpec is the desired state of one index.
+type IndexSpec struct {
+	// Name is the index name (required).
+	Name string `json:"name"`
+	// Type is "ivf", "ivfflat" (default), or "ivfpq".
+	Type string `json:"type,omitempty"`
+	// Dimension is the vector dimension; 0 lets the server decide.
+	Dimension int32 `json:"dimension,omitempty"`
+	// Metric is the distance metric, e.g. "euclidean" or "cosine".
+	Metric string `json:"metric,omitempty"`
+	// PQDim and PQBits configure "ivfpq" indexes.
+	PQDim  int32 `json:"pq_dim,omitempty"`
+	PQBits int32 `json:"pq_bits,omitempty"`
+	// Labels are recorded in the index catalog at creation, if enabled.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// IndexSpecs is a declarative set of indexes, as read from a config file.
+type IndexSpecs struct {
+	Indexes []IndexSpec `json:"indexes"`
+}
+
+// ApplyOp is the change Apply makes to one index.
+type ApplyOp string
+
+const (
+	// ApplyCreate creates a missing index.
+	ApplyCreate ApplyOp = "create"
+	// ApplyRecreate deletes and recreates an index whose configuration drifted.
+	ApplyRecreate ApplyOp = "recreate"
+	// ApplyDelete deletes an index that is not in the specs.
+	ApplyDelete ApplyOp = "delete"
+	// ApplyUnchanged leaves a matching index alone.
+	ApplyUnchanged ApplyOp = "unchanged"
+	// ApplyDrift reports a drifted index that was left alone because
+	// ApplyOptions.Recreate is off.
+	ApplyDrift ApplyOp = "drift"
+)
+
+// ApplyAction is the planned or performed change to one index.
+type ApplyAction struct {
+	Index  string  `json:"index"`
+	Op     ApplyOp `json:"op"`
+	Reason string  `json:"reason,omitempty"`
+	Err    error   `json:"-"`
+}
+
+// ApplyOptions controls Apply.
+type ApplyOptions struct {
+	// Keys resolves the key of every index Apply creates, inspects, or
+	// deletes (required).
+	Keys KeyProvider
+
+	// Prune deletes indexes that exist on the service but are not in the
+	// specs. Internal indexes (alias registry, catalog) are never pruned.
+	// With no specs at all, Prune would delete every index, so it is
+	// refused unless AllowEmptyPrune is also set.
+	Prune bool
+
+	// AllowEmptyPrune lets Prune run with empty specs, deleting every
+	// non-internal index.
+	AllowEmptyPrune bool
+
+	// Recreate deletes and recreates indexes whose configuration differs from
+	// the spec. The service cannot change an index in place, so this discards
+	// the index's vectors. When false, drift is only reported.
+	Recreate bool
+
+	// DryRun computes the plan without changing anything.
+	DryRun bool
+}
+
+// ApplyResult lists the action taken for each index, sorted by name.
+type ApplyResult struct {
+	Actions []ApplyAction `json:"actions"`
+}
+
+// Failed returns the actions that returned an error.
+func (r *ApplyResult) Failed() []ApplyAction {
+	var out []ApplyAction
+	for _, a := range r.Actions {
+		if a.Err != nil {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Apply converges the service to specs: missing indexes are created, drifted
+// ones are reported or recreated, and with Prune, unlisted ones are deleted.
+// It keeps going after a failed action; the result records each action's
+// error and the returned error summarizes them.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - specs: Desired indexes
+//   - opts: Key source and behavior flags
+//
+// Returns:
+//   - *ApplyResult: The plan, with per-action errors
+//   - error: ErrInvalidIndexSpec (including Prune with empty specs), a
+//     listing error, or a summary of failed actions
+//
+// Example:
+//
+//	res, err := client.Apply(ctx, specs, cyborgdb.ApplyOptions{Keys: provider, Prune: true})
+func (c *Client) Apply(ctx context.Context, specs IndexSpecs, opts ApplyOptions) (*ApplyResult, error) {
+	if opts.Keys == nil {
+		return nil, fmt.Errorf("%w: ApplyOptions.Keys is required", ErrInvalidIndexSpec)
+	}
+	if opts.Prune && len(specs.Indexes) == 0 && !opts.AllowEmptyPrune {
+		return nil, fmt.Errorf("%w: refusing to prune with no indexes in specs; set AllowEmptyPrune to delete every index", ErrInvalidIndexSpec)
+	}
+	desired := make(map[string]IndexSpec, len(specs.Indexes))
+	for _, spec := range specs.Indexes {
+		if err := spec.validate(); err != nil {
+			return nil, err
+		}
+		if _, dup := desired[spec.Name]; dup {
+			return nil, fmt.Errorf("%w: duplicate index %q", ErrInvalidIndexSpec, spec.Name)
+		}
+		desired[spec.Name] = spec
+	}
+
+	names, err := c.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+
+	result := &ApplyResult{}
+	for name, spec := range desired {
+		result.Actions = append(result.Actions, c.applySpec(ctx, spec, existing[name], opts))
+	}
+	if opts.Prune {
+		for _, name := range names {
+			if _, ok := desired[name]; ok || isInternalIndex(name) {
+				continue
+			}
+			action := ApplyAction{Index: name, Op: ApplyDelete, Reason: "not in specs"}
+			if !opts.DryRun {
+				action.Err = c.applyDelete(ctx, name, opts.Keys)
+			}
+			result.Actions = append(result.Actions, action)
+		}
+	}
+	sort.Slice(result.Actions, func(i, j int) bool { return result.Actions[i].Index < result.Actions[j].Index })
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return result, fmt.Errorf("%d of %d apply actions failed; first: %s %q: %w",
+			len(failed), len(result.Actions), failed[0].Op, failed[0].Index, failed[0].Err)
+	}
+	return result, nil
+}
+
+// applySpec plans and, unless DryRun, performs the change for one spec.
+func (c *Client) applySpec(ctx context.Context, spec IndexSpec, exists bool, opts ApplyOptions) ApplyAction {
+	action := ApplyAction{Index: spec.Name, Op: ApplyCreate}
+	key, err := resolveKey(ctx, opts.Keys, spec.Name)
+	if err != nil {
+		action.Err = err
+		return action
+	}
+	defer Zeroize(key)
+
+	if exists {
+		index, err := c.LoadIndex(ctx, spec.Name, key)
+		if err != nil {
+			action.Op, action.Err = ApplyUnchanged, err
+			return action
+		}
+		action.Reason = spec.diff(index)
+		switch {
+		case action.Reason == "":
+			action.Op = ApplyUnchanged
+			return action
+		case !opts.Recreate:
+			action.Op = ApplyDrift
+			return action
+		}
+		action.Op = ApplyRecreate
+		if opts.DryRun {
+			return action
+		}
+		if err := index.DeleteIndex(ctx); err != nil {
+			action.Err = err
+			return action
+		}
+	}
+
+	if opts.DryRun {
+		return action
+	}
+	_, action.Err = c.CreateIndex(ctx, spec.createParams(key))
+	return action
+}
+
+// applyDelete deletes the named index.
+func (c *Client) applyDelete(ctx context.Context, name string, keys KeyProvider) error {
+	index, err := c.LoadIndexWithKeyProvider(ctx, name, keys)
+	if err != nil {
+		return err
+	}
+	return index.DeleteIndex(ctx)
+}
+
+// validate checks that spec is complete.
+func (s IndexSpec) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidIndexSpec)
+	}
+	switch s.Type {
+	case "", "ivf", "ivfflat":
+	case "ivfpq":
+		if s.PQDim <= 0 || s.PQBits <= 0 {
+			return fmt.Errorf("%w: %q: ivfpq requires pq_dim and pq_bits", ErrInvalidIndexSpec, s.Name)
+		}
+	default:
+		return fmt.Errorf("%w: %q: unknown type %q", ErrInvalidIndexSpec, s.Name, s.Type)
+	}
+	if s.Dimension < 0 {
+		return fmt.Errorf("%w: %q: negative dimension", ErrInvalidIndexSpec, s.Name)
+	}
+	return nil
+}
+
+// indexType returns the spec's type, defaulting to "ivfflat".
+func (s IndexSpec) indexType() string {
+	if s.Type == "" {
+		return "ivfflat"
+	}
+	return s.Type
+}
+
+// createParams returns the CreateIndexParams for the spec.
+func (s IndexSpec) createParams(key []byte) *CreateIndexParams {
+	params := &CreateIndexParams{IndexName: s.Name, IndexKey: key, Labels: s.Labels}
+	switch s.indexType() {
+	case "ivf":
+		params.IndexConfig = IndexIVF(s.Dimension)
+	case "ivfpq":
+		params.IndexConfig = IndexIVFPQ(s.Dimension, s.PQDim, s.PQBits)
+	default:
+		params.IndexConfig = IndexIVFFlat(s.Dimension)
+	}
+	if s.Metric != "" {
+		params.Metric = String(s.Metric)
+	}
+	return params
+}
+
+// diff describes how index differs from the spec, or returns "" if it
+// matches. Settings the spec leaves unset, or the server does not report,
+// are not compared.
+func (s IndexSpec) diff(index *EncryptedIndex) string {
+	if t := index.GetIndexType(); t != "" && t != s.indexType() {
+		return fmt.Sprintf("type is %s, want %s", t, s.indexType())
+	}
+	if d := index.configDimension(); s.Dimension != 0 && d != 0 && d != s.Dimension {
+		return fmt.Sprintf("dimension is %d, want %d", d, s.Dimension)
+	}
+	if m := index.GetMetric(); s.Metric != "" && m != "" && m != s.Metric {
+		return fmt.Sprintf("metric is %s, want %s", m, s.Metric)
+	}
+	if cfg := index.GetIndexConfig(); cfg.Type == "ivfpq" && s.indexType() == "ivfpq" {
+		if cfg.PQDim != s.PQDim || cfg.PQBits != s.PQBits {
+			return fmt.Sprintf("pq is %d/%d, want %d/%d", cfg.PQDim, cfg.PQBits, s.PQDim, s.PQBits)
+		}
+	}
+	return ""
+}
+
+// isInternalIndex reports whether name is an index the SDK manages itself.
+func isInternalIndex(name string) bool {
+	return name == AliasRegistryIndexName || name == IndexCatalogName || strings.HasPrefix(name, schemaIndexPrefix)
+}
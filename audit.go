@@ -0,0 +1,144 @@
+// audit.go implements an optional audit trail of mutating SDK calls.
+package cyborgdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditOperation names a mutating operation.
+type AuditOperation string
+
+const (
+	// AuditCreateIndex records CreateIndex.
+	AuditCreateIndex AuditOperation = "create_index"
+	// AuditUpsert records each upsert request (Upsert, Import batches, ...).
+	AuditUpsert AuditOperation = "upsert"
+	// AuditDelete records Delete.
+	AuditDelete AuditOperation = "delete"
+	// AuditTrain records Train.
+	AuditTrain AuditOperation = "train"
+	// AuditDeleteIndex records DeleteIndex.
+	AuditDeleteIndex AuditOperation = "delete_index"
+)
+
+// AuditRecord describes one mutating call.
+type AuditRecord struct {
+	// Time is when the call started.
+	Time time.Time
+	// Operation is the kind of mutation.
+	Operation AuditOperation
+	// Index is the index name.
+	Index string
+	// Count is the number of items written or deleted, 0 for index-level
+	// operations.
+	Count int
+	// Actor is the caller identity attached with WithActor, if any.
+	Actor string
+	// Duration is how long the call took.
+	Duration time.Duration
+	// Err is the call's error, nil on success.
+	Err error
+}
+
+// AuditSink receives a record for every mutating call made by a client.
+//
+// Audit is called synchronously after the call completes, possibly from
+// many goroutines at once, so implementations must be safe for concurrent
+// use and should hand slow work off to a queue.
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord)
+}
+
+// AuditSinkFunc adapts a function to AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord)
+
+// Audit implements AuditSink.
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) { f(ctx, record) }
+
+// WithAuditSink sends an AuditRecord to sink for every mutating call
+// (CreateIndex, Upsert, Delete, Train, DeleteIndex) made through the client
+// and its index handles.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithAuditSink(cyborgdb.NewJSONAuditSink(auditFile)))
+func WithAuditSink(sink AuditSink) ClientOption {
+	return func(o *clientOptions) { o.auditSink = sink }
+}
+
+// actorKey is the context key for the audit actor.
+type actorKey struct{}
+
+// WithActor returns a context whose mutating calls are attributed to actor
+// (a user, service, or job identity) in audit records.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor attached with WithActor, or "".
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// NewJSONAuditSink returns an AuditSink that writes each record to w as a
+// line of JSON. Writes are serialized; write errors are dropped.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{enc: json.NewEncoder(w)}
+}
+
+type jsonAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// Audit implements AuditSink.
+func (s *jsonAuditSink) Audit(_ context.Context, r AuditRecord) {
+	line := struct {
+		Time       time.Time      `json:"time"`
+		Operation  AuditOperation `json:"operation"`
+		Index      string         `json:"index"`
+		Count      int            `json:"count"`
+		Actor      string         `json:"actor,omitempty"`
+		DurationMs int64          `json:"duration_ms"`
+		Result     string         `json:"result"`
+		Error      string         `json:"error,omitempty"`
+	}{
+		Time:       r.Time.UTC(),
+		Operation:  r.Operation,
+		Index:      r.Index,
+		Count:      r.Count,
+		Actor:      r.Actor,
+		DurationMs: r.Duration.Milliseconds(),
+		Result:     "ok",
+	}
+	if r.Err != nil {
+		line.Result, line.Error = "error", r.Err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.enc.Encode(line)
+}
+
+// audit reports a mutating call that started at start to the configured
+// sink, if any.
+func (o *clientOptions) audit(ctx context.Context, op AuditOperation, index string, count int, start time.Time, err error) {
+	if o == nil || o.auditSink == nil {
+		return
+	}
+	o.auditSink.Audit(ctx, AuditRecord{
+		Time:      start,
+		Operation: op,
+		Index:     index,
+		Count:     count,
+		Actor:     ActorFromContext(ctx),
+		Duration:  time.Since(start),
+		Err:       err,
+	})
+}
@@ -0,0 +1,112 @@
+// autotrain.go implements an automatic training policy on the index handle.
+package cyborgdb
+
+import (
+	"context"
+	"sync"
+)
+
+// AutoTrain is a policy that trains an index once enough vectors have been
+// upserted through its handle, removing the "did we train yet?" bookkeeping.
+type AutoTrain struct {
+	// MinVectors is the number of vectors after which training is triggered.
+	// A common choice is a few times the number of IVF lists.
+	MinVectors int
+
+	// CountExisting seeds the count with the number of vectors already in the
+	// index (one ListIDs call) on the first upsert.
+	CountExisting bool
+
+	// WarnOnly calls OnThreshold without training, for callers who train on
+	// their own schedule.
+	WarnOnly bool
+
+	// Params are the training options used when training is triggered.
+	Params TrainParams
+
+	// OnThreshold, if set, is called once when the threshold is crossed, with
+	// the vector count and the background training job (nil with WarnOnly).
+	OnThreshold func(count int, job *TrainingJob)
+}
+
+// autoTrainState tracks upserted vectors against an AutoTrain policy.
+type autoTrainState struct {
+	policy AutoTrain
+
+	mu     sync.Mutex
+	count  int
+	seeded bool
+	fired  bool
+}
+
+// SetAutoTrain attaches an automatic training policy to this handle. Vectors
+// upserted through the handle (Upsert, Import, UpsertStream, ...) are counted;
+// when the count reaches policy.MinVectors and the index is untrained,
+// training starts in the background, once. Pass nil to remove the policy.
+//
+// Example:
+//
+//	index.SetAutoTrain(&cyborgdb.AutoTrain{
+//		MinVectors:    50000,
+//		CountExisting: true,
+//		OnThreshold: func(n int, job *cyborgdb.TrainingJob) {
+//			log.Printf("training after %d vectors", n)
+//		},
+//	})
+func (e *EncryptedIndex) SetAutoTrain(policy *AutoTrain) {
+	var state *autoTrainState
+	if policy != nil && policy.MinVectors > 0 {
+		state = &autoTrainState{policy: *policy}
+	}
+	e.mu.Lock()
+	e.autoTrain = state
+	e.mu.Unlock()
+}
+
+// observeUpsert counts n upserted vectors and applies the auto-train policy.
+// serverTriggered reports that the server already started training.
+func (e *EncryptedIndex) observeUpsert(ctx context.Context, n int, serverTriggered bool) {
+	e.mu.RLock()
+	s := e.autoTrain
+	e.mu.RUnlock()
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.fired {
+		s.mu.Unlock()
+		return
+	}
+	if serverTriggered {
+		s.fired = true
+		s.mu.Unlock()
+		return
+	}
+	if !s.seeded {
+		s.seeded = true
+		if s.policy.CountExisting {
+			// The IDs listed already include this upsert.
+			if ids, err := e.ListIDs(ctx); err == nil {
+				s.count = len(ids.Ids) - n
+			}
+		}
+	}
+	s.count += n
+	if s.count < s.policy.MinVectors || e.IsTrained() {
+		s.mu.Unlock()
+		return
+	}
+	s.fired = true
+	count := s.count
+	s.mu.Unlock()
+
+	var job *TrainingJob
+	if !s.policy.WarnOnly {
+		// Training outlives the upsert that triggered it.
+		job = e.TrainAsync(context.Background(), s.policy.Params)
+	}
+	if s.policy.OnThreshold != nil {
+		s.policy.OnThreshold(count, job)
+	}
+}
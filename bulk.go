@@ -0,0 +1,336 @@
+// bulk.go implements BulkWriter, which buffers mixed upserts and deletes and
+// writes them in batches.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBulkBatchSize is the default number of items or IDs per request
+	// sent by a BulkWriter.
+	DefaultBulkBatchSize = 500
+	// DefaultBulkMaxBuffered is the default number of buffered operations at
+	// which a BulkWriter flushes on its own.
+	DefaultBulkMaxBuffered = 10000
+	// DefaultBulkMaxRetries is the default number of retries of a batch that
+	// fails transiently.
+	DefaultBulkMaxRetries = 2
+)
+
+// ErrBulkWriterClosed is returned when a BulkWriter is used after Close.
+var ErrBulkWriterClosed = errors.New("bulk writer closed")
+
+// BulkWriterOptions configures a BulkWriter. Zero values fall back to defaults.
+type BulkWriterOptions struct {
+	// BatchSize is the number of items or IDs sent per request. Default: 500.
+	BatchSize int
+
+	// MaxBuffered is the number of buffered operations at which Upsert or
+	// Delete flushes before returning. Default: 10000.
+	MaxBuffered int
+
+	// MaxRetries is the number of times a batch that fails transiently (a
+	// timeout, a dropped connection, 408, or a 5xx response) is retried, with
+	// exponential backoff. Default: 2; negative disables retries.
+	MaxRetries int
+
+	// OnFlush, if set, is called at the end of each flush with running totals.
+	OnFlush func(BulkStats)
+}
+
+// BulkStats reports what a BulkWriter has written.
+type BulkStats struct {
+	// Upserted is the number of items upserted.
+	Upserted int `json:"upserted"`
+	// Deleted is the number of IDs deleted.
+	Deleted int `json:"deleted"`
+	// Failed is the number of operations in batches that failed.
+	Failed int `json:"failed"`
+	// Coalesced is the number of operations superseded by a later operation
+	// on the same ID before they were sent.
+	Coalesced int `json:"coalesced"`
+	// Flushes is the number of flushes.
+	Flushes int `json:"flushes"`
+}
+
+// BulkFailure describes a batch a BulkWriter could not write.
+type BulkFailure struct {
+	// Items holds the items of a failed upsert batch.
+	Items []VectorItem
+	// IDs holds the IDs of a failed delete batch.
+	IDs []string
+	// State is BatchFailed, BatchAborted, or BatchSkipped.
+	State BatchState
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// BulkError aggregates the failed batches of a flush. The operations in them
+// are no longer buffered; requeue Items and IDs to retry them.
+type BulkError struct {
+	Failures []BulkFailure
+}
+
+func (e *BulkError) Error() string {
+	var b strings.Builder
+	ops := 0
+	for _, f := range e.Failures {
+		ops += len(f.Items) + len(f.IDs)
+	}
+	fmt.Fprintf(&b, "bulk write failed for %d operations in %d batches", ops, len(e.Failures))
+	if len(e.Failures) > 0 {
+		fmt.Fprintf(&b, ": %v", e.Failures[0].Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of the first failed batch.
+func (e *BulkError) Unwrap() error {
+	if len(e.Failures) == 0 {
+		return nil
+	}
+	return e.Failures[0].Err
+}
+
+// BulkWriter buffers upserts and deletes for an index and writes them in
+// batches, for ETL pipelines that produce mixed operations one at a time.
+//
+// Operations on the same ID are coalesced while buffered: only the last one
+// is sent, so an upsert followed by a delete sends just the delete, and a
+// delete followed by an upsert sends just the upsert. Because each ID then
+// has a single pending operation, a flush sends all deletes and then all
+// upserts without changing the outcome.
+//
+// A BulkWriter is safe for concurrent use. Flushes are serialized, and
+// operations added during a flush are sent by the next one.
+type BulkWriter struct {
+	index *EncryptedIndex
+	opts  BulkWriterOptions
+
+	flushMu sync.Mutex
+
+	mu      sync.Mutex
+	upserts map[string]VectorItem
+	deletes map[string]bool
+	order   []string
+	stats   BulkStats
+	closed  bool
+}
+
+// NewBulkWriter returns a BulkWriter for the index.
+//
+// Example:
+//
+//	w := index.NewBulkWriter(cyborgdb.BulkWriterOptions{BatchSize: 1000})
+//	for ev := range events {
+//		if ev.Deleted {
+//			err = w.Delete(ctx, ev.ID)
+//		} else {
+//			err = w.Upsert(ctx, ev.Item)
+//		}
+//		if err != nil {
+//			log.Print(err) // failed batches of an automatic flush
+//		}
+//	}
+//	if err := w.Close(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *EncryptedIndex) NewBulkWriter(opts BulkWriterOptions) *BulkWriter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBulkBatchSize
+	}
+	if opts.MaxBuffered <= 0 {
+		opts.MaxBuffered = DefaultBulkMaxBuffered
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultBulkMaxRetries
+	}
+	return &BulkWriter{
+		index:   e,
+		opts:    opts,
+		upserts: make(map[string]VectorItem),
+		deletes: make(map[string]bool),
+	}
+}
+
+// Upsert buffers items for upserting. If the buffer reaches MaxBuffered it
+// flushes first, returning any *BulkError of that flush.
+func (w *BulkWriter) Upsert(ctx context.Context, items ...VectorItem) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrBulkWriterClosed
+	}
+	for _, item := range items {
+		w.add(item.Id)
+		w.upserts[item.Id] = item
+	}
+	full := len(w.order) >= w.opts.MaxBuffered
+	w.mu.Unlock()
+	if full {
+		return w.Flush(ctx)
+	}
+	return nil
+}
+
+// Delete buffers ids for deletion. If the buffer reaches MaxBuffered it
+// flushes first, returning any *BulkError of that flush.
+func (w *BulkWriter) Delete(ctx context.Context, ids ...string) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrBulkWriterClosed
+	}
+	for _, id := range ids {
+		w.add(id)
+		w.deletes[id] = true
+	}
+	full := len(w.order) >= w.opts.MaxBuffered
+	w.mu.Unlock()
+	if full {
+		return w.Flush(ctx)
+	}
+	return nil
+}
+
+// add drops any pending operation on id and records it in the buffer order.
+// Callers hold mu.
+func (w *BulkWriter) add(id string) {
+	_, upsert := w.upserts[id]
+	if upsert || w.deletes[id] {
+		delete(w.upserts, id)
+		delete(w.deletes, id)
+		w.stats.Coalesced++
+		return
+	}
+	w.order = append(w.order, id)
+}
+
+// Buffered returns the number of operations waiting to be flushed.
+func (w *BulkWriter) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.order)
+}
+
+// Stats returns running totals.
+func (w *BulkWriter) Stats() BulkStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stats
+}
+
+// Flush writes every buffered operation: deletes first, then upserts, in
+// batches of BatchSize, retrying transient failures. A batch that still
+// fails does not stop the others; all such batches are returned in a
+// *BulkError.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts; batches not yet sent when
+//     it is done are reported with BatchSkipped
+//
+// Returns:
+//   - error: A *BulkError if any batch failed, nil otherwise
+func (w *BulkWriter) Flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	var (
+		deletes []string
+		upserts []VectorItem
+	)
+	for _, id := range w.order {
+		if item, ok := w.upserts[id]; ok {
+			upserts = append(upserts, item)
+		} else if w.deletes[id] {
+			deletes = append(deletes, id)
+		}
+	}
+	w.upserts = make(map[string]VectorItem)
+	w.deletes = make(map[string]bool)
+	w.order = nil
+	w.mu.Unlock()
+
+	var failures []BulkFailure
+	deleted, upserted := 0, 0
+	for _, batch := range chunkIDs(deletes, w.opts.BatchSize) {
+		state, err := w.retry(ctx, func() error { return w.index.Delete(ctx, batch) })
+		if err != nil {
+			failures = append(failures, BulkFailure{IDs: batch, State: state, Err: err})
+			continue
+		}
+		deleted += len(batch)
+	}
+	for start := 0; start < len(upserts); start += w.opts.BatchSize {
+		end := start + w.opts.BatchSize
+		if end > len(upserts) {
+			end = len(upserts)
+		}
+		batch := upserts[start:end]
+		state, err := w.retry(ctx, func() error { return w.index.Upsert(ctx, batch) })
+		if err != nil {
+			failures = append(failures, BulkFailure{Items: batch, State: state, Err: err})
+			continue
+		}
+		upserted += len(batch)
+	}
+
+	w.mu.Lock()
+	w.stats.Deleted += deleted
+	w.stats.Upserted += upserted
+	w.stats.Failed += len(deletes) + len(upserts) - deleted - upserted
+	w.stats.Flushes++
+	stats := w.stats
+	w.mu.Unlock()
+	if w.opts.OnFlush != nil {
+		w.opts.OnFlush(stats)
+	}
+
+	if len(failures) > 0 {
+		return &BulkError{Failures: failures}
+	}
+	return nil
+}
+
+// Close flushes the buffer and rejects further operations. Closing a closed
+// BulkWriter is a no-op.
+func (w *BulkWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+	return w.Flush(ctx)
+}
+
+// retry calls send until it succeeds, fails permanently, or MaxRetries
+// retries are used up, and returns the batch's state.
+func (w *BulkWriter) retry(ctx context.Context, send func() error) (BatchState, error) {
+	if err := ctx.Err(); err != nil {
+		return BatchSkipped, err
+	}
+	backoff := DefaultUpsertRetryInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := send()
+		if err == nil || attempt >= w.opts.MaxRetries || ctx.Err() != nil || !retryableUpsertError(err) {
+			return batchState(err), err
+		}
+		select {
+		case <-ctx.Done():
+			return batchState(err), err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > DefaultUpsertRetryMaxBackoff {
+			backoff = DefaultUpsertRetryMaxBackoff
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// cache.go implements an optional client-side LRU cache of query responses.
+package cyborgdb
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultQueryCacheTTL is the entry lifetime used by WithQueryCache when ttl
+// is not positive.
+const DefaultQueryCacheTTL = time.Minute
+
+// WithQueryCache caches up to size query responses per client for ttl
+// (DefaultQueryCacheTTL if <= 0), keyed by index, query vector, and all query
+// parameters including filters. Upsert, Delete, Train, and DeleteIndex on an
+// index invalidate its entries, so a handle always sees its own writes;
+// writes made by other clients or processes are seen once entries expire.
+//
+// Cached responses are shared: treat responses returned by Query as
+// read-only when the cache is enabled.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithQueryCache(10000, 30*time.Second))
+func WithQueryCache(size int, ttl time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		if size <= 0 {
+			o.queryCache = nil
+			return
+		}
+		if ttl <= 0 {
+			ttl = DefaultQueryCacheTTL
+		}
+		o.queryCache = newQueryCache(size, ttl)
+	}
+}
+
+// InvalidateQueryCache drops this index's cached query responses. It is only
+// needed after writes made through another client or process.
+func (e *EncryptedIndex) InvalidateQueryCache() {
+	if e.opts.queryCache != nil {
+		e.opts.queryCache.invalidate(e.indexName)
+	}
+}
+
+// queryCache is an LRU cache with per-entry expiry. Invalidation bumps a
+// per-index generation that is part of every key, so stale entries are never
+// hit and age out of the LRU naturally.
+type queryCache struct {
+	size int
+	ttl  time.Duration
+
+	mu          sync.Mutex
+	lru         *list.List // of *cacheEntry, most recent first
+	entries     map[[sha256.Size]byte]*list.Element
+	generations map[string]uint64
+}
+
+type cacheEntry struct {
+	key     [sha256.Size]byte
+	resp    *QueryResponse
+	expires time.Time
+}
+
+func newQueryCache(size int, ttl time.Duration) *queryCache {
+	return &queryCache{
+		size:        size,
+		ttl:         ttl,
+		lru:         list.New(),
+		entries:     make(map[[sha256.Size]byte]*list.Element),
+		generations: make(map[string]uint64),
+	}
+}
+
+// query answers params from the cache or runs it through e.query.
+func (c *queryCache) query(ctx context.Context, e *EncryptedIndex, params QueryParams) (*QueryResponse, error) {
+	c.mu.Lock()
+	generation := c.generations[e.indexName]
+	c.mu.Unlock()
+
+	key, err := queryCacheKey(e.indexName, generation, params)
+	if err != nil {
+		return e.query(ctx, params)
+	}
+	if resp, ok := c.get(key); ok {
+		return resp, nil
+	}
+
+	resp, err := e.query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, resp)
+	return copyQueryResponse(resp), nil
+}
+
+func (c *queryCache) get(key [sha256.Size]byte) (*QueryResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return copyQueryResponse(entry.resp), true
+}
+
+func (c *queryCache) put(key [sha256.Size]byte, resp *QueryResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, resp: resp, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// invalidate makes all cached entries for indexName unreachable.
+func (c *queryCache) invalidate(indexName string) {
+	c.mu.Lock()
+	c.generations[indexName]++
+	c.mu.Unlock()
+}
+
+// queryCacheKey hashes the index, its cache generation, and every query
+// parameter, including the client-side ones not sent to the server.
+func queryCacheKey(indexName string, generation uint64, params QueryParams) ([sha256.Size]byte, error) {
+	data, err := json.Marshal(struct {
+		Index            string        `json:"index"`
+		Generation       uint64        `json:"generation"`
+		Params           QueryParams   `json:"params"`
+		RerankExact      bool          `json:"rerank_exact"`
+		RerankCandidates int32         `json:"rerank_candidates"`
+		MMRLambda        *float64      `json:"mmr_lambda"`
+		MinScore         *float32      `json:"min_score"`
+		SparseVector     *SparseVector `json:"sparse_vector"`
+		Offset           int32         `json:"offset"`
+	}{indexName, generation, params, params.RerankExact, params.RerankCandidates,
+		params.MMRLambda, params.MinScore, params.SparseVector, params.Offset})
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// copyQueryResponse copies the result lists of resp so callers can reorder
+// or truncate them without affecting the cached response.
+func copyQueryResponse(resp *QueryResponse) *QueryResponse {
+	out := *resp
+	if r := resp.Results.ArrayOfQueryResultItem; r != nil {
+		items := append([]QueryResultItem(nil), *r...)
+		out.Results.ArrayOfQueryResultItem = &items
+	}
+	if r := resp.Results.ArrayOfArrayOfQueryResultItem; r != nil {
+		batches := make([][]QueryResultItem, len(*r))
+		for i, items := range *r {
+			batches[i] = append([]QueryResultItem(nil), items...)
+		}
+		out.Results.ArrayOfArrayOfQueryResultItem = &batches
+	}
+	return &out
+}
@@ -0,0 +1,94 @@
+// cancel.go defines how batched operations report their batches when the
+// context is canceled.
+//
+// Every operation returns promptly once its context is done: requests in
+// flight are aborted, including while their bodies are compressed or
+// uploaded, and waits for retries or the rate limiter end early.
+//
+// Batched operations (Import, DeleteBatched, UpsertStream, and
+// BulkWriter.Flush) additionally stop starting batches and report each batch
+// as one of the BatchState values, so a caller can tell exactly what was
+// written: BatchCommitted batches were applied, BatchFailed and BatchSkipped
+// batches were not, and BatchAborted batches were in flight when the context
+// ended and may or may not have been applied. Upserts and deletes are
+// idempotent, so resending aborted batches is always safe.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+)
+
+// BatchState is the outcome of one batch of a batched operation.
+type BatchState int
+
+const (
+	// BatchCommitted means the server accepted the batch.
+	BatchCommitted BatchState = iota
+	// BatchFailed means the server rejected the batch, or the request failed
+	// before it was sent.
+	BatchFailed
+	// BatchAborted means the request was in flight when the context ended or
+	// timed out; the server may or may not have applied it.
+	BatchAborted
+	// BatchSkipped means the batch was never sent because the context ended
+	// first.
+	BatchSkipped
+)
+
+func (s BatchState) String() string {
+	switch s {
+	case BatchCommitted:
+		return "committed"
+	case BatchFailed:
+		return "failed"
+	case BatchAborted:
+		return "aborted"
+	case BatchSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s BatchState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// BatchResult reports the outcome of one batch.
+type BatchResult struct {
+	// Seq is the 0-based batch number, in the order batches were formed.
+	Seq int `json:"seq"`
+	// Start and End delimit the batch in the input: positions [Start, End)
+	// in the ID list for DeleteBatched, and 1-based rows [Start, End) for
+	// Import. Import batches skip invalid rows, so not every row in the range
+	// belongs to the batch.
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// State is the outcome.
+	State BatchState `json:"state"`
+	// Error explains a state other than BatchCommitted.
+	Error string `json:"error,omitempty"`
+}
+
+// batchState classifies the error of a sent batch.
+func batchState(err error) BatchState {
+	switch {
+	case err == nil:
+		return BatchCommitted
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return BatchAborted
+	default:
+		return BatchFailed
+	}
+}
+
+// newBatchResult returns the result of batch seq covering [start, end).
+func newBatchResult(seq, start, end int, state BatchState, err error) BatchResult {
+	r := BatchResult{Seq: seq, Start: start, End: end, State: state}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
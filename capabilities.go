@@ -0,0 +1,167 @@
+// capabilities.go reports which features the connected server supports.
+package cyborgdb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Capabilities describes the features supported by the connected server.
+//
+// The service has no dedicated capabilities endpoint, so values are taken
+// from the health response when the server reports them, and otherwise fall
+// back to the baseline of the REST API this SDK targets. Reported tells the
+// two apart.
+type Capabilities struct {
+	// ServerVersion is the server version, if reported.
+	ServerVersion string `json:"server_version,omitempty"`
+
+	// BackendType is BackendLite or BackendFull, if reported.
+	BackendType string `json:"backend_type,omitempty"`
+
+	// IndexTypes lists the supported index types, e.g. "ivfflat".
+	IndexTypes []string `json:"index_types"`
+
+	// FilterOperators lists the supported metadata filter operators, e.g. "$gte".
+	FilterOperators []string `json:"filter_operators"`
+
+	// EmbeddingModels lists server-side embedding models, empty if none are reported.
+	EmbeddingModels []string `json:"embedding_models,omitempty"`
+
+	// MaxBatchSize is the largest number of items accepted per request, or 0
+	// if the server does not report a limit.
+	MaxBatchSize int `json:"max_batch_size,omitempty"`
+
+	// MaxBodyBytes is the largest request body accepted, or 0 if the server
+	// does not report a limit.
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+
+	// SparseVectors reports whether items and queries may carry sparse vectors.
+	SparseVectors bool `json:"sparse_vectors"`
+
+	// Reported is true if any value above came from the server rather than
+	// the SDK baseline.
+	Reported bool `json:"reported"`
+}
+
+// Baseline capabilities of the REST API this SDK targets.
+var (
+	baselineIndexTypes      = []string{"ivf", "ivfflat", "ivfpq"}
+	baselineFilterOperators = []string{
+		"$eq", "$ne", "$gt", "$gte", "$lt", "$lte", "$in", "$nin", "$exists", "$and", "$or",
+	}
+)
+
+// Health response keys that carry capability information. List values are
+// comma-separated.
+const (
+	capIndexTypesKey      = "index_types"
+	capFilterOperatorsKey = "filter_operators"
+	capEmbeddingModelsKey = "embedding_models"
+	capMaxBatchSizeKey    = "max_batch_size"
+	capMaxBodyBytesKey    = "max_body_bytes"
+	capSparseVectorsKey   = "sparse_vectors"
+)
+
+// SupportsIndexType reports whether indexType is supported.
+func (c *Capabilities) SupportsIndexType(indexType string) bool {
+	return containsFold(c.IndexTypes, indexType)
+}
+
+// SupportsFilterOperator reports whether the metadata filter operator op
+// (for example "$gte") is supported.
+func (c *Capabilities) SupportsFilterOperator(op string) bool {
+	return containsFold(c.FilterOperators, op)
+}
+
+// SupportsEmbeddingModel reports whether the server can embed with model.
+func (c *Capabilities) SupportsEmbeddingModel(model string) bool {
+	return containsFold(c.EmbeddingModels, model)
+}
+
+// GetCapabilities reports which features the connected server supports, so
+// callers can degrade gracefully instead of probing with failing requests.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//
+// Returns:
+//   - *Capabilities: Supported features
+//   - error: Any error encountered contacting the server
+//
+// Example:
+//
+//	caps, err := client.GetCapabilities(ctx)
+//	if err == nil && !caps.SupportsFilterOperator("$gte") {
+//		// fall back to client-side range filtering
+//	}
+func (c *Client) GetCapabilities(ctx context.Context) (*Capabilities, error) {
+	health, err := c.GetHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newCapabilities(health), nil
+}
+
+// newCapabilities builds Capabilities from a health response.
+func newCapabilities(health *HealthResponse) *Capabilities {
+	caps := &Capabilities{
+		ServerVersion:   health.Version,
+		BackendType:     health.BackendType,
+		IndexTypes:      baselineIndexTypes,
+		FilterOperators: baselineFilterOperators,
+	}
+	caps.Reported = caps.ServerVersion != "" || caps.BackendType != ""
+
+	if v := splitList(health.Raw[capIndexTypesKey]); v != nil {
+		caps.IndexTypes = v
+		caps.Reported = true
+	}
+	if v := splitList(health.Raw[capFilterOperatorsKey]); v != nil {
+		caps.FilterOperators = v
+		caps.Reported = true
+	}
+	if v := splitList(health.Raw[capEmbeddingModelsKey]); v != nil {
+		caps.EmbeddingModels = v
+		caps.Reported = true
+	}
+	if n, err := strconv.Atoi(health.Raw[capMaxBatchSizeKey]); err == nil && n > 0 {
+		caps.MaxBatchSize = n
+		caps.Reported = true
+	}
+	if n, err := strconv.ParseInt(health.Raw[capMaxBodyBytesKey], 10, 64); err == nil && n > 0 {
+		caps.MaxBodyBytes = n
+		caps.Reported = true
+	}
+	if b, err := strconv.ParseBool(health.Raw[capSparseVectorsKey]); err == nil {
+		caps.SparseVectors = b
+		caps.Reported = true
+	}
+
+	// Return copies so callers cannot modify the shared baseline.
+	caps.IndexTypes = append([]string(nil), caps.IndexTypes...)
+	caps.FilterOperators = append([]string(nil), caps.FilterOperators...)
+	return caps
+}
+
+// splitList splits a comma-separated list, returning nil if it is empty.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
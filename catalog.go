@@ -0,0 +1,235 @@
+// catalog.go implements index labels and detailed index listings.
+//
+// The service stores no labels or creation times, so they are kept in a
+// client-side catalog persisted in a well-known encrypted index, enabled with
+// WithIndexCatalog. CreateIndex records each new index there and DeleteIndex
+// removes it.
+package cyborgdb
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// IndexCatalogName is the name of the index that stores the catalog.
+const IndexCatalogName = "_cyborgdb_catalog"
+
+// Catalog item metadata keys.
+const (
+	catalogTypeKey      = "index_type"
+	catalogCreatedAtKey = "created_at"
+	catalogLabelsKey    = "labels"
+)
+
+// ErrCatalogDisabled is returned by ListIndexesDetailed when the client was
+// created without WithIndexCatalog.
+var ErrCatalogDisabled = errors.New("index catalog is not enabled; use WithIndexCatalog")
+
+// IndexDetails describes an index in ListIndexesDetailed.
+type IndexDetails struct {
+	// Name is the index name.
+	Name string `json:"name"`
+	// Type is the index type ("ivf", "ivfflat", "ivfpq"), if recorded.
+	Type string `json:"type,omitempty"`
+	// CreatedAt is when the index was created, zero if not recorded.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Labels are the labels attached at creation.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Cataloged is false for indexes created without the catalog (for example
+	// by another SDK); only Name is known for them.
+	Cataloged bool `json:"cataloged"`
+}
+
+// WithIndexCatalog enables the index catalog, protected by key, which records
+// the type, creation time, and labels of every index created by the client.
+// All clients sharing a catalog must use the same key.
+func WithIndexCatalog(key []byte) ClientOption {
+	return func(o *clientOptions) {
+		if len(key) != KeySize {
+			o.setErr(fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(key)))
+			return
+		}
+		o.catalogKey = append([]byte(nil), key...)
+	}
+}
+
+// ListIndexesDetailed returns every index with its catalog details, keeping
+// only those whose labels include every key/value pair in selector (nil or
+// empty matches all). Uncataloged indexes only match an empty selector.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - selector: Required labels, e.g. {"team": "search", "env": "prod"}
+//
+// Returns:
+//   - []IndexDetails: Matching indexes sorted by name
+//   - error: ErrCatalogDisabled without WithIndexCatalog, or any request error
+//
+// Example:
+//
+//	prod, err := client.ListIndexesDetailed(ctx, map[string]string{"env": "prod"})
+func (c *Client) ListIndexesDetailed(ctx context.Context, selector map[string]string) ([]IndexDetails, error) {
+	if c.opts.catalogKey == nil {
+		return nil, ErrCatalogDisabled
+	}
+	names, err := c.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]IndexDetails, len(names))
+	hasCatalog := false
+	for _, name := range names {
+		if name == IndexCatalogName {
+			hasCatalog = true
+			continue
+		}
+		details[name] = IndexDetails{Name: name}
+	}
+
+	if hasCatalog && len(details) > 0 {
+		ids := make([]string, 0, len(details))
+		for name := range details {
+			ids = append(ids, name)
+		}
+		resp, err := catalogHandle(c.internalIndexBase()).Get(ctx, ids, []string{"metadata"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index catalog: %w", err)
+		}
+		for _, item := range resp.Results {
+			if _, ok := details[item.Id]; ok {
+				details[item.Id] = catalogDetails(item.Id, item.Metadata)
+			}
+		}
+	}
+
+	out := make([]IndexDetails, 0, len(details))
+	for _, d := range details {
+		if matchLabels(d.Labels, selector) {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// catalogLabels returns the labels recorded for the named index, or nil if
+// the catalog is disabled, has not been created, or has no entry for it.
+func (c *Client) catalogLabels(ctx context.Context, name string) (map[string]string, error) {
+	if c.opts.catalogKey == nil {
+		return nil, nil
+	}
+	names, err := c.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range names {
+		if n != IndexCatalogName {
+			continue
+		}
+		resp, err := catalogHandle(c.internalIndexBase()).Get(ctx, []string{name}, []string{"metadata"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index catalog: %w", err)
+		}
+		for _, item := range resp.Results {
+			if item.Id == name {
+				return catalogDetails(name, item.Metadata).Labels, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// internalIndexBase returns an index handle template sharing the client's
+// connection and options.
+func (c *Client) internalIndexBase() *EncryptedIndex {
+	return &EncryptedIndex{client: c.internal, opts: c.opts}
+}
+
+// catalogHandle returns a handle for the catalog index using base's client.
+func catalogHandle(base *EncryptedIndex) *EncryptedIndex {
+	return &EncryptedIndex{
+		indexName: IndexCatalogName,
+		indexKey:  hex.EncodeToString(base.opts.catalogKey),
+		client:    base.client,
+		opts:      base.opts,
+	}
+}
+
+// recordInCatalog adds idx to the catalog, creating the catalog on first use.
+func (c *Client) recordInCatalog(ctx context.Context, idx *EncryptedIndex, labels map[string]string) error {
+	names, err := c.ListIndexes(ctx)
+	if err != nil {
+		return err
+	}
+	exists := false
+	for _, name := range names {
+		if name == IndexCatalogName {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		_, err := c.CreateIndex(ctx, &CreateIndexParams{
+			IndexName:   IndexCatalogName,
+			IndexKey:    c.opts.catalogKey,
+			IndexConfig: IndexIVFFlat(int32(len(aliasVector))),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index catalog: %w", err)
+		}
+	}
+
+	metadata := map[string]interface{}{
+		catalogTypeKey:      idx.GetIndexType(),
+		catalogCreatedAtKey: time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(labels) > 0 {
+		m := make(map[string]interface{}, len(labels))
+		for k, v := range labels {
+			m[k] = v
+		}
+		metadata[catalogLabelsKey] = m
+	}
+	return catalogHandle(idx).Upsert(ctx, []VectorItem{{Id: idx.indexName, Vector: aliasVector, Metadata: metadata}})
+}
+
+// removeFromCatalog deletes e's catalog entry, if the catalog is enabled.
+func (e *EncryptedIndex) removeFromCatalog(ctx context.Context) error {
+	if e.opts.catalogKey == nil || isInternalIndex(e.indexName) {
+		return nil
+	}
+	return catalogHandle(e).Delete(ctx, []string{e.indexName})
+}
+
+// catalogDetails builds IndexDetails from a catalog item.
+func catalogDetails(name string, metadata map[string]interface{}) IndexDetails {
+	d := IndexDetails{Name: name, Cataloged: true}
+	d.Type, _ = metadata[catalogTypeKey].(string)
+	if s, ok := metadata[catalogCreatedAtKey].(string); ok {
+		d.CreatedAt, _ = time.Parse(time.RFC3339, s)
+	}
+	if labels, ok := metadata[catalogLabelsKey].(map[string]interface{}); ok {
+		d.Labels = make(map[string]string, len(labels))
+		for k, v := range labels {
+			if s, ok := v.(string); ok {
+				d.Labels[k] = s
+			}
+		}
+	}
+	return d
+}
+
+// matchLabels reports whether labels include every pair in selector.
+func matchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
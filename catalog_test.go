@@ -0,0 +1,46 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestRekeyAndCopyKeepLabels(t *testing.T) {
+	_, client := newFakeService(t, cyborgdb.WithIndexCatalog(testKey(9)))
+	ctx := context.Background()
+	labels := map[string]string{"team": "search", "env": "prod"}
+	if _, err := client.CreateIndex(ctx, &cyborgdb.CreateIndexParams{
+		IndexName:   "docs",
+		IndexKey:    testKey(1),
+		IndexConfig: cyborgdb.IndexIVFFlat(2),
+		Labels:      labels,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.RotateIndexKey(ctx, "docs", testKey(1), testKey(2), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CloneIndex(ctx, "docs", "docs-copy", testKey(2), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RenameIndex(ctx, "docs-copy", "docs-renamed", testKey(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := client.ListIndexesDetailed(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]map[string]string{}
+	for _, d := range details {
+		got[d.Name] = d.Labels
+	}
+	want := map[string]map[string]string{"docs": labels, "docs-renamed": labels}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("labels after rotate, clone, and rename = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+// checkpoint.go implements progress checkpoints for resumable imports.
+package cyborgdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ImportCheckpoint records how far an import has committed.
+type ImportCheckpoint struct {
+	// Row is the last input row such that it and every row before it have
+	// been processed (imported, skipped, or deduplicated).
+	Row int `json:"row"`
+	// Imported is the number of records imported so far, across resumed runs.
+	Imported int `json:"imported"`
+	// UpdatedAt is when the checkpoint was saved.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore persists import checkpoints under a key, so an interrupted
+// import can resume. Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Load returns the checkpoint for key, and false if there is none.
+	Load(ctx context.Context, key string) (ImportCheckpoint, bool, error)
+	// Save replaces the checkpoint for key.
+	Save(ctx context.Context, key string, cp ImportCheckpoint) error
+	// Clear removes the checkpoint for key. Clearing a missing key is not an error.
+	Clear(ctx context.Context, key string) error
+}
+
+// ImportProgress is reported to ImportOptions.OnProgress after each batch.
+type ImportProgress struct {
+	// RowsRead is the number of input rows read so far.
+	RowsRead int
+	// Imported, Skipped, Failed, and Deduplicated are running totals, as in ImportSummary.
+	Imported, Skipped, Failed, Deduplicated int
+	// CommittedRow is the checkpointed row (see ImportCheckpoint.Row).
+	CommittedRow int
+}
+
+// FileCheckpointStore stores checkpoints as JSON files in a directory, one
+// file per key. Files are replaced atomically, so a crash mid-save leaves the
+// previous checkpoint intact.
+type FileCheckpointStore struct {
+	// Dir is the directory holding checkpoint files. It is created on first save.
+	Dir string
+
+	mu sync.Mutex
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore writing to dir.
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{Dir: dir}
+}
+
+func (s *FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.Dir, DeterministicID([]byte(key))+".checkpoint.json")
+}
+
+// Load implements CheckpointStore.
+func (s *FileCheckpointStore) Load(_ context.Context, key string) (ImportCheckpoint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cp ImportCheckpoint
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, false, nil
+	}
+	if err != nil {
+		return cp, false, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return cp, false, fmt.Errorf("corrupt checkpoint %s: %w", s.path(key), err)
+	}
+	return cp, true, nil
+}
+
+// Save implements CheckpointStore.
+func (s *FileCheckpointStore) Save(_ context.Context, key string, cp ImportCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Clear implements CheckpointStore.
+func (s *FileCheckpointStore) Clear(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// importTracker advances the checkpoint watermark as batches finish, which
+// may be out of order when batches are upserted concurrently.
+type importTracker struct {
+	committed int              // last row of the contiguous finished prefix
+	next      int              // sequence number of the next batch to commit
+	pending   map[int]int      // finished batches past the watermark: seq -> last row
+	failed    map[int]struct{} // sequence numbers of failed batches
+}
+
+func newImportTracker(resumeRow int) *importTracker {
+	return &importTracker{committed: resumeRow, pending: make(map[int]int), failed: make(map[int]struct{})}
+}
+
+// finish records that batch seq, ending at lastRow, is done and reports
+// whether the watermark advanced. A failed batch blocks the watermark, so a
+// resumed import retries it.
+func (t *importTracker) finish(seq, lastRow int, ok bool) bool {
+	if !ok {
+		t.failed[seq] = struct{}{}
+	}
+	t.pending[seq] = lastRow
+	advanced := false
+	for {
+		row, done := t.pending[t.next]
+		if !done {
+			return advanced
+		}
+		if _, bad := t.failed[t.next]; bad {
+			return advanced
+		}
+		delete(t.pending, t.next)
+		t.committed = row
+		t.next++
+		advanced = true
+	}
+}
@@ -0,0 +1,197 @@
+// circuitbreaker.go implements an optional circuit breaker for requests.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitFailureThreshold is the number of consecutive failures that opens the circuit.
+	DefaultCircuitFailureThreshold = 5
+	// DefaultCircuitOpenTimeout is how long the circuit stays open before a probe is allowed.
+	DefaultCircuitOpenTimeout = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit is open.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through to test recovery.
+	CircuitHalfOpen
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures WithCircuitBreaker. Zero values fall back to defaults.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures (network errors
+	// and 5xx responses) that opens the circuit. Default: 5.
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open before a single probe
+	// request is allowed through. Default: 30s.
+	OpenTimeout time.Duration
+
+	// OnStateChange, if set, is called on every state transition, e.g. to
+	// export metrics. It must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen after
+// repeated failures instead of sending more requests to a degraded endpoint.
+//
+// After FailureThreshold consecutive failures the circuit opens. Once
+// OpenTimeout has elapsed, one probe request is let through: success closes
+// the circuit, failure re-opens it. Requests cancelled by the caller are not
+// counted as failures.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) ClientOption {
+	return func(o *clientOptions) { o.circuitBreaker = &cfg }
+}
+
+// CircuitBreakerState returns the current circuit state, or CircuitClosed if
+// the client has no circuit breaker.
+func (c *Client) CircuitBreakerState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.currentState()
+}
+
+// circuitBreaker tracks consecutive failures and the circuit state.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	cfg      CircuitBreakerConfig
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker returns a closed breaker with defaults applied to cfg.
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultCircuitFailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultCircuitOpenTimeout
+	}
+	return &circuitBreaker{cfg: cfg}
+}
+
+// currentState returns the state, accounting for an elapsed open timeout.
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may proceed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		return true
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+	}
+
+	if b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.setState(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.openedAt = time.Now()
+		b.setState(CircuitOpen)
+	}
+}
+
+// setState transitions to s, notifying OnStateChange. b.mu must be held.
+func (b *circuitBreaker) setState(s CircuitState) {
+	if b.state == s {
+		return
+	}
+	from := b.state
+	b.state = s
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, s)
+	}
+}
+
+// circuitBreakerTransport rejects requests while the circuit is open.
+type circuitBreakerTransport struct {
+	next    http.RoundTripper
+	breaker *circuitBreaker
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.breaker.allow() {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil && errors.Is(req.Context().Err(), context.Canceled):
+		// The caller gave up; this says nothing about the endpoint. Release
+		// the probe slot without changing the state.
+		t.breaker.mu.Lock()
+		t.breaker.probing = false
+		t.breaker.mu.Unlock()
+	case err != nil:
+		t.breaker.record(false)
+	default:
+		t.breaker.record(resp.StatusCode < 500)
+	}
+	return resp, err
+}
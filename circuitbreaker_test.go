@@ -0,0 +1,98 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var healthy, calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"detail":"unavailable"}`))
+			return
+		}
+		w.Write([]byte(`{"indexes":[]}`))
+	}))
+	defer srv.Close()
+
+	var transitions []string
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithCircuitBreaker(cyborgdb.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+		OnStateChange: func(from, to cyborgdb.CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ListIndexes(ctx); err == nil || errors.Is(err, cyborgdb.ErrCircuitOpen) {
+			t.Fatalf("call %d: err = %v, want a service error", i, err)
+		}
+	}
+	if s := client.CircuitBreakerState(); s != cyborgdb.CircuitOpen {
+		t.Fatalf("state after 2 failures = %v, want open", s)
+	}
+	if _, err := client.ListIndexes(ctx); !errors.Is(err, cyborgdb.ErrCircuitOpen) {
+		t.Fatalf("err while open = %v, want ErrCircuitOpen", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("service called %d times, want 2; the open circuit must not send requests", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if s := client.CircuitBreakerState(); s != cyborgdb.CircuitHalfOpen {
+		t.Fatalf("state after the open timeout = %v, want half-open", s)
+	}
+	atomic.StoreInt32(&healthy, 1)
+	if _, err := client.ListIndexes(ctx); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if s := client.CircuitBreakerState(); s != cyborgdb.CircuitClosed {
+		t.Errorf("state after a successful probe = %v, want closed", s)
+	}
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transitions = %v, want %v", transitions, want)
+			break
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail":"not found"}`))
+	}))
+	defer srv.Close()
+
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithCircuitBreaker(cyborgdb.CircuitBreakerConfig{FailureThreshold: 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		client.ListIndexes(context.Background())
+	}
+	if s := client.CircuitBreakerState(); s != cyborgdb.CircuitClosed {
+		t.Errorf("state after 4xx responses = %v, want closed", s)
+	}
+}
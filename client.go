@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/cyborginc/cyborgdb-go/internal"
 )
@@ -37,6 +38,10 @@ var (
 // All operations maintain end-to-end encryption for vector data.
 type Client struct {
 	internal *internal.Client // Embedded internal client
+	opts     *clientOptions   // Options shared with every index handle
+	breaker  *circuitBreaker  // Optional circuit breaker, nil if disabled
+
+	credentials *swappableCredentials // Current API credentials
 }
 
 // GenerateKey returns a cryptographically secure 32-byte key for use with CyborgDB indexes.
@@ -67,23 +72,49 @@ func GenerateKey() ([]byte, error) {
 //	NewClient(url, apiKey)        // auto-detect verifySSL
 //	NewClient(url, apiKey, false) // force off
 //	NewClient(url, apiKey, true)  // force on
+//
+// Use NewClientWithOptions for timeouts and other settings.
 func NewClient(baseURL, apiKey string, verifySSL ...bool) (*Client, error) {
 	// Explicit override wins.
 	if len(verifySSL) > 0 {
-		v := verifySSL[0]
-		internalClient, err := internal.NewClient(baseURL, apiKey, v)
-		if err != nil {
-			return nil, err
-		}
-		return &Client{internal: internalClient}, nil
+		return NewClientWithOptions(baseURL, apiKey, WithVerifySSL(verifySSL[0]))
+	}
+	return NewClientWithOptions(baseURL, apiKey)
+}
+
+// NewClientWithOptions constructs a new CyborgDB client configured by opts.
+//
+// SSL verification is auto-detected as described on NewClient unless
+// WithVerifySSL is given. Default per-operation timeouts apply to calls whose
+// context has no deadline; see WithQueryTimeout, WithUpsertTimeout,
+// WithTrainTimeout, and WithOperationTimeout. apiKey may be empty when
+// WithCredentialsProvider supplies the credentials. Connection pooling and HTTP/2
+// are tuned with options such as WithMaxIdleConnsPerHost and WithHTTP2.
+//
+// Usage:
+//
+//	client, err := NewClientWithOptions(url, apiKey,
+//		WithQueryTimeout(5*time.Second),
+//		WithTrainTimeout(2*time.Hour),
+//	)
+func NewClientWithOptions(baseURL, apiKey string, opts ...ClientOption) (*Client, error) {
+	options := defaultClientOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.err != nil {
+		return nil, options.err
 	}
 
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
 	}
+
 	v := true
-	if u.Scheme == "http" {
+	if options.verifySSL != nil {
+		v = *options.verifySSL
+	} else if u.Scheme == "http" {
 		v = false
 	} else {
 		host := u.Hostname()
@@ -92,11 +123,29 @@ func NewClient(baseURL, apiKey string, verifySSL ...bool) (*Client, error) {
 		}
 	}
 
-	internalClient, err := internal.NewClient(baseURL, apiKey, v)
+	// Route every request through a swappable provider so SetAPIKey and
+	// UpdateCredentials take effect without rebuilding the client.
+	credentials := &swappableCredentials{provider: options.credentials}
+	if credentials.provider == nil {
+		credentials.provider = StaticCredentials(apiKey)
+	}
+	options.credentials = credentials
+
+	httpClient, err := newHTTPClient(options, v)
+	if err != nil {
+		return nil, err
+	}
+	var breaker *circuitBreaker
+	if options.circuitBreaker != nil {
+		breaker = newCircuitBreaker(*options.circuitBreaker)
+		httpClient.Transport = &circuitBreakerTransport{next: httpClient.Transport, breaker: breaker}
+	}
+
+	internalClient, err := internal.NewClientWithHTTPClient(baseURL, apiKey, httpClient)
 	if err != nil {
 		return nil, err
 	}
-	return &Client{internal: internalClient}, nil
+	return &Client{internal: internalClient, opts: options, breaker: breaker, credentials: credentials}, nil
 }
 
 // ListIndexes returns the names of all encrypted indexes in your project.
@@ -108,6 +157,8 @@ func NewClient(baseURL, apiKey string, verifySSL ...bool) (*Client, error) {
 //   - []string: Index names (empty slice if none)
 //   - error: Any error encountered
 func (c *Client) ListIndexes(ctx context.Context) ([]string, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.opts.operationTimeout)
+	defer cancel()
 	return c.internal.ListIndexes(ctx)
 }
 
@@ -120,10 +171,12 @@ func (c *Client) ListIndexes(ctx context.Context) ([]string, error) {
 //   - ctx: Context for cancellation/timeouts
 //   - params: Complete payload containing:
 //   - IndexName (required): unique index name
-//   - IndexKey  (required): 32-byte encryption key
+//   - IndexKey  (required unless KeyProvider is set): 32-byte encryption key
+//   - KeyProvider (optional): resolves the key at call time when IndexKey is empty
 //   - IndexConfig (optional): index configuration (IndexIVF, IndexIVFFlat, or IndexIVFPQ)
 //   - Metric (optional): distance metric (e.g., "euclidean", "cosine")
 //   - EmbeddingModel (optional): embedding model name to associate
+//   - Labels (optional): labels recorded in the index catalog
 //
 // Returns:
 //   - *EncryptedIndex: Handle for vector operations
@@ -135,13 +188,24 @@ func (c *Client) CreateIndex(
 	ctx context.Context,
 	params *CreateIndexParams,
 ) (*EncryptedIndex, error) {
+	indexKey := params.IndexKey
+	if len(indexKey) == 0 && params.KeyProvider != nil {
+		key, err := resolveKey(ctx, params.KeyProvider, params.IndexName)
+		if err != nil {
+			return nil, err
+		}
+		// The resolved copy is owned by the SDK; wipe it once encoded
+		defer Zeroize(key)
+		indexKey = key
+	}
+
 	// Validate the key length
-	if len(params.IndexKey) != KeySize {
-		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(params.IndexKey))
+	if len(indexKey) != KeySize {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(indexKey))
 	}
 
 	// Convert bytes to hex string
-	keyHex := fmt.Sprintf("%x", params.IndexKey)
+	keyHex := fmt.Sprintf("%x", indexKey)
 
 	// Convert CreateIndexParams to internal.CreateIndexRequest
 	var indexConfig internal.IndexConfig
@@ -167,9 +231,13 @@ func (c *Client) CreateIndex(
 	}
 
 	// Call internal CreateIndex
+	ctx, cancel := withDefaultTimeout(ctx, c.opts.operationTimeout)
+	defer cancel()
+	start := time.Now()
 	_, _, err := c.internal.APIClient.DefaultAPI.CreateIndexV1IndexesCreatePost(ctx).
 		CreateIndexRequest(req).
 		Execute()
+	c.opts.audit(ctx, AuditCreateIndex, params.IndexName, 0, start, err)
 	if err != nil {
 		return nil, err
 	}
@@ -179,8 +247,14 @@ func (c *Client) CreateIndex(
 		indexName: params.IndexName,
 		indexKey:  keyHex,
 		client:    c.internal,
+		opts:      c.opts,
 		config:    &indexConfig,
 		trained:   false,
+		embedder:  params.Embedder,
+	}
+
+	if params.Metric != nil {
+		idx.metric = *params.Metric
 	}
 
 	// Set index type if available
@@ -192,13 +266,20 @@ func (c *Client) CreateIndex(
 		idx.indexType = *indexConfig.IndexIVFPQModel.Type
 	}
 
+	if c.opts.catalogKey != nil && params.IndexName != IndexCatalogName {
+		if err := c.recordInCatalog(ctx, idx, params.Labels); err != nil {
+			return idx, fmt.Errorf("index created but not recorded in catalog: %w", err)
+		}
+	}
+
 	return idx, nil
 }
 
 // LoadIndex loads an existing encrypted index by name and key.
 //
-// The provided key must match the one used at creation time. Configuration and
-// metadata are fetched from the server.
+// The provided key must match the one used at creation time. The index type,
+// full configuration (dimension, n_lists, PQ parameters), and trained status
+// are fetched from the server via the describe endpoint.
 //
 // Parameters:
 //   - ctx: Context for cancellation/timeouts
@@ -216,46 +297,17 @@ func (c *Client) LoadIndex(ctx context.Context, indexName string, indexKey []byt
 
 	keyHex := fmt.Sprintf("%x", indexKey)
 
-	describeReq := internal.IndexOperationRequest{
-		IndexName: indexName,
-		IndexKey:  keyHex,
-	}
-
-	indexInfo, _, err := c.internal.APIClient.DefaultAPI.GetIndexInfoV1IndexesDescribePost(ctx).
-		IndexOperationRequest(describeReq).
-		Execute()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get index info: %w", err)
+	idx := &EncryptedIndex{
+		indexName: indexName,
+		indexKey:  keyHex,
+		client:    c.internal,
+		opts:      c.opts,
 	}
 
-	// Convert the map[string]interface{} config to internal.IndexConfig if needed
-	var indexConfig *internal.IndexConfig
-	if len(indexInfo.IndexConfig) > 0 {
-		// For now, leave config as nil since converting from map to IndexConfig is complex
-		// The EncryptedIndex will work without the detailed config
-		indexConfig = nil
+	// Populate type, full configuration, and trained state from the server
+	if err := idx.RefreshInfo(ctx); err != nil {
+		return nil, err
 	}
 
-	return &EncryptedIndex{
-		indexName: indexInfo.IndexName,
-		indexKey:  keyHex,
-		indexType: indexInfo.IndexType,
-		config:    indexConfig,
-		client:    c.internal,
-		trained:   indexInfo.IsTrained,
-	}, nil
-}
-
-// GetHealth checks the health status of the CyborgDB service.
-//
-// Useful for readiness/liveness checks and connectivity diagnostics.
-//
-// Parameters:
-//   - ctx: Context for cancellation/timeouts
-//
-// Returns:
-//   - map[string]string: Health status from the server
-//   - error: Any error encountered
-func (c *Client) GetHealth(ctx context.Context) (map[string]string, error) {
-	return c.internal.GetHealth(ctx)
+	return idx, nil
 }
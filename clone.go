@@ -0,0 +1,158 @@
+// clone.go implements index clone and rename.
+//
+// The service has no clone or rename endpoint, so both stream the vectors
+// from the source index into a newly created one, as RotateIndexKey does.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CloneIndex copies an index into a new index named dst, optionally under a
+// different key, for blue/green rebuilds and environment copies. The source
+// is left untouched. The configuration, metric, schema, and catalog labels
+// are preserved, and the clone is trained if the source was.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - src: Name of the index to copy
+//   - dst: Name of the new index; it must not exist
+//   - key: 32-byte key of the source index
+//   - newKey: 32-byte key for the new index, or nil to reuse key
+//
+// Returns:
+//   - *EncryptedIndex: Handle for the new index
+//   - error: ErrHashedFieldRekey for a new key under WithSearchableHashes,
+//     or any other error encountered; a partially copied clone is deleted
+//
+// Example:
+//
+//	green, err := client.CloneIndex(ctx, "docs-blue", "docs-green", key, nil)
+func (c *Client) CloneIndex(ctx context.Context, src, dst string, key, newKey []byte) (*EncryptedIndex, error) {
+	if newKey == nil {
+		newKey = key
+	}
+	if len(newKey) != KeySize {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(newKey))
+	}
+	if err := c.opts.checkRekey(key, newKey); err != nil {
+		return nil, err
+	}
+
+	source, err := c.LoadIndex(ctx, src, key)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := source.GetSchema(ctx)
+	if err != nil && !errors.Is(err, ErrNoSchema) {
+		return nil, err
+	}
+	labels, err := c.catalogLabels(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &CreateIndexParams{
+		IndexName:   dst,
+		IndexKey:    newKey,
+		IndexConfig: indexModelFromConfig(source.cachedConfig()),
+		Labels:      labels,
+	}
+	if metric := source.GetMetric(); metric != "" {
+		params.Metric = &metric
+	}
+	clone, err := c.CreateIndex(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index %q: %w", dst, err)
+	}
+
+	if err := copyVectors(ctx, source, clone, "clone", nil); err != nil {
+		_ = clone.DeleteIndex(ctx)
+		return nil, fmt.Errorf("failed to copy %q into %q: %w", src, dst, err)
+	}
+	if schema != nil {
+		if err := clone.SetSchema(ctx, schema); err != nil {
+			_ = clone.DeleteIndex(ctx)
+			return nil, fmt.Errorf("failed to copy the schema of %q into %q: %w", src, dst, err)
+		}
+	}
+
+	if source.IsTrained() {
+		if err := clone.Train(ctx, TrainParams{}); err != nil {
+			return clone, fmt.Errorf("clone succeeded but training failed: %w", err)
+		}
+	}
+	return clone, nil
+}
+
+// CloneIndexWithKeyProvider is CloneIndex with the keys resolved through
+// providers, e.g. SecretKeys. The resolved copies are wiped when the clone
+// returns.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - src: Name of the index to copy
+//   - dst: Name of the new index; it must not exist
+//   - key: Source of the source index's key
+//   - newKey: Source of the new index's key, or nil to reuse key
+//
+// Returns:
+//   - *EncryptedIndex: Handle for the new index
+//   - error: Any error resolving the keys or cloning the index
+func (c *Client) CloneIndexWithKeyProvider(ctx context.Context, src, dst string, key, newKey KeyProvider) (*EncryptedIndex, error) {
+	srcKey, err := resolveKey(ctx, key, src)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(srcKey)
+	var dstKey []byte
+	if newKey != nil {
+		if dstKey, err = resolveKey(ctx, newKey, dst); err != nil {
+			return nil, err
+		}
+		defer Zeroize(dstKey)
+	}
+	return c.CloneIndex(ctx, src, dst, srcKey, dstKey)
+}
+
+// RenameIndex renames an index by cloning it under the new name with the
+// same key and then deleting the old index.
+//
+// The rename is not atomic: both names exist until the copy completes, and
+// writes to the old index during the copy may be lost. Pause writers, or use
+// aliases for zero-downtime switches. If the old index was trained and
+// training the copy fails, the copy is deleted and the old index kept, so a
+// failed rename never leaves an untrained index in its place.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - oldName: Current index name
+//   - newName: New index name; it must not exist
+//   - key: 32-byte index key
+//
+// Returns:
+//   - *EncryptedIndex: Handle for the index under its new name
+//   - error: Any error encountered; the old index is kept if the copy or its
+//     training fails
+func (c *Client) RenameIndex(ctx context.Context, oldName, newName string, key []byte) (*EncryptedIndex, error) {
+	renamed, err := c.CloneIndex(ctx, oldName, newName, key, nil)
+	if err != nil {
+		if renamed != nil {
+			if delErr := renamed.DeleteIndex(ctx); delErr != nil {
+				return nil, fmt.Errorf("rename failed (%v) and the copy %q was not deleted: %w", err, newName, delErr)
+			}
+		}
+		return nil, err
+	}
+
+	old, err := c.LoadIndex(ctx, oldName, key)
+	if err != nil {
+		return renamed, fmt.Errorf("rename copied the index but failed to load %q for deletion: %w", oldName, err)
+	}
+	if err := old.DeleteIndex(ctx); err != nil {
+		return renamed, fmt.Errorf("rename copied the index but failed to delete %q: %w", oldName, err)
+	}
+	return renamed, nil
+}
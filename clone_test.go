@@ -0,0 +1,36 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestRenameIndexKeepsOldIndexWhenTrainingFails(t *testing.T) {
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: "a", Vector: []float32{1, 0}}}); err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	fake.indexes["docs"].trained = true
+	fake.mu.Unlock()
+
+	// The fake has no train endpoint, so training the copy fails.
+	renamed, err := client.RenameIndex(ctx, "docs", "docs-renamed", testKey(1))
+	if err == nil {
+		t.Fatal("RenameIndex succeeded although training failed")
+	}
+	if renamed != nil {
+		t.Errorf("RenameIndex returned a handle for %q after failing", renamed.GetIndexName())
+	}
+	if got := fake.indexNames(); !reflect.DeepEqual(got, []string{"docs"}) {
+		t.Errorf("indexes = %v, want only the old index", got)
+	}
+	if fake.item("docs", "a") == nil {
+		t.Error("old index lost its items")
+	}
+}
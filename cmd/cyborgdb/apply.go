@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func runApply(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	file := fs.String("f", "", "YAML or JSON file of index specs (required)")
+	keyDir := fs.String("key-dir", "", "directory holding <index>.key files (hex or 32 raw bytes); defaults to CYBORGDB_INDEX_KEY for every index")
+	prune := fs.Bool("prune", false, "delete indexes that are not in the file")
+	allowEmptyPrune := fs.Bool("allow-empty-prune", false, "with -prune and a file listing no indexes, delete every index")
+	recreate := fs.Bool("recreate", false, "delete and recreate drifted indexes (discards their vectors)")
+	dryRun := fs.Bool("dry-run", false, "print the plan without changing anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-f is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	specs, err := parseIndexSpecs(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *file, err)
+	}
+
+	client, err := conn.client()
+	if err != nil {
+		return err
+	}
+	result, applyErr := client.Apply(ctx, specs, cyborgdb.ApplyOptions{
+		Keys:            keyDirProvider(*keyDir),
+		Prune:           *prune,
+		AllowEmptyPrune: *allowEmptyPrune,
+		Recreate:        *recreate,
+		DryRun:          *dryRun,
+	})
+	if result != nil {
+		type row struct {
+			Index  string `json:"index"`
+			Op     string `json:"op"`
+			Reason string `json:"reason,omitempty"`
+			Error  string `json:"error,omitempty"`
+		}
+		rows := make([]row, 0, len(result.Actions))
+		for _, a := range result.Actions {
+			r := row{Index: a.Index, Op: string(a.Op), Reason: a.Reason}
+			if a.Err != nil {
+				r.Error = a.Err.Error()
+			}
+			rows = append(rows, r)
+		}
+		if err := printJSON(out, map[string]interface{}{"dry_run": *dryRun, "actions": rows}); err != nil {
+			return err
+		}
+	}
+	return applyErr
+}
+
+// parseIndexSpecs decodes index specs from JSON or YAML. Unknown keys are
+// rejected, so a misspelled "indexes" cannot pass for an empty file.
+func parseIndexSpecs(data []byte) (cyborgdb.IndexSpecs, error) {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+		return decodeIndexSpecs(data)
+	}
+	doc, err := parseYAML(data)
+	if err != nil {
+		return cyborgdb.IndexSpecs{}, err
+	}
+	// Round-trip through JSON to reuse the IndexSpecs field tags.
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return cyborgdb.IndexSpecs{}, err
+	}
+	return decodeIndexSpecs(raw)
+}
+
+// decodeIndexSpecs strictly decodes JSON index specs.
+func decodeIndexSpecs(data []byte) (cyborgdb.IndexSpecs, error) {
+	var specs cyborgdb.IndexSpecs
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&specs); err != nil {
+		return specs, err
+	}
+	if dec.More() {
+		return specs, errors.New("unexpected data after index specs")
+	}
+	return specs, nil
+}
+
+// keyDirProvider resolves index keys from <dir>/<index>.key, or from
+// CYBORGDB_INDEX_KEY when dir is empty.
+type keyDirProvider string
+
+// IndexKey implements cyborgdb.KeyProvider.
+func (dir keyDirProvider) IndexKey(_ context.Context, indexName string) ([]byte, error) {
+	if dir == "" {
+		if env := os.Getenv("CYBORGDB_INDEX_KEY"); env != "" {
+			return decodeHexKey(env)
+		}
+		return nil, errors.New("index key required: set -key-dir or CYBORGDB_INDEX_KEY")
+	}
+	data, err := os.ReadFile(filepath.Join(string(dir), indexName+".key"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if len(data) == cyborgdb.KeySize {
+		return data, nil
+	}
+	return decodeHexKey(string(data))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestParseIndexSpecsRejectsUnknownKeys(t *testing.T) {
+	for name, doc := range map[string]string{
+		"yaml top level": "indexs:\n  - name: docs\n",
+		"yaml spec":      "indexes:\n  - name: docs\n    dimensions: 4\n",
+		"json top level": `{"indexs": [{"name": "docs"}]}`,
+		"json spec":      `{"indexes": [{"name": "docs", "metrik": "cosine"}]}`,
+	} {
+		if _, err := parseIndexSpecs([]byte(doc)); err == nil {
+			t.Errorf("%s: parsed %q without error", name, doc)
+		}
+	}
+}
+
+func TestParseIndexSpecs(t *testing.T) {
+	specs, err := parseIndexSpecs([]byte(`
+indexes:
+  - name: docs
+    type: ivfpq
+    dimension: 768
+    pq_dim: 64
+    pq_bits: 8
+    labels:
+      env: prod
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs.Indexes) != 1 {
+		t.Fatalf("got %d specs, want 1", len(specs.Indexes))
+	}
+	got := specs.Indexes[0]
+	if got.Name != "docs" || got.Type != "ivfpq" || got.Dimension != 768 || got.PQDim != 64 || got.PQBits != 8 {
+		t.Errorf("spec = %+v", got)
+	}
+	if got.Labels["env"] != "prod" {
+		t.Errorf("labels = %v", got.Labels)
+	}
+}
+
+func TestApplyRefusesEmptyPrune(t *testing.T) {
+	srv, calls := fakeService(t, http.StatusOK)
+	file := filepath.Join(t.TempDir(), "indexes.json")
+	if err := os.WriteFile(file, []byte(`{"indexes": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := run(context.Background(), []string{"-url", srv.URL, "apply", "-f", file, "-prune"}, &out)
+	if !errors.Is(err, cyborgdb.ErrInvalidIndexSpec) {
+		t.Fatalf("apply error = %v, want ErrInvalidIndexSpec", err)
+	}
+	if n := atomic.LoadInt32(calls); n != 0 {
+		t.Errorf("service called %d times, want 0", n)
+	}
+}
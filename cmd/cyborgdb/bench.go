@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// benchConfig holds the workload settings for the bench command.
+type benchConfig struct {
+	dimension   int
+	numVectors  int
+	numQueries  int
+	batchSize   int
+	topK        int
+	nProbes     int
+	concurrency int
+	train       bool
+	keep        bool
+	seed        int64
+}
+
+// latencyStats summarizes a set of request latencies.
+type latencyStats struct {
+	Requests   int     `json:"requests"`
+	Throughput float64 `json:"throughput_per_sec"`
+	P50Ms      float64 `json:"p50_ms"`
+	P90Ms      float64 `json:"p90_ms"`
+	P99Ms      float64 `json:"p99_ms"`
+	MaxMs      float64 `json:"max_ms"`
+}
+
+// benchReport is the JSON output of the bench command.
+type benchReport struct {
+	Index      string       `json:"index"`
+	Dimension  int          `json:"dimension"`
+	NumVectors int          `json:"num_vectors"`
+	NumQueries int          `json:"num_queries"`
+	TopK       int          `json:"top_k"`
+	NProbes    int          `json:"n_probes,omitempty"`
+	Upsert     latencyStats `json:"upsert"`
+	TrainSecs  float64      `json:"train_secs,omitempty"`
+	Query      latencyStats `json:"query"`
+	Recall     float64      `json:"recall"`
+}
+
+func runBench(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	var cfg benchConfig
+	fs.IntVar(&cfg.dimension, "dimension", 128, "vector dimension")
+	fs.IntVar(&cfg.numVectors, "vectors", 10000, "number of vectors to upsert")
+	fs.IntVar(&cfg.numQueries, "queries", 100, "number of queries to run")
+	fs.IntVar(&cfg.batchSize, "batch-size", 500, "vectors per upsert request")
+	fs.IntVar(&cfg.topK, "top-k", 10, "results per query")
+	fs.IntVar(&cfg.nProbes, "n-probes", 0, "IVF lists to probe (0 uses the index default)")
+	fs.IntVar(&cfg.concurrency, "concurrency", 4, "concurrent requests")
+	fs.BoolVar(&cfg.train, "train", true, "train the index before querying")
+	fs.BoolVar(&cfg.keep, "keep", false, "keep the benchmark index instead of deleting it")
+	fs.Int64Var(&cfg.seed, "seed", 1, "random seed for the synthetic dataset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"dimension", cfg.dimension},
+		{"vectors", cfg.numVectors},
+		{"queries", cfg.numQueries},
+		{"batch-size", cfg.batchSize},
+		{"top-k", cfg.topK},
+	} {
+		if f.value <= 0 {
+			return fmt.Errorf("-%s must be positive, got %d", f.name, f.value)
+		}
+	}
+	if cfg.nProbes < 0 {
+		return fmt.Errorf("-n-probes must not be negative, got %d", cfg.nProbes)
+	}
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+
+	client, err := conn.client()
+	if err != nil {
+		return err
+	}
+	key, err := cyborgdb.GenerateKey()
+	if err != nil {
+		return err
+	}
+	defer cyborgdb.Zeroize(key)
+
+	name := fmt.Sprintf("bench_%d", time.Now().UnixNano())
+	index, err := client.CreateIndex(ctx, &cyborgdb.CreateIndexParams{
+		IndexName:   name,
+		IndexKey:    key,
+		IndexConfig: cyborgdb.IndexIVFFlat(int32(cfg.dimension)),
+	})
+	if err != nil {
+		return err
+	}
+	if !cfg.keep {
+		defer func() { _ = index.DeleteIndex(context.Background()) }()
+	}
+
+	rng := rand.New(rand.NewSource(cfg.seed))
+	vectors := randomVectors(rng, cfg.numVectors, cfg.dimension)
+	queries := randomVectors(rng, cfg.numQueries, cfg.dimension)
+
+	report := benchReport{
+		Index:      name,
+		Dimension:  cfg.dimension,
+		NumVectors: cfg.numVectors,
+		NumQueries: cfg.numQueries,
+		TopK:       cfg.topK,
+		NProbes:    cfg.nProbes,
+	}
+
+	// Upsert phase
+	numBatches := (cfg.numVectors + cfg.batchSize - 1) / cfg.batchSize
+	upsertLatencies, upsertElapsed, err := runConcurrent(ctx, numBatches, cfg.concurrency, func(ctx context.Context, b int) error {
+		start := b * cfg.batchSize
+		end := start + cfg.batchSize
+		if end > cfg.numVectors {
+			end = cfg.numVectors
+		}
+		items := make([]cyborgdb.VectorItem, 0, end-start)
+		for i := start; i < end; i++ {
+			items = append(items, cyborgdb.VectorItem{Id: strconv.Itoa(i), Vector: vectors[i]})
+		}
+		return index.Upsert(ctx, items)
+	})
+	if err != nil {
+		return fmt.Errorf("upsert phase failed: %w", err)
+	}
+	report.Upsert = summarizeLatencies(upsertLatencies, upsertElapsed)
+
+	// Train phase
+	if cfg.train {
+		start := time.Now()
+		if err := index.Train(ctx, cyborgdb.TrainParams{}); err != nil {
+			return fmt.Errorf("train phase failed: %w", err)
+		}
+		report.TrainSecs = time.Since(start).Seconds()
+	}
+
+	// Query phase
+	results := make([][]cyborgdb.QueryResult, cfg.numQueries)
+	opts := []cyborgdb.QueryOption{cyborgdb.WithTopK(int32(cfg.topK))}
+	if cfg.nProbes > 0 {
+		opts = append(opts, cyborgdb.WithNProbes(int32(cfg.nProbes)))
+	}
+	queryLatencies, queryElapsed, err := runConcurrent(ctx, cfg.numQueries, cfg.concurrency, func(ctx context.Context, q int) error {
+		res, err := index.QueryOne(ctx, queries[q], opts...)
+		results[q] = res
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("query phase failed: %w", err)
+	}
+	report.Query = summarizeLatencies(queryLatencies, queryElapsed)
+
+	report.Recall = recallAtK(vectors, queries, results, cfg.topK)
+	return printJSON(out, report)
+}
+
+// runConcurrent runs fn for tasks 0..n-1 with at most concurrency in flight,
+// returning each task's latency and the total wall time.
+func runConcurrent(ctx context.Context, n, concurrency int, fn func(context.Context, int) error) ([]time.Duration, time.Duration, error) {
+	latencies := make([]time.Duration, n)
+	tasks := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range tasks {
+				begin := time.Now()
+				if err := fn(ctx, t); err != nil {
+					errOnce.Do(func() { firstErr = err; cancel() })
+					continue
+				}
+				latencies[t] = time.Since(begin)
+			}
+		}()
+	}
+	for t := 0; t < n && ctx.Err() == nil; t++ {
+		tasks <- t
+	}
+	close(tasks)
+	wg.Wait()
+	return latencies, time.Since(start), firstErr
+}
+
+func summarizeLatencies(latencies []time.Duration, elapsed time.Duration) latencyStats {
+	if len(latencies) == 0 {
+		return latencyStats{}
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	pct := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return float64(sorted[i]) / float64(time.Millisecond)
+	}
+	stats := latencyStats{
+		Requests: len(sorted),
+		P50Ms:    pct(0.50),
+		P90Ms:    pct(0.90),
+		P99Ms:    pct(0.99),
+		MaxMs:    float64(sorted[len(sorted)-1]) / float64(time.Millisecond),
+	}
+	// A phase can finish within the clock resolution; report no throughput
+	// rather than +Inf, which JSON cannot encode.
+	if elapsed > 0 {
+		stats.Throughput = float64(len(sorted)) / elapsed.Seconds()
+	}
+	return stats
+}
+
+func randomVectors(rng *rand.Rand, n, dim int) [][]float32 {
+	out := make([][]float32, n)
+	for i := range out {
+		v := make([]float32, dim)
+		for j := range v {
+			v[j] = rng.Float32()
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// recallAtK compares results against brute-force euclidean ground truth.
+func recallAtK(vectors, queries [][]float32, results [][]cyborgdb.QueryResult, k int) float64 {
+	if len(queries) == 0 || k <= 0 {
+		return 0
+	}
+	type scored struct {
+		id   int
+		dist float32
+	}
+	var total float64
+	for q, query := range queries {
+		all := make([]scored, len(vectors))
+		for i, v := range vectors {
+			var d float32
+			for j := range v {
+				diff := v[j] - query[j]
+				d += diff * diff
+			}
+			all[i] = scored{id: i, dist: d}
+		}
+		sort.Slice(all, func(a, b int) bool { return all[a].dist < all[b].dist })
+
+		limit := k
+		if limit > len(all) {
+			limit = len(all)
+		}
+		truth := make(map[string]struct{}, limit)
+		for _, s := range all[:limit] {
+			truth[strconv.Itoa(s.id)] = struct{}{}
+		}
+		hits := 0
+		for _, r := range results[q] {
+			if _, ok := truth[r.ID]; ok {
+				hits++
+			}
+		}
+		if limit > 0 {
+			total += float64(hits) / float64(limit)
+		}
+	}
+	return total / float64(len(queries))
+}
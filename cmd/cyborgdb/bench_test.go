@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBenchRejectsNonPositiveSizes(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"success","message":"ok"}`))
+	}))
+	defer srv.Close()
+
+	for _, flag := range []string{"-batch-size=0", "-vectors=0", "-queries=-1", "-dimension=0", "-top-k=0", "-n-probes=-1"} {
+		var out bytes.Buffer
+		err := run(context.Background(), []string{"-url", srv.URL, "bench", flag}, &out)
+		name := strings.SplitN(strings.TrimPrefix(flag, "-"), "=", 2)[0]
+		if err == nil || !strings.Contains(err.Error(), "-"+name) {
+			t.Errorf("bench %s: err = %v, want an error naming -%s", flag, err, name)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("service called %d times, want 0", n)
+	}
+}
+
+func TestSummarizeLatenciesZero(t *testing.T) {
+	if got := summarizeLatencies(nil, 0); got != (latencyStats{}) {
+		t.Errorf("summary of no requests = %+v, want zero", got)
+	}
+	got := summarizeLatencies([]time.Duration{time.Millisecond, 3 * time.Millisecond}, 0)
+	if got.Requests != 2 || got.Throughput != 0 || got.MaxMs != 3 {
+		t.Errorf("summary with zero elapsed time = %+v, want 2 requests, no throughput, max 3ms", got)
+	}
+	if _, err := json.Marshal(benchReport{Upsert: got, Query: got}); err != nil {
+		t.Errorf("report with zero elapsed time does not encode: %v", err)
+	}
+	if r := recallAtK(nil, nil, nil, 10); r != 0 {
+		t.Errorf("recall with no queries = %v, want 0", r)
+	}
+}
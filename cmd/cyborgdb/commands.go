@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func runHealth(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	client, err := conn.client()
+	if err != nil {
+		return err
+	}
+	health, err := client.GetHealth(ctx)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, health)
+}
+
+func runIndex(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: cyborgdb index create|list|describe|delete [flags]")
+		return errUsage
+	}
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "create":
+		return runIndexCreate(ctx, conn, args, out)
+	case "list":
+		client, err := conn.client()
+		if err != nil {
+			return err
+		}
+		names, err := client.ListIndexes(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(out, names)
+	case "describe":
+		fs := flag.NewFlagSet("index describe", flag.ContinueOnError)
+		idxFlags := registerIndexFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		index, err := idxFlags.open(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return printJSON(out, map[string]interface{}{
+			"index_name":   index.GetIndexName(),
+			"index_type":   index.GetIndexType(),
+			"is_trained":   index.IsTrained(),
+			"n_lists":      index.GetNLists(),
+			"metric":       index.GetMetric(),
+			"index_config": index.GetIndexConfig(),
+		})
+	case "delete":
+		fs := flag.NewFlagSet("index delete", flag.ContinueOnError)
+		idxFlags := registerIndexFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		index, err := idxFlags.open(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := index.DeleteIndex(ctx); err != nil {
+			return err
+		}
+		return printJSON(out, map[string]string{"deleted": index.GetIndexName()})
+	default:
+		return fmt.Errorf("unknown index subcommand %q", sub)
+	}
+}
+
+func runIndexCreate(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("index create", flag.ContinueOnError)
+	idxFlags := registerIndexFlags(fs)
+	indexType := fs.String("type", "ivfflat", "index type: ivf, ivfflat, or ivfpq")
+	dimension := fs.Int("dimension", 0, "vector dimension (0 lets the server decide)")
+	pqDim := fs.Int("pq-dim", 0, "PQ dimension (ivfpq only)")
+	pqBits := fs.Int("pq-bits", 8, "bits per PQ code (ivfpq only)")
+	metric := fs.String("metric", "", "distance metric, e.g. euclidean or cosine")
+	embeddingModel := fs.String("embedding-model", "", "server-side embedding model name")
+	generateKey := fs.Bool("generate-key", false, "generate a new key and write it (hex) to -key-file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *idxFlags.name == "" {
+		return errors.New("-index is required")
+	}
+
+	var key []byte
+	var keyOut *os.File
+	if *generateKey {
+		if *idxFlags.keyFile == "" {
+			return errors.New("-generate-key requires -key-file")
+		}
+		// Claim the key file before creating the index, and never replace
+		// an existing one: it may hold the only key of another index.
+		f, err := os.OpenFile(*idxFlags.keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return fmt.Errorf("key file %s already exists; refusing to overwrite it", *idxFlags.keyFile)
+			}
+			return fmt.Errorf("failed to create key file: %w", err)
+		}
+		keyOut = f
+		defer func() {
+			// Only reached with keyOut set if the index was not created.
+			if keyOut != nil {
+				keyOut.Close()
+				os.Remove(keyOut.Name())
+			}
+		}()
+		if key, err = cyborgdb.GenerateKey(); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if key, err = idxFlags.key(); err != nil {
+			return err
+		}
+	}
+	defer cyborgdb.Zeroize(key)
+
+	params := &cyborgdb.CreateIndexParams{IndexName: *idxFlags.name, IndexKey: key}
+	switch *indexType {
+	case "ivf":
+		params.IndexConfig = cyborgdb.IndexIVF(int32(*dimension))
+	case "ivfflat":
+		params.IndexConfig = cyborgdb.IndexIVFFlat(int32(*dimension))
+	case "ivfpq":
+		params.IndexConfig = cyborgdb.IndexIVFPQ(int32(*dimension), int32(*pqDim), int32(*pqBits))
+	default:
+		return fmt.Errorf("unknown index type %q", *indexType)
+	}
+	if *metric != "" {
+		params.Metric = metric
+	}
+	if *embeddingModel != "" {
+		params.EmbeddingModel = embeddingModel
+	}
+
+	client, err := conn.client()
+	if err != nil {
+		return err
+	}
+	index, err := client.CreateIndex(ctx, params)
+	if index != nil && keyOut != nil {
+		// The index exists, even if err reports a later failure, so its key
+		// must be kept. An index whose key could not be saved is unusable
+		// and still empty, so it is deleted again.
+		f := keyOut
+		keyOut = nil
+		if writeErr := writeKeyFile(f, key); writeErr != nil {
+			if delErr := index.DeleteIndex(ctx); delErr != nil {
+				return fmt.Errorf("%v; deleting the index also failed: %w", writeErr, delErr)
+			}
+			return writeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return printJSON(out, map[string]string{"created": index.GetIndexName(), "index_type": index.GetIndexType()})
+}
+
+// writeKeyFile writes key to f as hex and closes it.
+func writeKeyFile(f *os.File, key []byte) error {
+	_, err := f.WriteString(hex.EncodeToString(key) + "\n")
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write key file %s: %w", f.Name(), err)
+	}
+	return nil
+}
+
+func runVectors(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: cyborgdb vectors upsert|get|delete [flags]")
+		return errUsage
+	}
+	sub, args := args[0], args[1:]
+	fs := flag.NewFlagSet("vectors "+sub, flag.ContinueOnError)
+	idxFlags := registerIndexFlags(fs)
+
+	switch sub {
+	case "upsert":
+		file := fs.String("file", "-", "JSONL or CSV file of records ('-' for stdin)")
+		format := fs.String("format", "jsonl", "input format: jsonl or csv")
+		batchSize := fs.Int("batch-size", 0, "records per upsert request")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		index, err := idxFlags.open(ctx, conn)
+		if err != nil {
+			return err
+		}
+		in := io.Reader(os.Stdin)
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		summary, err := index.Import(ctx, in, cyborgdb.RecordFormat(*format), cyborgdb.ImportOptions{BatchSize: *batchSize})
+		if summary != nil {
+			if printErr := printJSON(out, summary); printErr != nil {
+				return printErr
+			}
+		}
+		return err
+	case "get":
+		ids := fs.String("ids", "", "comma-separated vector IDs (required)")
+		include := fs.String("include", "vector,metadata,contents", "comma-separated fields to return")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		index, err := idxFlags.open(ctx, conn)
+		if err != nil {
+			return err
+		}
+		resp, err := index.Get(ctx, splitList(*ids), splitList(*include))
+		if err != nil {
+			return err
+		}
+		return printJSON(out, resp)
+	case "delete":
+		ids := fs.String("ids", "", "comma-separated vector IDs (required)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		index, err := idxFlags.open(ctx, conn)
+		if err != nil {
+			return err
+		}
+		idList := splitList(*ids)
+		if err := index.Delete(ctx, idList); err != nil {
+			return err
+		}
+		return printJSON(out, map[string]int{"deleted": len(idList)})
+	default:
+		return fmt.Errorf("unknown vectors subcommand %q", sub)
+	}
+}
+
+func runQuery(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	idxFlags := registerIndexFlags(fs)
+	vector := fs.String("vector", "", "query vector as a JSON array")
+	contents := fs.String("contents", "", "query text (server-side embedding)")
+	topK := fs.Int("top-k", 10, "number of results")
+	nProbes := fs.Int("n-probes", 0, "IVF lists to probe (0 uses the index default)")
+	filters := fs.String("filters", "", "metadata filter as a JSON object")
+	include := fs.String("include", "distance,metadata", "comma-separated fields to return")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	params := cyborgdb.QueryParams{TopK: int32(*topK), Include: splitList(*include)}
+	switch {
+	case *vector != "":
+		if err := json.Unmarshal([]byte(*vector), &params.QueryVector); err != nil {
+			return fmt.Errorf("invalid -vector: %w", err)
+		}
+	case *contents != "":
+		params.QueryContents = contents
+	default:
+		return errors.New("one of -vector or -contents is required")
+	}
+	if *nProbes > 0 {
+		n := int32(*nProbes)
+		params.NProbes = &n
+	}
+	if *filters != "" {
+		if err := json.Unmarshal([]byte(*filters), &params.Filters); err != nil {
+			return fmt.Errorf("invalid -filters: %w", err)
+		}
+	}
+
+	index, err := idxFlags.open(ctx, conn)
+	if err != nil {
+		return err
+	}
+	resp, err := index.Query(ctx, params)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, resp)
+}
+
+func runTrain(ctx context.Context, conn *connFlags, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("train", flag.ContinueOnError)
+	idxFlags := registerIndexFlags(fs)
+	nLists := fs.Int("n-lists", 0, "number of IVF lists (0 = auto)")
+	batchSize := fs.Int("batch-size", 0, "training batch size (0 = server default)")
+	maxIters := fs.Int("max-iters", 0, "maximum iterations (0 = server default)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var params cyborgdb.TrainParams
+	if *nLists > 0 {
+		n := int32(*nLists)
+		params.NLists = &n
+	}
+	if *batchSize > 0 {
+		n := int32(*batchSize)
+		params.BatchSize = &n
+	}
+	if *maxIters > 0 {
+		n := int32(*maxIters)
+		params.MaxIters = &n
+	}
+
+	index, err := idxFlags.open(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if err := index.Train(ctx, params); err != nil {
+		return err
+	}
+	return printJSON(out, map[string]interface{}{"trained": index.GetIndexName()})
+}
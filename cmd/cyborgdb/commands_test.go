@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeService serves index creation with the given status and counts
+// requests.
+func fakeService(t *testing.T, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"status":"success","message":"created"}`))
+			return
+		}
+		w.Write([]byte(`{"detail":"index already exists"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func runCreate(t *testing.T, url, keyFile string) error {
+	t.Helper()
+	var out bytes.Buffer
+	return run(context.Background(), []string{
+		"-url", url, "index", "create",
+		"-index", "docs", "-dimension", "4", "-generate-key", "-key-file", keyFile,
+	}, &out)
+}
+
+func TestIndexCreateGenerateKeyWritesKey(t *testing.T) {
+	srv, _ := fakeService(t, http.StatusOK)
+	keyFile := filepath.Join(t.TempDir(), "docs.key")
+
+	if err := runCreate(t, srv.URL, keyFile); err != nil {
+		t.Fatalf("index create: %v", err)
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatalf("read key file: %v", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		t.Fatalf("key file holds %q, want 32 hex-encoded bytes", data)
+	}
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("key file mode = %v, want 0600", perm)
+	}
+}
+
+func TestIndexCreateGenerateKeyKeepsExistingFile(t *testing.T) {
+	srv, calls := fakeService(t, http.StatusOK)
+	keyFile := filepath.Join(t.TempDir(), "existing.key")
+	const existing = "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff\n"
+	if err := os.WriteFile(keyFile, []byte(existing), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runCreate(t, srv.URL, keyFile)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("index create error = %v, want refusal to overwrite", err)
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != existing {
+		t.Errorf("existing key file was modified: %q", data)
+	}
+	if n := atomic.LoadInt32(calls); n != 0 {
+		t.Errorf("service called %d times, want 0", n)
+	}
+}
+
+func TestIndexCreateGenerateKeyFailedCreateLeavesNoFile(t *testing.T) {
+	srv, calls := fakeService(t, http.StatusConflict)
+	keyFile := filepath.Join(t.TempDir(), "docs.key")
+
+	if err := runCreate(t, srv.URL, keyFile); err == nil {
+		t.Fatal("index create succeeded, want error")
+	}
+	if atomic.LoadInt32(calls) == 0 {
+		t.Fatal("service was not called")
+	}
+	if _, err := os.Stat(keyFile); !os.IsNotExist(err) {
+		t.Errorf("key file left behind after failed create (stat err: %v)", err)
+	}
+}
+
+func TestIndexCreateGenerateKeyRequiresKeyFile(t *testing.T) {
+	var out bytes.Buffer
+	err := run(context.Background(), []string{"index", "create", "-index", "docs", "-generate-key"}, &out)
+	if err == nil || !strings.Contains(err.Error(), "-key-file") {
+		t.Fatalf("index create error = %v, want -key-file required", err)
+	}
+}
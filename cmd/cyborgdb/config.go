@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+const defaultBaseURL = "http://localhost:8000"
+
+var errMissingIndexKey = errors.New("index key required: set -key-file or CYBORGDB_INDEX_KEY")
+
+// connFlags holds service connection settings.
+type connFlags struct {
+	url    *string
+	apiKey *string
+}
+
+func registerConnFlags(fs *flag.FlagSet) *connFlags {
+	return &connFlags{
+		url:    fs.String("url", envOr("CYBORGDB_BASE_URL", defaultBaseURL), "service URL"),
+		apiKey: fs.String("api-key", os.Getenv("CYBORGDB_API_KEY"), "API key"),
+	}
+}
+
+func (c *connFlags) client() (*cyborgdb.Client, error) {
+	return cyborgdb.NewClient(*c.url, *c.apiKey)
+}
+
+// indexFlags holds the settings needed to open an existing index.
+type indexFlags struct {
+	name    *string
+	keyFile *string
+}
+
+func registerIndexFlags(fs *flag.FlagSet) *indexFlags {
+	return &indexFlags{
+		name:    fs.String("index", "", "index name (required)"),
+		keyFile: fs.String("key-file", "", "file holding the index key (hex or 32 raw bytes); overrides CYBORGDB_INDEX_KEY"),
+	}
+}
+
+// key reads the index key from -key-file or CYBORGDB_INDEX_KEY.
+func (f *indexFlags) key() ([]byte, error) {
+	if *f.keyFile != "" {
+		data, err := os.ReadFile(*f.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		if len(data) == cyborgdb.KeySize {
+			return data, nil
+		}
+		return decodeHexKey(string(data))
+	}
+	if env := os.Getenv("CYBORGDB_INDEX_KEY"); env != "" {
+		return decodeHexKey(env)
+	}
+	return nil, errMissingIndexKey
+}
+
+// open loads the index named by the flags.
+func (f *indexFlags) open(ctx context.Context, conn *connFlags) (*cyborgdb.EncryptedIndex, error) {
+	if *f.name == "" {
+		return nil, errors.New("-index is required")
+	}
+	key, err := f.key()
+	if err != nil {
+		return nil, err
+	}
+	defer cyborgdb.Zeroize(key)
+
+	client, err := conn.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.LoadIndex(ctx, *f.name, key)
+}
+
+func decodeHexKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("index key is not valid hex: %w", err)
+	}
+	return key, nil
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+// Command cyborgdb is a command-line tool for managing CyborgDB encrypted
+// indexes and vectors.
+//
+// Usage:
+//
+//	cyborgdb [global flags] <command> [subcommand] [flags]
+//
+// Commands:
+//
+//	health                      Check service health
+//	index create|list|describe|delete
+//	vectors upsert|get|delete
+//	query                       Run a similarity search
+//	train                       Train an index
+//	bench                       Benchmark upsert and query workloads
+//	apply -f indexes.yaml       Converge indexes to a declarative spec file
+//
+// Connection settings come from flags or the environment:
+//
+//	CYBORGDB_BASE_URL    Service URL (default http://localhost:8000)
+//	CYBORGDB_API_KEY     API key
+//	CYBORGDB_INDEX_KEY   Hex-encoded 32-byte index key (or use -key-file)
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+// errUsage signals that usage has already been printed.
+var errUsage = errors.New("usage")
+
+const usage = `Usage: cyborgdb [global flags] <command> [subcommand] [flags]
+
+Commands:
+  health                              Check service health
+  index create|list|describe|delete   Manage indexes
+  vectors upsert|get|delete           Manage vectors
+  query                               Run a similarity search
+  train                               Train an index
+  bench                               Benchmark upsert and query workloads
+  apply -f indexes.yaml               Converge indexes to a declarative spec file
+
+Global flags:
+  -url string       Service URL (env CYBORGDB_BASE_URL, default http://localhost:8000)
+  -api-key string   API key (env CYBORGDB_API_KEY)
+
+Run 'cyborgdb <command> -h' for command flags.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run dispatches a command line to the matching command.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	global := flag.NewFlagSet("cyborgdb", flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprint(global.Output(), usage) }
+	conn := registerConnFlags(global)
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+
+	args = global.Args()
+	if len(args) == 0 {
+		global.Usage()
+		return errUsage
+	}
+
+	cmd, rest := args[0], args[1:]
+	switch cmd {
+	case "health":
+		return runHealth(ctx, conn, rest, out)
+	case "index":
+		return runIndex(ctx, conn, rest, out)
+	case "vectors":
+		return runVectors(ctx, conn, rest, out)
+	case "query":
+		return runQuery(ctx, conn, rest, out)
+	case "train":
+		return runTrain(ctx, conn, rest, out)
+	case "bench":
+		return runBench(ctx, conn, rest, out)
+	case "apply":
+		return runApply(ctx, conn, rest, out)
+	case "help":
+		global.Usage()
+		return nil
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		global.Usage()
+		return errUsage
+	}
+}
+
+// printJSON writes v to out as indented JSON.
+func printJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
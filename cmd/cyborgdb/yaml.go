@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML decodes the block-style YAML subset used by index spec files:
+// nested mappings, sequences ("- "), plain or quoted scalars, and comments.
+// Flow collections other than the empty "[]" and "{}", anchors, aliases,
+// tags, and multi-line scalars are rejected rather than read as strings.
+func parseYAML(data []byte) (interface{}, error) {
+	var lines []yamlLine
+	for n, text := range strings.Split(string(data), "\n") {
+		text = stripYAMLComment(strings.TrimRight(text, " \t\r"))
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", n+1)
+		}
+		lines = append(lines, yamlLine{num: n + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	p := &yamlParser{lines: lines}
+	v, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return v, nil
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence starting at the current line.
+func (p *yamlParser) block(indent int) (interface{}, error) {
+	if strings.HasPrefix(p.lines[p.pos].text, "-") {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) sequence(indent int) (interface{}, error) {
+	var out []interface{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !(line.text == "-" || strings.HasPrefix(line.text, "- ")) {
+			break
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if rest == "" {
+			p.pos++
+			v, err := p.nested(indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+			continue
+		}
+		if _, _, ok := splitYAMLKey(rest); ok {
+			// "- key: value" starts a mapping indented past the dash.
+			p.lines[p.pos] = yamlLine{num: line.num, indent: indent + 2, text: rest}
+			v, err := p.mapping(indent + 2)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+			continue
+		}
+		v, err := yamlScalar(line.num, rest)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		p.pos++
+	}
+	return out, nil
+}
+
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	out := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		key, value, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := out[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+		if value != "" {
+			v, err := yamlScalar(line.num, value)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = v
+			continue
+		}
+		v, err := p.nested(indent)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// nested parses the block indented past parent, or returns nil if none.
+func (p *yamlParser) nested(parent int) (interface{}, error) {
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	// Sequences may sit at the same indentation as their parent key.
+	if next.indent > parent || (next.indent == parent && strings.HasPrefix(next.text, "- ")) {
+		return p.block(next.indent)
+	}
+	return nil, nil
+}
+
+// splitYAMLKey splits "key: value" or "key:" into its parts.
+func splitYAMLKey(s string) (key, value string, ok bool) {
+	if i := strings.Index(s, ": "); i > 0 {
+		return unquoteYAML(s[:i]), strings.TrimSpace(s[i+2:]), true
+	}
+	if strings.HasSuffix(s, ":") && len(s) > 1 {
+		return unquoteYAML(s[:len(s)-1]), "", true
+	}
+	return "", "", false
+}
+
+// yamlScalar converts a plain or quoted scalar on line num to a string,
+// number, bool, or nil, or an empty flow collection to an empty slice or map.
+func yamlScalar(num int, s string) (interface{}, error) {
+	switch s {
+	case "[]":
+		return []interface{}{}, nil
+	case "{}":
+		return map[string]interface{}{}, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	}
+	if s[0] == '"' || s[0] == '\'' {
+		if len(s) < 2 || s[len(s)-1] != s[0] {
+			return nil, fmt.Errorf("line %d: unterminated quoted value %s", num, s)
+		}
+		return unquoteYAML(s), nil
+	}
+	if strings.ContainsRune("[{&*!|>%@`", rune(s[0])) {
+		return nil, fmt.Errorf("line %d: unsupported YAML syntax %q; use block-style YAML or JSON", num, s)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+func unquoteYAML(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
+// stripYAMLComment removes a trailing "# ..." comment outside quotes.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimRight(s[:i], " \t")
+		}
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	got, err := parseYAML([]byte(`
+# index specs
+---
+indexes:
+- name: docs          # sequence at the key's indentation
+  dimension: 768
+  enabled: true
+  note: "a # inside quotes"
+  empty: ~
+  labels:
+    env: 'prod''s'
+- name: ratio
+  scale: 0.5
+tags: []
+extra: {}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"indexes": []interface{}{
+			map[string]interface{}{
+				"name":      "docs",
+				"dimension": int64(768),
+				"enabled":   true,
+				"note":      "a # inside quotes",
+				"empty":     nil,
+				"labels":    map[string]interface{}{"env": "prod's"},
+			},
+			map[string]interface{}{"name": "ratio", "scale": 0.5},
+		},
+		"tags":  []interface{}{},
+		"extra": map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAML = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestParseYAMLRejectsUnsupportedSyntax(t *testing.T) {
+	for name, doc := range map[string]string{
+		"flow mapping":    "labels: {env: prod}\n",
+		"flow sequence":   "tags: [a, b]\n",
+		"flow item":       "tags:\n  - [a, b]\n",
+		"anchor":          "base: &base\n  a: 1\n",
+		"alias":           "copy: *base\n",
+		"tag":             "n: !!int 3\n",
+		"block scalar":    "text: |\n  line\n",
+		"folded scalar":   "text: >\n  line\n",
+		"unterminated":    "name: \"docs\n",
+		"duplicate key":   "a: 1\na: 2\n",
+		"tab indentation": "a:\n\tb: 1\n",
+		"bad indentation": "a: 1\n  b: 2\n",
+		"missing colon":   "a: 1\nb\n",
+	} {
+		if v, err := parseYAML([]byte(doc)); err == nil {
+			t.Errorf("%s: parsed %q as %#v without error", name, doc, v)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// compression.go implements request body compression.
+package cyborgdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Compression names a Content-Encoding applied to request bodies.
+type Compression string
+
+const (
+	// CompressionNone sends request bodies uncompressed. This is the default.
+	CompressionNone Compression = ""
+	// CompressionGzip compresses request bodies with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses request bodies with zstd. The SDK does not
+	// bundle a zstd encoder; register one with RegisterCompressor first.
+	CompressionZstd Compression = "zstd"
+)
+
+// DefaultCompressionThreshold is the smallest request body, in bytes, that is
+// compressed. Smaller bodies are sent as-is since compression would not pay off.
+const DefaultCompressionThreshold = 1024
+
+// ErrUnsupportedCompression is returned when no compressor is registered for a Compression.
+var ErrUnsupportedCompression = fmt.Errorf("unsupported compression")
+
+// CompressorFunc wraps w in a writer that compresses everything written to it.
+// Close must flush all compressed data to w.
+type CompressorFunc func(w io.Writer) (io.WriteCloser, error)
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[Compression]CompressorFunc{
+		CompressionGzip: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+	}
+)
+
+// RegisterCompressor makes a request body encoding available to WithCompression.
+//
+// Example (using github.com/klauspost/compress/zstd):
+//
+//	cyborgdb.RegisterCompressor(cyborgdb.CompressionZstd, func(w io.Writer) (io.WriteCloser, error) {
+//		return zstd.NewWriter(w)
+//	})
+func RegisterCompressor(c Compression, fn CompressorFunc) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[c] = fn
+}
+
+// lookupCompressor returns the registered compressor for c.
+func lookupCompressor(c Compression) (CompressorFunc, error) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	fn, ok := compressors[c]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCompression, c)
+	}
+	return fn, nil
+}
+
+// WithCompression compresses request bodies of at least
+// DefaultCompressionThreshold bytes, such as large upserts and batch queries.
+// Gzip-encoded responses are always accepted and decoded transparently.
+func WithCompression(c Compression) ClientOption {
+	return func(o *clientOptions) { o.compression = c }
+}
+
+// compressingTransport compresses request bodies before passing them on.
+type compressingTransport struct {
+	next      http.RoundTripper
+	encoding  Compression
+	compress  CompressorFunc
+	threshold int64
+}
+
+// compressChunkSize is the amount of body compressed between checks for a
+// canceled request.
+const compressChunkSize = 1 << 20
+
+// RoundTrip implements http.RoundTripper.
+func (t *compressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	out := req.Clone(req.Context())
+	if int64(len(body)) < t.threshold {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		return t.next.RoundTrip(out)
+	}
+
+	var buf bytes.Buffer
+	w, err := t.compress(&buf)
+	if err != nil {
+		return nil, err
+	}
+	// Compress in chunks so a canceled request stops promptly even for a
+	// large body.
+	for start := 0; start < len(body); start += compressChunkSize {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		end := start + compressChunkSize
+		if end > len(body) {
+			end = len(body)
+		}
+		if _, err := w.Write(body[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	compressed := buf.Bytes()
+	out.Body = io.NopCloser(bytes.NewReader(compressed))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	out.ContentLength = int64(len(compressed))
+	out.Header.Set("Content-Encoding", string(t.encoding))
+	return t.next.RoundTrip(out)
+}
@@ -0,0 +1,65 @@
+package cyborgdb_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestCompressionGzipsLargeBodies(t *testing.T) {
+	encodings := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("%s: invalid gzip body: %v", r.URL.Path, err)
+				return
+			}
+			body = zr
+		}
+		if _, err := io.ReadAll(body); err != nil {
+			t.Errorf("%s: reading body: %v", r.URL.Path, err)
+		}
+		encodings[r.URL.Path] = r.Header.Get("Content-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/indexes/describe" {
+			w.Write([]byte(`{"index_name":"docs","index_type":"ivfflat","is_trained":false,"index_config":{"type":"ivfflat","dimension":512}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","message":"ok"}`))
+	}))
+	defer srv.Close()
+
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithCompression(cyborgdb.CompressionGzip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	index, err := client.LoadIndex(ctx, "docs", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: "a", Vector: make([]float32, 512)}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := encodings["/v1/indexes/describe"]; got != "" {
+		t.Errorf("small describe body sent with Content-Encoding %q, want none", got)
+	}
+	if got := encodings["/v1/vectors/upsert"]; got != "gzip" {
+		t.Errorf("large upsert body sent with Content-Encoding %q, want gzip", got)
+	}
+}
+
+func TestCompressionUnregistered(t *testing.T) {
+	_, err := cyborgdb.NewClientWithOptions("http://localhost:8000", "key", cyborgdb.WithCompression("brotli"))
+	if !errors.Is(err, cyborgdb.ErrUnsupportedCompression) {
+		t.Errorf("err = %v, want ErrUnsupportedCompression", err)
+	}
+}
@@ -0,0 +1,210 @@
+// conditional.go implements conditional upserts (insert-only and optimistic
+// concurrency via a version number in metadata).
+package cyborgdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// VersionMetadataKey is the metadata field holding an item's version for
+// UpsertIfVersionMatches.
+const VersionMetadataKey = "_version"
+
+// ErrUpsertConflict is returned by ConditionalUpsertResult.Err when any item
+// was rejected by its condition.
+var ErrUpsertConflict = fmt.Errorf("conditional upsert conflict")
+
+// ErrDuplicateItemID is returned by UpsertConditional when an ID appears more
+// than once in a batch checked against the stored items. Every copy would be
+// checked against the same stored state, so more than one could pass and the
+// last would silently win.
+var ErrDuplicateItemID = fmt.Errorf("duplicate item ID in conditional upsert")
+
+// UpsertMode selects the condition applied by UpsertConditional.
+type UpsertMode int
+
+const (
+	// UpsertAlways writes every item, like Upsert.
+	UpsertAlways UpsertMode = iota
+
+	// UpsertInsertOnly writes only items whose ID does not exist yet.
+	UpsertInsertOnly
+
+	// UpsertIfVersionMatches writes an item only if the version stored on the
+	// server equals the version in the item's metadata (VersionMetadataKey),
+	// i.e. the version the writer last read. A missing version means "the item
+	// must not exist yet". Written items are stored with the version
+	// incremented by one.
+	UpsertIfVersionMatches
+)
+
+// UpsertConflict describes an item rejected by its condition.
+type UpsertConflict struct {
+	// ID is the item ID.
+	ID string `json:"id"`
+	// Exists reports whether the ID was already present on the server.
+	Exists bool `json:"exists"`
+	// ExpectedVersion is the version the item carried (UpsertIfVersionMatches only).
+	ExpectedVersion int64 `json:"expected_version,omitempty"`
+	// ActualVersion is the version stored on the server (UpsertIfVersionMatches only).
+	ActualVersion int64 `json:"actual_version,omitempty"`
+}
+
+// ConditionalUpsertResult reports which items UpsertConditional wrote.
+type ConditionalUpsertResult struct {
+	// Upserted lists the IDs written.
+	Upserted []string `json:"upserted"`
+	// Conflicts lists the items rejected by their condition.
+	Conflicts []UpsertConflict `json:"conflicts,omitempty"`
+}
+
+// Err returns an error wrapping ErrUpsertConflict if any item was rejected,
+// for callers that treat conflicts as failures.
+func (r *ConditionalUpsertResult) Err() error {
+	if len(r.Conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d items rejected", ErrUpsertConflict, len(r.Conflicts), len(r.Conflicts)+len(r.Upserted))
+}
+
+// UpsertConditional upserts the items that satisfy mode and reports the rest
+// as conflicts instead of overwriting them.
+//
+// The service has no server-side conditional writes, so the condition is
+// checked by reading the current items first. This closes the common case of
+// writers clobbering each other's updates, but two writers racing within the
+// same round trip can still both succeed; use UpsertIfVersionMatches and
+// re-read on conflict for read-modify-write loops.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - items: Items to write; with UpsertIfVersionMatches, each item's metadata
+//     carries the expected version under VersionMetadataKey
+//   - mode: The condition to apply
+//
+// Returns:
+//   - *ConditionalUpsertResult: IDs written and conflicts
+//   - error: ErrDuplicateItemID if an ID appears twice under UpsertInsertOnly
+//     or UpsertIfVersionMatches, or any error reading or writing; conflicts
+//     are not errors (see Err)
+//
+// Example:
+//
+//	item.Metadata[cyborgdb.VersionMetadataKey] = readVersion
+//	res, err := index.UpsertConditional(ctx, []cyborgdb.VectorItem{item}, cyborgdb.UpsertIfVersionMatches)
+//	if err == nil && res.Err() != nil {
+//		// someone else updated the item: re-read and retry
+//	}
+func (e *EncryptedIndex) UpsertConditional(ctx context.Context, items []VectorItem, mode UpsertMode) (*ConditionalUpsertResult, error) {
+	result := &ConditionalUpsertResult{}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	if mode == UpsertAlways {
+		if err := e.Upsert(ctx, items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			result.Upserted = append(result.Upserted, item.Id)
+		}
+		return result, nil
+	}
+
+	ids := make([]string, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		if seen[item.Id] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateItemID, item.Id)
+		}
+		seen[item.Id] = true
+		ids[i] = item.Id
+	}
+	include := []string{}
+	if mode == UpsertIfVersionMatches {
+		include = []string{"metadata"}
+	}
+	existing, err := e.Get(ctx, ids, include)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current items: %w", err)
+	}
+	current := make(map[string]map[string]interface{}, len(existing.Results))
+	for _, r := range existing.Results {
+		current[r.Id] = r.Metadata
+	}
+
+	var write []VectorItem
+	for _, item := range items {
+		stored, exists := current[item.Id]
+
+		switch mode {
+		case UpsertInsertOnly:
+			if exists {
+				result.Conflicts = append(result.Conflicts, UpsertConflict{ID: item.Id, Exists: true})
+				continue
+			}
+
+		case UpsertIfVersionMatches:
+			expected, _ := metadataVersion(item.Metadata)
+			var actual int64
+			if exists {
+				actual, _ = metadataVersion(stored)
+			}
+			if expected != actual || (!exists && expected != 0) {
+				result.Conflicts = append(result.Conflicts, UpsertConflict{
+					ID:              item.Id,
+					Exists:          exists,
+					ExpectedVersion: expected,
+					ActualVersion:   actual,
+				})
+				continue
+			}
+			item.Metadata = withVersion(item.Metadata, actual+1)
+
+		default:
+			return nil, fmt.Errorf("unknown upsert mode %d", mode)
+		}
+		write = append(write, item)
+	}
+
+	if len(write) > 0 {
+		if err := e.Upsert(ctx, write); err != nil {
+			return nil, err
+		}
+		for _, item := range write {
+			result.Upserted = append(result.Upserted, item.Id)
+		}
+	}
+	return result, nil
+}
+
+// metadataVersion reads VersionMetadataKey from metadata.
+func metadataVersion(metadata map[string]interface{}) (int64, bool) {
+	switch v := metadata[VersionMetadataKey].(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// withVersion returns a copy of metadata with VersionMetadataKey set to version.
+func withVersion(metadata map[string]interface{}, version int64) map[string]interface{} {
+	out := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[VersionMetadataKey] = version
+	return out
+}
@@ -0,0 +1,40 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestUpsertConditionalRejectsDuplicateIDs(t *testing.T) {
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+
+	for _, mode := range []cyborgdb.UpsertMode{cyborgdb.UpsertInsertOnly, cyborgdb.UpsertIfVersionMatches} {
+		_, err := index.UpsertConditional(ctx, []cyborgdb.VectorItem{
+			{Id: "a", Vector: []float32{1, 0}},
+			{Id: "b", Vector: []float32{0, 1}},
+			{Id: "a", Vector: []float32{0, 1}},
+		}, mode)
+		if !errors.Is(err, cyborgdb.ErrDuplicateItemID) {
+			t.Errorf("mode %d: err = %v, want ErrDuplicateItemID", mode, err)
+		}
+	}
+	if fake.item("docs", "a") != nil || fake.item("docs", "b") != nil {
+		t.Error("items written despite duplicate IDs")
+	}
+
+	res, err := index.UpsertConditional(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}},
+		{Id: "b", Vector: []float32{0, 1}},
+	}, cyborgdb.UpsertInsertOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Upserted) != 2 || res.Err() != nil {
+		t.Errorf("result = %+v, want both items upserted", res)
+	}
+}
@@ -0,0 +1,305 @@
+// Package connectors streams messages from queues such as Kafka or NATS into
+// an encrypted index with at-least-once delivery.
+//
+// The package is broker-agnostic so the SDK stays free of client-library
+// dependencies: wrap your consumer in a Source (a few lines for kafka-go,
+// sarama, or nats.go JetStream; see the Source example) and supply a Decoder
+// that maps each message to a VectorItem. Run micro-batches decoded items,
+// upserts them, and only then commits the messages, so a crash re-delivers
+// rather than loses data. Messages that cannot be decoded, or batches the
+// server keeps rejecting, go to an optional DeadLetter sink and are committed
+// so they do not block the stream.
+package connectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+const (
+	// DefaultBatchSize is the default maximum number of messages per upsert.
+	DefaultBatchSize = 500
+	// DefaultFlushInterval is the default longest time a partial batch is held.
+	DefaultFlushInterval = time.Second
+	// DefaultMaxAttempts is the default number of upsert attempts per batch.
+	DefaultMaxAttempts = 3
+	// initialBackoff is the delay before the first upsert retry; it doubles on each attempt.
+	initialBackoff = 500 * time.Millisecond
+)
+
+// ErrNoDeadLetter is returned when a message must be dead-lettered but no
+// DeadLetter sink is configured.
+var ErrNoDeadLetter = errors.New("connectors: message rejected and no dead-letter sink configured")
+
+// Message is a single message read from a Source.
+type Message struct {
+	// Key is the message key, if any.
+	Key []byte
+	// Value is the message payload.
+	Value []byte
+	// Headers holds message headers, if the broker supports them.
+	Headers map[string]string
+	// Raw is the broker's own message value (e.g. kafka.Message or *nats.Msg),
+	// passed back to Commit.
+	Raw interface{}
+}
+
+// Source reads messages from a broker and commits them once processed.
+//
+// Example (github.com/segmentio/kafka-go):
+//
+//	type kafkaSource struct{ r *kafka.Reader }
+//
+//	func (s kafkaSource) Fetch(ctx context.Context) (connectors.Message, error) {
+//		m, err := s.r.FetchMessage(ctx)
+//		return connectors.Message{Key: m.Key, Value: m.Value, Raw: m}, err
+//	}
+//
+//	func (s kafkaSource) Commit(ctx context.Context, msgs []connectors.Message) error {
+//		raw := make([]kafka.Message, len(msgs))
+//		for i, m := range msgs {
+//			raw[i] = m.Raw.(kafka.Message)
+//		}
+//		return s.r.CommitMessages(ctx, raw...)
+//	}
+//
+// For NATS JetStream, Fetch wraps a pull subscription's Fetch(1) and Commit
+// calls Ack on each *nats.Msg.
+type Source interface {
+	// Fetch blocks until a message is available or ctx is done.
+	Fetch(ctx context.Context) (Message, error)
+
+	// Commit marks msgs as processed. Messages are passed in the order they
+	// were fetched.
+	Commit(ctx context.Context, msgs []Message) error
+}
+
+// Decoder maps a message to the item to upsert.
+type Decoder func(Message) (cyborgdb.VectorItem, error)
+
+// DeadLetter receives messages that could not be indexed.
+type DeadLetter interface {
+	// Send records msg with the reason it was rejected.
+	Send(ctx context.Context, msg Message, reason error) error
+}
+
+// DeadLetterFunc adapts a function to the DeadLetter interface.
+type DeadLetterFunc func(ctx context.Context, msg Message, reason error) error
+
+// Send implements DeadLetter.
+func (f DeadLetterFunc) Send(ctx context.Context, msg Message, reason error) error {
+	return f(ctx, msg, reason)
+}
+
+// Options configures Run. Zero values fall back to defaults.
+type Options struct {
+	// BatchSize is the maximum number of messages upserted per request. Default: 500.
+	BatchSize int
+
+	// FlushInterval bounds how long a partial batch is held before it is sent. Default: 1s.
+	FlushInterval time.Duration
+
+	// MaxAttempts is the number of times a batch upsert is attempted before
+	// its messages are dead-lettered. Default: 3.
+	MaxAttempts int
+
+	// DeadLetter, if set, receives undecodable messages and messages from
+	// batches that exhausted MaxAttempts. Without it, Run stops with an error
+	// instead of skipping them.
+	DeadLetter DeadLetter
+
+	// OnBatch, if set, is called after each batch is committed.
+	OnBatch func(Stats)
+}
+
+// Stats reports Run progress.
+type Stats struct {
+	// Upserted is the number of messages indexed.
+	Upserted int
+	// DeadLettered is the number of messages sent to the dead-letter sink.
+	DeadLettered int
+}
+
+// fetchResult carries one Fetch outcome from the reader goroutine.
+type fetchResult struct {
+	msg Message
+	err error
+}
+
+// Run consumes src until ctx is done or the source fails, upserting decoded
+// messages into index and committing them after each successful upsert.
+//
+// Parameters:
+//   - ctx: Context for cancellation; cancelling it stops Run after the
+//     in-progress batch is abandoned uncommitted (it will be re-delivered)
+//   - index: Destination index
+//   - src: Message source
+//   - decode: Maps messages to items
+//   - opts: Batching, retry, and dead-letter options
+//
+// Returns:
+//   - Stats: Totals at the time Run returned
+//   - error: ctx.Err() on cancellation, or the first unrecoverable error
+func Run(ctx context.Context, index *cyborgdb.EncryptedIndex, src Source, decode Decoder, opts Options) (Stats, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Fetch blocks, so it runs in its own goroutine to let the flush timer fire.
+	// The unbuffered channel keeps at most one message in hand, preserving
+	// backpressure on the broker.
+	fetched := make(chan fetchResult)
+	go func() {
+		for {
+			msg, err := src.Fetch(ctx)
+			select {
+			case fetched <- fetchResult{msg: msg, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	r := &runner{index: index, src: src, opts: opts}
+	timer := time.NewTimer(opts.FlushInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return r.stats, ctx.Err()
+
+		case <-timer.C:
+			if err := r.flush(ctx); err != nil {
+				return r.stats, err
+			}
+			timer.Reset(opts.FlushInterval)
+
+		case res := <-fetched:
+			if res.err != nil {
+				if ctx.Err() != nil {
+					return r.stats, ctx.Err()
+				}
+				return r.stats, fmt.Errorf("connectors: fetch failed: %w", res.err)
+			}
+
+			item, err := decode(res.msg)
+			if err != nil {
+				if err := r.deadLetter(ctx, res.msg, fmt.Errorf("decode: %w", err)); err != nil {
+					return r.stats, err
+				}
+				// Dead-lettered messages are committed with the next batch.
+				r.pending = append(r.pending, res.msg)
+				continue
+			}
+			r.pending = append(r.pending, res.msg)
+			r.items = append(r.items, item)
+			r.decoded = append(r.decoded, res.msg)
+
+			if len(r.items) >= opts.BatchSize {
+				if err := r.flush(ctx); err != nil {
+					return r.stats, err
+				}
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(opts.FlushInterval)
+			}
+		}
+	}
+}
+
+// runner holds the in-progress batch for Run.
+type runner struct {
+	index   *cyborgdb.EncryptedIndex
+	src     Source
+	opts    Options
+	stats   Stats
+	pending []Message             // every message to commit, in fetch order
+	items   []cyborgdb.VectorItem // decoded items to upsert
+	decoded []Message             // messages that produced items, parallel to items
+}
+
+// flush upserts the batch with retries, dead-letters it if every attempt
+// fails, and commits all pending messages.
+func (r *runner) flush(ctx context.Context) error {
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	if len(r.items) > 0 {
+		if err := r.upsertWithRetry(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			for _, msg := range r.decoded {
+				if err := r.deadLetter(ctx, msg, fmt.Errorf("upsert: %w", err)); err != nil {
+					return err
+				}
+			}
+		} else {
+			r.stats.Upserted += len(r.items)
+		}
+	}
+
+	if err := r.src.Commit(ctx, r.pending); err != nil {
+		return fmt.Errorf("connectors: commit failed: %w", err)
+	}
+	r.pending, r.items, r.decoded = nil, nil, nil
+	if r.opts.OnBatch != nil {
+		r.opts.OnBatch(r.stats)
+	}
+	return nil
+}
+
+// upsertWithRetry upserts the batch, retrying with exponential backoff.
+func (r *runner) upsertWithRetry(ctx context.Context) error {
+	backoff := initialBackoff
+	var err error
+	for attempt := 1; attempt <= r.opts.MaxAttempts; attempt++ {
+		if err = r.index.Upsert(ctx, r.items); err == nil {
+			return nil
+		}
+		if attempt == r.opts.MaxAttempts {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// deadLetter sends msg to the dead-letter sink.
+func (r *runner) deadLetter(ctx context.Context, msg Message, reason error) error {
+	if r.opts.DeadLetter == nil {
+		return fmt.Errorf("%w: %v", ErrNoDeadLetter, reason)
+	}
+	if err := r.opts.DeadLetter.Send(ctx, msg, reason); err != nil {
+		return fmt.Errorf("connectors: dead-letter failed: %w", err)
+	}
+	r.stats.DeadLettered++
+	return nil
+}
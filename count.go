@@ -0,0 +1,44 @@
+// count.go implements counting the items in an index that match a metadata
+// filter.
+package cyborgdb
+
+import "context"
+
+// Count returns the number of items whose metadata matches filters, in the
+// syntax of QueryParams.Filters (see MatchFilter). With no filters it
+// returns the size of the index.
+//
+// The service has no filtered count endpoint, so with filters Count lists
+// every ID and streams the metadata through MatchFilter a few chunks at a
+// time; its cost grows with the index size, not the count. Cache the result
+// where it is read often, e.g. for dashboards.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts; it applies to every request
+//   - filters: Metadata filter, or nil to count every item
+//
+// Returns:
+//   - int: The number of matching items
+//   - error: Any error listing IDs, fetching metadata, or in filters
+//
+// Example:
+//
+//	n, err := index.Count(ctx, map[string]interface{}{"tenant": "acme"})
+//	if err == nil && n >= quota {
+//		return ErrQuotaExceeded
+//	}
+func (e *EncryptedIndex) Count(ctx context.Context, filters map[string]interface{}) (int, error) {
+	listed, err := e.listIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(filters) == 0 {
+		return len(listed.Ids), nil
+	}
+	count := 0
+	err = e.matchIDs(ctx, listed.Ids, filters, func(string) { count++ })
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,229 @@
+// credentials.go supplies API credentials to a Client, with refresh support
+// for short-lived tokens.
+package cyborgdb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// apiKeyHeader is the header carrying the API key or token.
+const apiKeyHeader = "X-API-Key"
+
+// DefaultCredentialsExpiryWindow is how long before expiry RefreshingCredentials
+// fetches a new token, so requests in flight never carry an expired one.
+const DefaultCredentialsExpiryWindow = 30 * time.Second
+
+// CredentialsProvider supplies the API key or token sent with each request.
+//
+// Token is called for every request and should return quickly, caching the
+// token between calls. If the provider also implements CredentialsInvalidator,
+// a 401 response invalidates the token and the request is retried once with a
+// fresh one.
+type CredentialsProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// CredentialsInvalidator is implemented by providers that can discard a token
+// the server rejected.
+type CredentialsInvalidator interface {
+	// Invalidate discards token so the next Token call obtains a new one.
+	Invalidate(token string)
+}
+
+// StaticCredentials is a CredentialsProvider that always returns the same key.
+type StaticCredentials string
+
+// Token implements CredentialsProvider.
+func (s StaticCredentials) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// TokenFetchFunc obtains a new token and the time it expires. A zero expiry
+// means the token does not expire.
+type TokenFetchFunc func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// RefreshingCredentials caches a token from a TokenFetchFunc and fetches a new
+// one shortly before it expires or after the server rejects it. It is safe
+// for concurrent use; concurrent refreshes are collapsed into one fetch.
+//
+// Example (OAuth2 client credentials via golang.org/x/oauth2):
+//
+//	creds := cyborgdb.NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+//		tok, err := tokenSource.Token()
+//		if err != nil {
+//			return "", time.Time{}, err
+//		}
+//		return tok.AccessToken, tok.Expiry, nil
+//	})
+//	client, err := cyborgdb.NewClientWithOptions(url, "", cyborgdb.WithCredentialsProvider(creds))
+type RefreshingCredentials struct {
+	fetch TokenFetchFunc
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewRefreshingCredentials returns credentials backed by fetch.
+func NewRefreshingCredentials(fetch TokenFetchFunc) *RefreshingCredentials {
+	return &RefreshingCredentials{fetch: fetch}
+}
+
+// Token implements CredentialsProvider.
+func (r *RefreshingCredentials) Token(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token != "" && (r.expiry.IsZero() || time.Until(r.expiry) > DefaultCredentialsExpiryWindow) {
+		return r.token, nil
+	}
+
+	token, expiry, err := r.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.token, r.expiry = token, expiry
+	return token, nil
+}
+
+// Expiry returns the expiry of the cached token, or the zero time if there is
+// no cached token or it does not expire.
+func (r *RefreshingCredentials) Expiry() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.expiry
+}
+
+// Invalidate implements CredentialsInvalidator.
+func (r *RefreshingCredentials) Invalidate(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token == token {
+		r.token, r.expiry = "", time.Time{}
+	}
+}
+
+// WithCredentialsProvider authenticates requests with tokens from p instead
+// of the static API key passed to the constructor.
+func WithCredentialsProvider(p CredentialsProvider) ClientOption {
+	return func(o *clientOptions) { o.credentials = p }
+}
+
+// credentialsTransport sets the API key header from a CredentialsProvider.
+type credentialsTransport struct {
+	next  http.RoundTripper
+	creds CredentialsProvider
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *credentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, err := t.creds.Token(ctx)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	out := req.Clone(ctx)
+	setAPIKeyHeader(out, token)
+	resp, err := t.next.RoundTrip(out)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	invalidator, ok := t.creds.(CredentialsInvalidator)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	invalidator.Invalidate(token)
+
+	fresh, err := t.creds.Token(ctx)
+	if err != nil || fresh == token {
+		// Nothing new to try; report the original rejection.
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.Clone(ctx)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	setAPIKeyHeader(retry, fresh)
+	return t.next.RoundTrip(retry)
+}
+
+// setAPIKeyHeader sets the API key header to token. An empty token removes
+// the header, including the constructor's key that the generated client sets
+// on every request, so SetAPIKey("") really sends no key.
+func setAPIKeyHeader(req *http.Request, token string) {
+	if token == "" {
+		req.Header.Del(apiKeyHeader)
+		return
+	}
+	req.Header.Set(apiKeyHeader, token)
+}
+
+// swappableCredentials lets a Client replace its credentials at runtime.
+// Each request reads the current provider once, so in-flight requests finish
+// with the credentials they started with.
+type swappableCredentials struct {
+	mu       sync.RWMutex
+	provider CredentialsProvider
+}
+
+// current returns the active provider.
+func (s *swappableCredentials) current() CredentialsProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.provider
+}
+
+// set replaces the active provider.
+func (s *swappableCredentials) set(p CredentialsProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = p
+}
+
+// Token implements CredentialsProvider.
+func (s *swappableCredentials) Token(ctx context.Context) (string, error) {
+	return s.current().Token(ctx)
+}
+
+// Invalidate implements CredentialsInvalidator by forwarding to the active
+// provider, if it supports invalidation.
+func (s *swappableCredentials) Invalidate(token string) {
+	if inv, ok := s.current().(CredentialsInvalidator); ok {
+		inv.Invalidate(token)
+	}
+}
+
+// SetAPIKey replaces the API key used for subsequent requests, e.g. during
+// scheduled key rotation. Requests already in flight complete with the key
+// they started with; index handles created from the client pick up the new
+// key automatically.
+func (c *Client) SetAPIKey(apiKey string) {
+	c.credentials.set(StaticCredentials(apiKey))
+}
+
+// CredentialsExpiry returns when the current credentials expire, or the zero
+// time if they do not expire or the provider does not report an expiry.
+func (c *Client) CredentialsExpiry() time.Time {
+	if e, ok := c.credentials.current().(interface{ Expiry() time.Time }); ok {
+		return e.Expiry()
+	}
+	return time.Time{}
+}
+
+// UpdateCredentials replaces the credentials provider used for subsequent
+// requests. See SetAPIKey.
+func (c *Client) UpdateCredentials(p CredentialsProvider) {
+	c.credentials.set(p)
+}
@@ -0,0 +1,120 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// tokenService accepts only the token "valid" and records the keys sent.
+func tokenService(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("X-API-Key"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-API-Key") != "valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"detail":"invalid API key"}`))
+			return
+		}
+		w.Write([]byte(`{"indexes":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+}
+
+func TestRefreshingCredentialsRetryOn401(t *testing.T) {
+	srv, keys := tokenService(t)
+	tokens := []string{"stale", "valid"}
+	var fetches int
+	creds := cyborgdb.NewRefreshingCredentials(func(context.Context) (string, time.Time, error) {
+		token := tokens[fetches]
+		fetches++
+		return token, time.Time{}, nil
+	})
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "", cyborgdb.WithCredentialsProvider(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ListIndexes(context.Background()); err != nil {
+		t.Fatalf("ListIndexes: %v", err)
+	}
+	if _, err := client.ListIndexes(context.Background()); err != nil {
+		t.Fatalf("second ListIndexes: %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("token fetched %d times, want 2", fetches)
+	}
+	got := keys()
+	want := []string{"stale", "valid", "valid"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("keys sent = %v, want %v", got, want)
+	}
+}
+
+func TestRefreshingCredentialsRefreshBeforeExpiry(t *testing.T) {
+	var fetches int
+	creds := cyborgdb.NewRefreshingCredentials(func(context.Context) (string, time.Time, error) {
+		fetches++
+		// Inside the expiry window, so every call fetches again.
+		return "valid", time.Now().Add(cyborgdb.DefaultCredentialsExpiryWindow / 2), nil
+	})
+	for i := 0; i < 2; i++ {
+		if _, err := creds.Token(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("token fetched %d times, want 2", fetches)
+	}
+	if creds.Expiry().IsZero() {
+		t.Error("Expiry is zero after fetching an expiring token")
+	}
+}
+
+func TestStaticCredentialsNoRetry(t *testing.T) {
+	srv, keys := tokenService(t)
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "", cyborgdb.WithCredentialsProvider(cyborgdb.StaticCredentials("wrong")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListIndexes(context.Background()); err == nil {
+		t.Fatal("ListIndexes succeeded with a rejected key")
+	}
+	if got := keys(); len(got) != 1 {
+		t.Errorf("keys sent = %v, want a single attempt", got)
+	}
+}
+
+func TestSetAPIKeyEmptyClearsHeader(t *testing.T) {
+	srv, keys := tokenService(t)
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := client.ListIndexes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client.SetAPIKey("")
+	if _, err := client.ListIndexes(ctx); err == nil {
+		t.Fatal("ListIndexes succeeded after the API key was cleared")
+	}
+	if got := keys(); len(got) != 2 || got[0] != "valid" || got[1] != "" {
+		t.Errorf("keys sent = %q, want [valid \"\"]", got)
+	}
+}
@@ -0,0 +1,209 @@
+// dedup.go implements content-based deduplication for ingestion.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+const (
+	// DefaultDedupExpectedItems sizes the duplicate filter when
+	// DedupOptions.ExpectedItems is not set.
+	DefaultDedupExpectedItems = 1000000
+	// DefaultDedupFalsePositiveRate is the default probability that a unique
+	// item is mistaken for a duplicate by the in-memory filter.
+	DefaultDedupFalsePositiveRate = 1e-6
+)
+
+// DedupAction selects what happens to an item whose content was seen before.
+type DedupAction int
+
+const (
+	// DedupSkip drops duplicates and counts them.
+	DedupSkip DedupAction = iota
+	// DedupMerge writes duplicates under their content-derived ID, so they
+	// update the stored item instead of creating a second copy. It implies
+	// DedupOptions.ContentIDs.
+	DedupMerge
+)
+
+// DedupOptions configures a Deduper. Zero values fall back to defaults.
+type DedupOptions struct {
+	// Action is applied to duplicates. Default: DedupSkip.
+	Action DedupAction
+
+	// Key returns the bytes that identify an item's content. Default: the
+	// string contents if set, otherwise the vector.
+	Key func(VectorItem) []byte
+
+	// ContentIDs replaces each item's ID with DeterministicID of its key, so
+	// the same content always has the same ID across ingestion runs.
+	ContentIDs bool
+
+	// CheckExisting looks up each batch's IDs on the server with Get and
+	// treats items already stored as duplicates. Combine with ContentIDs to
+	// dedupe re-crawled corpora against earlier runs.
+	CheckExisting bool
+
+	// ExpectedItems is the number of distinct items the in-memory filter is
+	// sized for. Default: 1,000,000.
+	ExpectedItems int
+
+	// FalsePositiveRate is the target probability that a unique item is
+	// wrongly treated as a duplicate. Default: 1e-6.
+	FalsePositiveRate float64
+}
+
+// Deduper filters duplicate items during ingestion. Items seen in this
+// process are remembered in a Bloom filter, so memory stays bounded for large
+// corpora. A Deduper is safe for concurrent use and can be shared across
+// Import and Upsert calls.
+type Deduper struct {
+	opts DedupOptions
+
+	mu      sync.Mutex
+	seen    *bloomFilter
+	deduped int
+}
+
+// NewDeduper returns a Deduper configured by opts.
+func NewDeduper(opts DedupOptions) *Deduper {
+	if opts.Key == nil {
+		opts.Key = contentKey
+	}
+	if opts.Action == DedupMerge {
+		opts.ContentIDs = true
+	}
+	if opts.ExpectedItems <= 0 {
+		opts.ExpectedItems = DefaultDedupExpectedItems
+	}
+	if opts.FalsePositiveRate <= 0 || opts.FalsePositiveRate >= 1 {
+		opts.FalsePositiveRate = DefaultDedupFalsePositiveRate
+	}
+	return &Deduper{opts: opts, seen: newBloomFilter(opts.ExpectedItems, opts.FalsePositiveRate)}
+}
+
+// Deduplicated returns the total number of duplicates found so far.
+func (d *Deduper) Deduplicated() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.deduped
+}
+
+// Filter applies deduplication to items and returns the items to upsert and
+// the number of duplicates skipped. With DedupMerge nothing is skipped;
+// duplicates are returned under their content ID.
+func (d *Deduper) Filter(ctx context.Context, index *EncryptedIndex, items []VectorItem) ([]VectorItem, int, error) {
+	out, _, err := d.filter(ctx, index, items)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, len(items) - len(out), nil
+}
+
+// filter is Filter that also reports the input position of each kept item.
+func (d *Deduper) filter(ctx context.Context, index *EncryptedIndex, items []VectorItem) ([]VectorItem, []int, error) {
+	out := make([]VectorItem, 0, len(items))
+	positions := make([]int, 0, len(items))
+
+	d.mu.Lock()
+	for i, item := range items {
+		key := d.opts.Key(item)
+		if d.opts.ContentIDs {
+			item.Id = DeterministicID(key)
+		}
+		if !d.seen.addIfAbsent(key) && d.opts.Action == DedupSkip {
+			d.deduped++
+			continue
+		}
+		out = append(out, item)
+		positions = append(positions, i)
+	}
+	d.mu.Unlock()
+
+	if !d.opts.CheckExisting || d.opts.Action == DedupMerge || len(out) == 0 {
+		return out, positions, nil
+	}
+
+	ids := make([]string, len(out))
+	for i, item := range out {
+		ids[i] = item.Id
+	}
+	existing, err := index.Get(ctx, ids, []string{})
+	if err != nil {
+		return nil, nil, err
+	}
+	stored := make(map[string]struct{}, len(existing.Results))
+	for _, r := range existing.Results {
+		stored[r.Id] = struct{}{}
+	}
+
+	kept, keptPositions := out[:0], positions[:0]
+	skipped := 0
+	for i, item := range out {
+		if _, ok := stored[item.Id]; ok {
+			skipped++
+			continue
+		}
+		kept = append(kept, item)
+		keptPositions = append(keptPositions, positions[i])
+	}
+
+	d.mu.Lock()
+	d.deduped += skipped
+	d.mu.Unlock()
+	return kept, keptPositions, nil
+}
+
+// contentKey is the default dedup key: string contents, else the vector bytes.
+func contentKey(item VectorItem) []byte {
+	if text, ok := contentsText(item.Contents); ok {
+		return []byte(text)
+	}
+	buf := make([]byte, 4*len(item.Vector))
+	for i, f := range item.Vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
+
+// bloomFilter is a fixed-size Bloom filter using double hashing over SHA-256.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    int
+}
+
+// newBloomFilter sizes a filter for n items at false-positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// addIfAbsent adds key and reports whether it was (probably) absent before.
+func (b *bloomFilter) addIfAbsent(key []byte) bool {
+	sum := sha256.Sum256(key)
+	h1 := binary.LittleEndian.Uint64(sum[0:8])
+	h2 := binary.LittleEndian.Uint64(sum[8:16]) | 1
+
+	absent := false
+	for i := 0; i < b.k; i++ {
+		bit := (h1 + uint64(i)*h2) % b.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			absent = true
+			b.bits[word] |= mask
+		}
+	}
+	return absent
+}
@@ -0,0 +1,156 @@
+// deletebatched.go implements deleting large ID lists in concurrent batches.
+package cyborgdb
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// DefaultDeleteBatchSize is the default number of IDs per delete request
+	// during DeleteBatched.
+	DefaultDeleteBatchSize = 1000
+	// DefaultDeleteConcurrency is the default number of concurrent delete
+	// requests during DeleteBatched.
+	DefaultDeleteConcurrency = 4
+	// maxDeleteErrors caps the number of batch errors kept in a DeleteSummary.
+	maxDeleteErrors = 1000
+)
+
+// DeleteBatchOptions configures DeleteBatched. Zero values fall back to defaults.
+type DeleteBatchOptions struct {
+	// BatchSize is the number of IDs sent per delete request. Default: 1000.
+	BatchSize int
+
+	// Concurrency is the maximum number of delete requests in flight. Default: 4.
+	Concurrency int
+
+	// OnProgress, if set, is called after each batch with running totals.
+	// Calls are serialized; keep the callback fast.
+	OnProgress func(DeleteProgress)
+}
+
+// DeleteProgress reports the state of a DeleteBatched call.
+type DeleteProgress struct {
+	// Deleted is the number of IDs in batches the server accepted so far.
+	Deleted int
+	// Failed is the number of IDs in batches the server rejected so far.
+	Failed int
+	// Total is the number of IDs to delete.
+	Total int
+}
+
+// DeleteSummary reports the outcome of a DeleteBatched call.
+type DeleteSummary struct {
+	// Deleted is the number of IDs in batches the server accepted. IDs that
+	// did not exist are included, as for Delete.
+	Deleted int `json:"deleted"`
+	// Failed is the number of IDs in batches that failed or were aborted.
+	Failed int `json:"failed"`
+	// Skipped is the number of IDs in batches never sent because ctx was done.
+	Skipped int `json:"skipped,omitempty"`
+	// FailedIDs lists the IDs not known to be deleted (failed, aborted, and
+	// skipped batches), to retry with.
+	FailedIDs []string `json:"failed_ids,omitempty"`
+	// Errors lists the error of each failed or aborted batch (capped at 1000
+	// entries).
+	Errors []string `json:"errors,omitempty"`
+	// Batches reports every batch in order; see BatchState.
+	Batches []BatchResult `json:"batches"`
+}
+
+// DeleteBatched deletes ids in batches of DeleteBatchOptions.BatchSize,
+// with up to DeleteBatchOptions.Concurrency requests in flight, for ID lists
+// too large for a single Delete request.
+//
+// A rejected batch does not stop the others; its IDs are reported in the
+// summary so they can be retried. Batches are independent, so a failed or
+// canceled call may leave some IDs deleted and others not; Batches records
+// which (see BatchState).
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - ids: IDs to delete
+//   - opts: Batching, concurrency, and progress options
+//
+// Returns:
+//   - *DeleteSummary: Counts of deleted and failed IDs, with the failed IDs
+//   - error: ctx.Err() if the call was canceled before every batch was sent
+//
+// Example:
+//
+//	summary, err := index.DeleteBatched(ctx, staleIDs, cyborgdb.DeleteBatchOptions{
+//		OnProgress: func(p cyborgdb.DeleteProgress) {
+//			log.Printf("deleted %d/%d", p.Deleted+p.Failed, p.Total)
+//		},
+//	})
+//	if err == nil && summary.Failed > 0 {
+//		_, err = index.DeleteBatched(ctx, summary.FailedIDs, cyborgdb.DeleteBatchOptions{})
+//	}
+func (e *EncryptedIndex) DeleteBatched(ctx context.Context, ids []string, opts DeleteBatchOptions) (*DeleteSummary, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultDeleteBatchSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDeleteConcurrency
+	}
+
+	chunks := chunkIDs(ids, batchSize)
+	summary := &DeleteSummary{Batches: make([]BatchResult, len(chunks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	next := make(chan int)
+	for w := 0; w < concurrency && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				chunk := chunks[i]
+				err := e.Delete(ctx, chunk)
+				mu.Lock()
+				summary.Batches[i] = newBatchResult(i, i*batchSize, i*batchSize+len(chunk), batchState(err), err)
+				if err != nil {
+					summary.Failed += len(chunk)
+					if len(summary.Errors) < maxDeleteErrors {
+						summary.Errors = append(summary.Errors, err.Error())
+					}
+				} else {
+					summary.Deleted += len(chunk)
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(DeleteProgress{Deleted: summary.Deleted, Failed: summary.Failed, Total: len(ids)})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	sent := 0
+	var canceled error
+feed:
+	for ; sent < len(chunks); sent++ {
+		select {
+		case next <- sent:
+		case <-ctx.Done():
+			canceled = ctx.Err()
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	for i := sent; i < len(chunks); i++ {
+		summary.Batches[i] = newBatchResult(i, i*batchSize, i*batchSize+len(chunks[i]), BatchSkipped, canceled)
+		summary.Skipped += len(chunks[i])
+	}
+	for i, batch := range summary.Batches {
+		if batch.State != BatchCommitted {
+			summary.FailedIDs = append(summary.FailedIDs, chunks[i]...)
+		}
+	}
+	return summary, canceled
+}
@@ -0,0 +1,83 @@
+// deleteresult.go implements deletes that report which IDs were removed.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrIDsNotFound is returned by DeleteResult.Err when some IDs did not exist.
+var ErrIDsNotFound = errors.New("IDs not found")
+
+// DeleteResult reports what a delete removed.
+type DeleteResult struct {
+	// Deleted is the number of distinct IDs that existed and were removed.
+	Deleted int `json:"deleted"`
+	// NotFound lists the requested IDs that did not exist, in request order.
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// Err returns an error wrapping ErrIDsNotFound if any ID was missing, for
+// callers that treat drift between their records and the index as a failure.
+func (r *DeleteResult) Err() error {
+	if len(r.NotFound) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d IDs, e.g. %q", ErrIDsNotFound, len(r.NotFound), len(r.NotFound)+r.Deleted, r.NotFound[0])
+}
+
+// DeleteWithResult is Delete, additionally reporting how many IDs were
+// removed and which did not exist.
+//
+// The service deletes missing IDs silently, so existence is checked with one
+// extra read request before deleting (no item data is fetched). An ID written
+// or deleted concurrently between the check and the delete may be reported
+// in the wrong category.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - ids: IDs to delete
+//
+// Returns:
+//   - *DeleteResult: Removed count and missing IDs
+//   - error: Any error checking or deleting; missing IDs are not errors (see Err)
+//
+// Example:
+//
+//	res, err := index.DeleteWithResult(ctx, staleIDs)
+//	if err == nil && len(res.NotFound) > 0 {
+//		log.Printf("index out of sync: %d IDs already gone", len(res.NotFound))
+//	}
+func (e *EncryptedIndex) DeleteWithResult(ctx context.Context, ids []string) (*DeleteResult, error) {
+	result := &DeleteResult{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	// Request no fields; only the returned IDs matter.
+	existing, err := e.Get(ctx, ids, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing items: %w", err)
+	}
+	exists := make(map[string]bool, len(existing.Results))
+	for _, item := range existing.Results {
+		exists[item.Id] = true
+	}
+
+	if err := e.Delete(ctx, ids); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if exists[id] {
+			result.Deleted++
+		} else {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	return result, nil
+}
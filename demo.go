@@ -35,10 +35,20 @@ type DemoAPIKeyResponse struct {
 	ExpiresAt *int64 `json:"expiresAt,omitempty"`
 }
 
+// DemoAPIKey is a temporary demo API key and its expiry.
+type DemoAPIKey struct {
+	// Key is the API key.
+	Key string
+	// ExpiresAt is when the key expires, or the zero time if not reported.
+	ExpiresAt time.Time
+}
+
 // GetDemoAPIKey generates a temporary demo API key from the CyborgDB demo API service.
 //
 // This function generates a temporary API key that can be used for demo purposes.
 // The endpoint can be configured via the CYBORGDB_DEMO_ENDPOINT environment variable.
+// Use RequestDemoAPIKey to also learn when the key expires, or NewDemoClient
+// to have keys renewed automatically.
 //
 // Parameters:
 //   - description: Optional description for the demo API key.
@@ -56,6 +66,30 @@ type DemoAPIKeyResponse struct {
 //	}
 //	client, err := cyborgdb.NewClient("https://your-instance.com", demoKey)
 func GetDemoAPIKey(description string) (string, error) {
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDemoTimeout)
+	defer cancel()
+
+	key, err := RequestDemoAPIKey(ctx, description)
+	if err != nil {
+		return "", err
+	}
+	return key.Key, nil
+}
+
+// RequestDemoAPIKey generates a temporary demo API key and reports its expiry.
+//
+// The endpoint can be configured via the CYBORGDB_DEMO_ENDPOINT environment variable.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - description: Optional description for the demo API key.
+//     If empty, defaults to "Temporary demo API key"
+//
+// Returns:
+//   - *DemoAPIKey: The generated key and its expiry
+//   - error: Any error encountered during generation
+func RequestDemoAPIKey(ctx context.Context, description string) (*DemoAPIKey, error) {
 	// Use environment variable if set, otherwise use default endpoint
 	endpoint := os.Getenv("CYBORGDB_DEMO_ENDPOINT")
 	if endpoint == "" {
@@ -74,17 +108,13 @@ func GetDemoAPIKey(description string) (string, error) {
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal request payload: %w", err)
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultDemoTimeout)
-	defer cancel()
-
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payloadBytes))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
@@ -97,38 +127,76 @@ func GetDemoAPIKey(description string) (string, error) {
 	// Make the POST request
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate demo API key: %w", err)
+		return nil, fmt.Errorf("failed to generate demo API key: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Check if request was successful
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("%w with status %d: %s", ErrDemoAPIKeyGeneration, resp.StatusCode, string(body))
+		return nil, fmt.Errorf("%w with status %d: %s", ErrDemoAPIKeyGeneration, resp.StatusCode, string(body))
 	}
 
 	// Parse the response
 	var result DemoAPIKeyResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Validate the API key
 	if result.APIKey == "" {
-		return "", ErrDemoAPIKeyNotFound
+		return nil, ErrDemoAPIKeyNotFound
 	}
 
-	// Log expiration info if available
+	key := &DemoAPIKey{Key: result.APIKey}
 	if result.ExpiresAt != nil {
-		expiresAt := time.Unix(*result.ExpiresAt, 0)
-		timeLeft := time.Until(expiresAt).Round(time.Second)
-		fmt.Printf("Demo API key will expire in %s\n", timeLeft)
+		key.ExpiresAt = time.Unix(*result.ExpiresAt, 0)
+	}
+	return key, nil
+}
+
+// NewDemoClient constructs a client authenticated with demo API keys.
+//
+// A demo key is requested immediately, so an unreachable demo service is
+// reported here. A new key is requested shortly before the current one
+// expires, or when the server rejects it; Client.CredentialsExpiry reports the
+// current key's expiry.
+//
+// Parameters:
+//   - ctx: Context for the initial key request
+//   - baseURL: CyborgDB service URL
+//   - opts: Additional client options
+//
+// Returns:
+//   - *Client: A client that renews its demo key automatically
+//   - error: Any error encountered requesting the first key
+//
+// Example:
+//
+//	client, err := cyborgdb.NewDemoClient(ctx, "http://localhost:8000")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("demo key expires at", client.CredentialsExpiry())
+func NewDemoClient(ctx context.Context, baseURL string, opts ...ClientOption) (*Client, error) {
+	creds := NewRefreshingCredentials(func(ctx context.Context) (string, time.Time, error) {
+		ctx, cancel := context.WithTimeout(ctx, DefaultDemoTimeout)
+		defer cancel()
+
+		key, err := RequestDemoAPIKey(ctx, "")
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		return key.Key, key.ExpiresAt, nil
+	})
+	if _, err := creds.Token(ctx); err != nil {
+		return nil, err
 	}
 
-	return result.APIKey, nil
+	return NewClientWithOptions(baseURL, "", append(opts, WithCredentialsProvider(creds))...)
 }
@@ -0,0 +1,154 @@
+// dimension.go implements creating an index sized for an Embedder's output,
+// so the index dimension cannot drift from the embedding model.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DimensionProbeText is embedded to learn an Embedder's output dimension when
+// it does not implement DimensionReporter.
+const DimensionProbeText = "dimension probe"
+
+// ErrUnknownDimension is returned when an Embedder's output dimension cannot
+// be determined.
+var ErrUnknownDimension = errors.New("cannot determine embedding dimension")
+
+// DimensionReporter is implemented by Embedders that know their output
+// dimension without embedding anything. Dimension returns 0 if unknown.
+type DimensionReporter interface {
+	Dimension() int
+}
+
+// EmbedderIndexOptions configures CreateIndexForEmbedder. Zero values fall
+// back to defaults.
+type EmbedderIndexOptions struct {
+	// IndexType is "ivf", "ivfflat", or "ivfpq". Default: "ivfflat".
+	IndexType string
+
+	// PQDim and PQBits configure an "ivfpq" index. Default: a PQDim of an
+	// eighth of the dimension, and 8 bits.
+	PQDim  int32
+	PQBits int32
+
+	// Metric is the distance metric, e.g. "cosine". Default: the server's.
+	Metric string
+
+	// EmbeddingModel is recorded with the index. Default: the Embedder's
+	// Model(), if it has that method.
+	EmbeddingModel string
+
+	// KeyProvider resolves the index key when key is empty.
+	KeyProvider KeyProvider
+
+	// Labels tag the index in the index catalog. See CreateIndexParams.
+	Labels map[string]string
+}
+
+// EmbedderDimension returns the length of the vectors embedder produces:
+// its Dimension() if it implements DimensionReporter and knows it, otherwise
+// the length of the embedding of DimensionProbeText.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts of the probe
+//   - embedder: The Embedder to measure
+//
+// Returns:
+//   - int: The embedding dimension
+//   - error: ErrUnknownDimension, or the probe's embedding error
+func EmbedderDimension(ctx context.Context, embedder Embedder) (int, error) {
+	if embedder == nil {
+		return 0, fmt.Errorf("%w: nil embedder", ErrUnknownDimension)
+	}
+	if r, ok := embedder.(DimensionReporter); ok {
+		if d := r.Dimension(); d > 0 {
+			return d, nil
+		}
+	}
+	vectors, err := embedTexts(ctx, embedder, []string{DimensionProbeText})
+	if err != nil {
+		return 0, err
+	}
+	if len(vectors[0]) == 0 {
+		return 0, fmt.Errorf("%w: embedder returned an empty vector", ErrUnknownDimension)
+	}
+	return len(vectors[0]), nil
+}
+
+// CreateIndexForEmbedder creates an index whose dimension matches the
+// output of embedder, probing it if needed (see EmbedderDimension), and
+// attaches embedder to the returned index. Use it instead of a hard-coded
+// dimension constant that drifts when the model changes.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - name: Name of the index to create
+//   - key: 32-byte index key; may be empty when opts.KeyProvider is set
+//   - embedder: The Embedder that will produce the index's vectors
+//   - opts: Index type, metric, and other creation options
+//
+// Returns:
+//   - *EncryptedIndex: Handle with embedder attached
+//   - error: Any error probing the embedder or creating the index
+//
+// Example:
+//
+//	embedder, _ := openai.NewEmbedder(openai.Config{APIKey: apiKey, Model: "text-embedding-3-large"})
+//	index, err := client.CreateIndexForEmbedder(ctx, "docs", key, embedder,
+//		cyborgdb.EmbedderIndexOptions{Metric: "cosine"})
+func (c *Client) CreateIndexForEmbedder(ctx context.Context, name string, key []byte, embedder Embedder, opts EmbedderIndexOptions) (*EncryptedIndex, error) {
+	dimension, err := EmbedderDimension(ctx, embedder)
+	if err != nil {
+		return nil, err
+	}
+	config, err := embedderIndexConfig(int32(dimension), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &CreateIndexParams{
+		IndexName:   name,
+		IndexKey:    key,
+		KeyProvider: opts.KeyProvider,
+		IndexConfig: config,
+		Embedder:    embedder,
+		Labels:      opts.Labels,
+	}
+	if opts.Metric != "" {
+		params.Metric = &opts.Metric
+	}
+	model := opts.EmbeddingModel
+	if m, ok := embedder.(interface{ Model() string }); ok && model == "" {
+		model = m.Model()
+	}
+	if model != "" {
+		params.EmbeddingModel = &model
+	}
+	return c.CreateIndex(ctx, params)
+}
+
+// embedderIndexConfig returns the index configuration for dimension.
+func embedderIndexConfig(dimension int32, opts EmbedderIndexOptions) (IndexModel, error) {
+	switch opts.IndexType {
+	case "", "ivfflat":
+		return IndexIVFFlat(dimension), nil
+	case "ivf":
+		return IndexIVF(dimension), nil
+	case "ivfpq":
+		pqDim, pqBits := opts.PQDim, opts.PQBits
+		if pqDim == 0 {
+			if dimension%8 != 0 {
+				return nil, fmt.Errorf("dimension %d is not divisible by 8; set PQDim", dimension)
+			}
+			pqDim = dimension / 8
+		}
+		if pqBits == 0 {
+			pqBits = 8
+		}
+		return IndexIVFPQ(dimension, pqDim, pqBits), nil
+	default:
+		return nil, fmt.Errorf("unsupported index type %q", opts.IndexType)
+	}
+}
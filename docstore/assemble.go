@@ -0,0 +1,358 @@
+package docstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// DefaultSeparator joins consecutive chunks whose source offsets are unknown
+// or leave a gap between them.
+const DefaultSeparator = "\n"
+
+// AssembleOptions configures Store.Assemble.
+type AssembleOptions struct {
+	// Neighbors is the number of chunks before and after each matched chunk
+	// added for context. Default: 0, only matched chunks.
+	Neighbors int
+
+	// MaxPassages limits the number of passages returned. Default: all.
+	MaxPassages int
+
+	// Separator joins chunks that cannot be stitched by their source
+	// offsets. Default: DefaultSeparator.
+	Separator string
+}
+
+// Citation identifies the source span of a Passage.
+type Citation struct {
+	// DocID is the document the passage comes from.
+	DocID string `json:"doc_id"`
+
+	// Source is the document's Source, if one was recorded.
+	Source string `json:"source,omitempty"`
+
+	// FirstChunk and LastChunk are the indexes of the passage's first and
+	// last chunks within the document, or -1 for a result that is not a
+	// chunk.
+	FirstChunk int `json:"first_chunk"`
+	LastChunk  int `json:"last_chunk"`
+
+	// Start and End are the byte offsets of the passage within the
+	// document text, or -1 if the chunks carry no offsets.
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// String formats the citation as "source, chunks 2-4", using the document
+// ID when there is no source and omitting chunks for a non-chunk result.
+func (c Citation) String() string {
+	name := c.Source
+	if name == "" {
+		name = c.DocID
+	}
+	if c.FirstChunk < 0 {
+		return name
+	}
+	if c.FirstChunk == c.LastChunk {
+		return fmt.Sprintf("%s, chunk %d", name, c.FirstChunk)
+	}
+	return fmt.Sprintf("%s, chunks %d-%d", name, c.FirstChunk, c.LastChunk)
+}
+
+// Passage is a run of consecutive chunks of one document, stitched back into
+// contiguous text.
+type Passage struct {
+	// Text is the passage text.
+	Text string `json:"text"`
+
+	// Citation locates the passage in its document.
+	Citation Citation `json:"citation"`
+
+	// ChunkIDs are the IDs of the passage's chunks, in document order.
+	ChunkIDs []string `json:"chunk_ids"`
+
+	// Hits are the query results that fell in this passage, closest first.
+	Hits []cyborgdb.QueryResult `json:"hits"`
+
+	// Distance and Score are those of the passage's closest hit.
+	Distance float32 `json:"distance"`
+	Score    float32 `json:"score"`
+}
+
+// chunkRef locates a chunk within its document.
+type chunkRef struct {
+	docID string
+	index int
+}
+
+// storedChunk is a chunk fetched back from the index.
+type storedChunk struct {
+	id         string
+	text       string
+	source     string
+	start, end int
+}
+
+// Assemble turns chunk-level query results into passages: hits are grouped
+// by document, each widened by opts.Neighbors chunks on either side, and
+// consecutive chunks merged into one passage whose text is stitched using
+// the chunks' source offsets, so overlap between chunks is not repeated.
+//
+// Chunks are identified by their doc_id and chunk_index metadata or, when
+// hits carry no metadata, by their ChunkID. Results that are not chunks
+// written by ChunkAndUpsert are returned as single-chunk passages. Chunk
+// text is fetched with one Get call.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - hits: Query results, closest first
+//   - opts: Neighbor window, passage limit, and separator
+//
+// Returns:
+//   - []Passage: Passages ordered by their closest hit
+//   - error: Any error fetching chunk contents
+//
+// Example:
+//
+//	hits, _ := index.QueryOne(ctx, vec, cyborgdb.WithTopK(10))
+//	passages, err := store.Assemble(ctx, hits, docstore.AssembleOptions{Neighbors: 1})
+//	for _, p := range passages {
+//		fmt.Printf("[%s]\n%s\n", p.Citation, p.Text)
+//	}
+func (s *Store) Assemble(ctx context.Context, hits []cyborgdb.QueryResult, opts AssembleOptions) ([]Passage, error) {
+	if opts.Neighbors < 0 {
+		opts.Neighbors = 0
+	}
+	if opts.Separator == "" {
+		opts.Separator = DefaultSeparator
+	}
+
+	var (
+		docOrder []string
+		matched  = make(map[string]map[int][]cyborgdb.QueryResult)
+		counts   = make(map[string]int)
+		loose    []cyborgdb.QueryResult
+	)
+	for _, hit := range hits {
+		ref, ok := chunkOf(hit)
+		if !ok {
+			loose = append(loose, hit)
+			continue
+		}
+		if matched[ref.docID] == nil {
+			matched[ref.docID] = make(map[int][]cyborgdb.QueryResult)
+			docOrder = append(docOrder, ref.docID)
+		}
+		matched[ref.docID][ref.index] = append(matched[ref.docID][ref.index], hit)
+		if n, ok := metaInt(hit.Metadata, MetaChunkCount); ok {
+			counts[ref.docID] = n
+		}
+	}
+
+	// Fetch every matched chunk and its neighbors in one call.
+	var ids []string
+	wanted := make(map[string]bool)
+	want := func(id string) {
+		if !wanted[id] {
+			wanted[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, docID := range docOrder {
+		for index := range matched[docID] {
+			for i := index - opts.Neighbors; i <= index+opts.Neighbors; i++ {
+				if i < 0 || (counts[docID] > 0 && i >= counts[docID]) {
+					continue
+				}
+				want(ChunkID(docID, i))
+			}
+		}
+	}
+	for _, hit := range loose {
+		want(hit.ID)
+	}
+	stored := make(map[string]storedChunk, len(ids))
+	if len(ids) > 0 {
+		resp, err := s.index.Get(ctx, ids, []string{"contents", "metadata"})
+		if err != nil {
+			return nil, fmt.Errorf("docstore: failed to fetch chunks: %w", err)
+		}
+		for _, item := range resp.Results {
+			chunk := storedChunk{id: item.Id, start: -1, end: -1}
+			chunk.text, _ = cyborgdb.ContentsText(item.Contents)
+			chunk.source, _ = item.Metadata[MetaSource].(string)
+			if start, ok := metaInt(item.Metadata, MetaChunkStart); ok {
+				if end, ok := metaInt(item.Metadata, MetaChunkEnd); ok {
+					chunk.start, chunk.end = start, end
+				}
+			}
+			stored[item.Id] = chunk
+		}
+	}
+
+	var passages []Passage
+	for _, docID := range docOrder {
+		var present []int
+		for id := range wanted {
+			ref, ok := parseChunkID(id)
+			if ok && ref.docID == docID {
+				if _, ok := stored[id]; ok {
+					present = append(present, ref.index)
+				}
+			}
+		}
+		// A matched chunk missing from the index (e.g. deleted since the
+		// query) still yields a passage, with empty text.
+		for index := range matched[docID] {
+			if _, ok := stored[ChunkID(docID, index)]; !ok {
+				present = append(present, index)
+			}
+		}
+		sort.Ints(present)
+
+		for start := 0; start < len(present); {
+			end := start + 1
+			for end < len(present) && present[end] == present[end-1]+1 {
+				end++
+			}
+			if p, ok := s.passage(docID, present[start:end], matched[docID], stored, opts.Separator); ok {
+				passages = append(passages, p)
+			}
+			start = end
+		}
+	}
+	for _, hit := range loose {
+		chunk := stored[hit.ID]
+		passages = append(passages, Passage{
+			Text:     chunk.text,
+			Citation: Citation{DocID: hit.ID, Source: chunk.source, FirstChunk: -1, LastChunk: -1, Start: -1, End: -1},
+			ChunkIDs: []string{hit.ID},
+			Hits:     []cyborgdb.QueryResult{hit},
+			Distance: hit.Distance,
+			Score:    hit.Score,
+		})
+	}
+
+	// Hits arrive closest first, so ordering passages by the position of
+	// their first hit keeps the query's ranking.
+	rank := make(map[string]int, len(hits))
+	for i := len(hits) - 1; i >= 0; i-- {
+		rank[hits[i].ID] = i
+	}
+	sort.SliceStable(passages, func(i, j int) bool {
+		return rank[passages[i].Hits[0].ID] < rank[passages[j].Hits[0].ID]
+	})
+	if opts.MaxPassages > 0 && len(passages) > opts.MaxPassages {
+		passages = passages[:opts.MaxPassages]
+	}
+	return passages, nil
+}
+
+// passage stitches the consecutive chunks indexes of docID into a Passage.
+// It reports false for a run with no matched chunk.
+func (s *Store) passage(docID string, indexes []int, matched map[int][]cyborgdb.QueryResult, stored map[string]storedChunk, separator string) (Passage, bool) {
+	p := Passage{Citation: Citation{
+		DocID:      docID,
+		FirstChunk: indexes[0],
+		LastChunk:  indexes[len(indexes)-1],
+		Start:      -1,
+		End:        -1,
+	}}
+	var (
+		text    strings.Builder
+		prevEnd = -1
+	)
+	for _, index := range indexes {
+		id := ChunkID(docID, index)
+		p.ChunkIDs = append(p.ChunkIDs, id)
+		p.Hits = append(p.Hits, matched[index]...)
+
+		chunk, ok := stored[id]
+		if !ok {
+			prevEnd = -1
+			continue
+		}
+		if p.Citation.Source == "" {
+			p.Citation.Source = chunk.source
+		}
+		switch {
+		case text.Len() == 0:
+			text.WriteString(chunk.text)
+		case prevEnd >= 0 && chunk.start >= 0 && chunk.start <= prevEnd:
+			// Overlapping chunks: append only the part past the previous one.
+			if skip := prevEnd - chunk.start; skip < len(chunk.text) {
+				text.WriteString(chunk.text[skip:])
+			}
+		default:
+			text.WriteString(separator)
+			text.WriteString(chunk.text)
+		}
+		if chunk.start >= 0 {
+			if p.Citation.Start < 0 {
+				p.Citation.Start = chunk.start
+			}
+			if chunk.end > p.Citation.End {
+				p.Citation.End = chunk.end
+			}
+			if chunk.end > prevEnd {
+				prevEnd = chunk.end
+			}
+		} else {
+			prevEnd = -1
+		}
+	}
+	if len(p.Hits) == 0 {
+		return Passage{}, false
+	}
+	sort.SliceStable(p.Hits, func(i, j int) bool { return p.Hits[i].Distance < p.Hits[j].Distance })
+	p.Text = text.String()
+	p.Distance, p.Score = p.Hits[0].Distance, p.Hits[0].Score
+	return p, true
+}
+
+// chunkOf identifies the chunk a hit belongs to from its metadata, falling
+// back to parsing its ID.
+func chunkOf(hit cyborgdb.QueryResult) (chunkRef, bool) {
+	if docID, ok := hit.Metadata[MetaDocID].(string); ok {
+		if index, ok := metaInt(hit.Metadata, MetaChunkIndex); ok {
+			return chunkRef{docID: docID, index: index}, true
+		}
+	}
+	return parseChunkID(hit.ID)
+}
+
+// parseChunkID reverses ChunkID.
+func parseChunkID(id string) (chunkRef, bool) {
+	i := strings.LastIndex(id, "#chunk-")
+	if i < 0 {
+		return chunkRef{}, false
+	}
+	index, err := strconv.Atoi(id[i+len("#chunk-"):])
+	if err != nil || index < 0 {
+		return chunkRef{}, false
+	}
+	return chunkRef{docID: id[:i], index: index}, true
+}
+
+// metaInt reads an integer metadata value, which arrives as float64 when
+// decoded from JSON.
+func metaInt(metadata map[string]interface{}, key string) (int, bool) {
+	switch v := metadata[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), v == float64(int(v))
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package docstore
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Chunk is a contiguous span of a document's text.
+type Chunk struct {
+	// Text is the chunk contents.
+	Text string
+
+	// Start is the byte offset of the chunk within the source text.
+	Start int
+
+	// End is the byte offset one past the end of the chunk.
+	End int
+}
+
+// Chunker splits text into chunks.
+type Chunker interface {
+	// Chunk splits text into ordered, possibly overlapping chunks.
+	Chunk(text string) []Chunk
+}
+
+// FixedSizeChunker splits text into windows of at most Size characters that
+// overlap by Overlap characters. Where possible, a window is shortened to end
+// at whitespace so words are not split across chunks.
+type FixedSizeChunker struct {
+	// Size is the maximum chunk length in characters (runes). Default: 1000.
+	Size int
+
+	// Overlap is the number of characters shared by consecutive chunks.
+	// Must be smaller than Size. Default: 0.
+	Overlap int
+}
+
+const defaultChunkSize = 1000
+
+// Chunk implements Chunker.
+func (c FixedSizeChunker) Chunk(text string) []Chunk {
+	size := c.Size
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	overlap := c.Overlap
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	// Byte offset of every rune, plus len(text), so windows can be cut in runes.
+	offsets := make([]int, 0, utf8.RuneCountInString(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+	numRunes := len(offsets) - 1
+
+	var chunks []Chunk
+	for start := 0; start < numRunes; {
+		end := start + size
+		if end >= numRunes {
+			end = numRunes
+		} else if cut := lastSpace(text, offsets, start+overlap+1, end); cut > 0 {
+			end = cut
+		}
+
+		chunk := Chunk{Text: text[offsets[start]:offsets[end]], Start: offsets[start], End: offsets[end]}
+		if trimmed := trimSpace(chunk); trimmed.Text != "" {
+			chunks = append(chunks, trimmed)
+		}
+
+		if end == numRunes {
+			break
+		}
+		start = end - overlap
+	}
+	return chunks
+}
+
+// lastSpace returns the rune index just after the last whitespace rune in
+// [lo, hi), or 0 if there is none.
+func lastSpace(text string, offsets []int, lo, hi int) int {
+	for i := hi - 1; i >= lo; i-- {
+		r, _ := utf8.DecodeRuneInString(text[offsets[i]:])
+		if unicode.IsSpace(r) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// trimSpace removes leading and trailing whitespace, adjusting offsets.
+func trimSpace(c Chunk) Chunk {
+	for c.Text != "" {
+		r, n := utf8.DecodeRuneInString(c.Text)
+		if !unicode.IsSpace(r) {
+			break
+		}
+		c.Text = c.Text[n:]
+		c.Start += n
+	}
+	for c.Text != "" {
+		r, n := utf8.DecodeLastRuneInString(c.Text)
+		if !unicode.IsSpace(r) {
+			break
+		}
+		c.Text = c.Text[:len(c.Text)-n]
+		c.End -= n
+	}
+	return c
+}
@@ -0,0 +1,188 @@
+// Package docstore provides document chunking and ingestion helpers for
+// retrieval-augmented generation (RAG) workflows on top of an EncryptedIndex.
+//
+// Chunk text is stored in each item's Contents and provenance in its
+// Metadata. Vectors are produced by the index's Embedder, if one is attached,
+// or by the server-side embedding model configured for the index.
+package docstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// DefaultBatchSize is the default number of chunks upserted per request.
+const DefaultBatchSize = 100
+
+// Metadata keys written on every chunk.
+const (
+	MetaDocID      = "doc_id"
+	MetaSource     = "source"
+	MetaChunkIndex = "chunk_index"
+	MetaChunkCount = "chunk_count"
+	MetaChunkStart = "chunk_start"
+	MetaChunkEnd   = "chunk_end"
+)
+
+// ErrMissingDocumentID is returned when a Document has no ID.
+var ErrMissingDocumentID = errors.New("docstore: document ID is required")
+
+// Document is a piece of text to be chunked and ingested.
+type Document struct {
+	// ID uniquely identifies the document; chunk IDs are derived from it.
+	ID string
+
+	// Text is the full document text.
+	Text string
+
+	// Source optionally records where the document came from (path, URL, ...).
+	Source string
+}
+
+// Store ingests documents into an EncryptedIndex.
+type Store struct {
+	index     *cyborgdb.EncryptedIndex
+	batchSize int
+}
+
+// New returns a Store that writes to index. A batchSize of 0 uses DefaultBatchSize.
+func New(index *cyborgdb.EncryptedIndex, batchSize int) *Store {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Store{index: index, batchSize: batchSize}
+}
+
+// ChunkID returns the stable ID of the i-th chunk of a document.
+//
+// IDs depend only on the document ID and chunk position, so re-ingesting an
+// updated document overwrites its previous chunks in place, and
+// ChunkAndUpsert deletes any left over when the document shrinks.
+func ChunkID(docID string, i int) string {
+	return fmt.Sprintf("%s#chunk-%d", docID, i)
+}
+
+// ChunkAndUpsert splits doc into chunks, attaches provenance metadata, and
+// upserts them in batches. When the document was ingested before with more
+// chunks, the surplus chunks are deleted once the new ones are stored.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - doc: The document to ingest
+//   - chunker: Strategy used to split the text (e.g. FixedSizeChunker)
+//   - metadata: Extra metadata copied onto every chunk (may be nil)
+//
+// Returns:
+//   - []string: IDs of the upserted chunks, in document order
+//   - error: Any error encountered; chunks in earlier batches may already be stored
+func (s *Store) ChunkAndUpsert(
+	ctx context.Context,
+	doc Document,
+	chunker Chunker,
+	metadata map[string]interface{},
+) ([]string, error) {
+	if doc.ID == "" {
+		return nil, ErrMissingDocumentID
+	}
+
+	previous, err := s.storedChunkCount(ctx, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := chunker.Chunk(doc.Text)
+	items := make([]cyborgdb.VectorItem, len(chunks))
+	ids := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		meta := make(map[string]interface{}, len(metadata)+6)
+		for k, v := range metadata {
+			meta[k] = v
+		}
+		meta[MetaDocID] = doc.ID
+		meta[MetaChunkIndex] = i
+		meta[MetaChunkCount] = len(chunks)
+		meta[MetaChunkStart] = chunk.Start
+		meta[MetaChunkEnd] = chunk.End
+		if doc.Source != "" {
+			meta[MetaSource] = doc.Source
+		}
+
+		ids[i] = ChunkID(doc.ID, i)
+		items[i] = cyborgdb.VectorItem{
+			Id:       ids[i],
+			Contents: cyborgdb.TextContents(chunk.Text),
+			Metadata: meta,
+		}
+	}
+
+	for start := 0; start < len(items); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		if err := s.index.Upsert(ctx, items[start:end]); err != nil {
+			return nil, fmt.Errorf("docstore: failed to upsert chunks %d-%d of %q: %w", start, end-1, doc.ID, err)
+		}
+	}
+
+	if err := s.deleteChunks(ctx, doc.ID, len(chunks), previous); err != nil {
+		return ids, err
+	}
+	return ids, nil
+}
+
+// DeleteDocument deletes every chunk of the document with the given ID.
+// Deleting a document that was never ingested is not an error.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - docID: ID of the document to delete
+//
+// Returns:
+//   - error: Any error encountered; some chunks may already be deleted
+func (s *Store) DeleteDocument(ctx context.Context, docID string) error {
+	if docID == "" {
+		return ErrMissingDocumentID
+	}
+	count, err := s.storedChunkCount(ctx, docID)
+	if err != nil {
+		return err
+	}
+	return s.deleteChunks(ctx, docID, 0, count)
+}
+
+// storedChunkCount returns the chunk count recorded on the first stored
+// chunk of a document, or 0 if it has none.
+func (s *Store) storedChunkCount(ctx context.Context, docID string) (int, error) {
+	resp, err := s.index.Get(ctx, []string{ChunkID(docID, 0)}, []string{"metadata"})
+	if err != nil {
+		return 0, fmt.Errorf("docstore: failed to read chunks of %q: %w", docID, err)
+	}
+	for _, item := range resp.Results {
+		if count, ok := metaInt(item.Metadata, MetaChunkCount); ok {
+			return count, nil
+		}
+	}
+	return 0, nil
+}
+
+// deleteChunks deletes chunks [from, to) of a document in batches.
+func (s *Store) deleteChunks(ctx context.Context, docID string, from, to int) error {
+	for start := from; start < to; start += s.batchSize {
+		end := start + s.batchSize
+		if end > to {
+			end = to
+		}
+		ids := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			ids = append(ids, ChunkID(docID, i))
+		}
+		if err := s.index.Delete(ctx, ids); err != nil {
+			return fmt.Errorf("docstore: failed to delete chunks %d-%d of %q: %w", start, end-1, docID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package docstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// newTestStore returns a Store backed by an in-memory index served over
+// HTTP, and a function listing the stored IDs.
+func newTestStore(t *testing.T) (*Store, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	items := map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req struct {
+			Items []map[string]interface{} `json:"items"`
+			Ids   []string                 `json:"ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var resp interface{} = map[string]string{"status": "success", "message": "ok"}
+		switch r.URL.Path {
+		case "/v1/indexes/describe":
+			resp = map[string]interface{}{"index_name": "docs", "index_type": "ivfflat", "is_trained": false,
+				"index_config": map[string]interface{}{"type": "ivfflat", "dimension": 2}}
+		case "/v1/vectors/upsert":
+			for _, item := range req.Items {
+				items[item["id"].(string)] = item
+			}
+		case "/v1/vectors/delete":
+			for _, id := range req.Ids {
+				delete(items, id)
+			}
+		case "/v1/vectors/get":
+			results := []map[string]interface{}{}
+			for _, id := range req.Ids {
+				if item, ok := items[id]; ok {
+					results = append(results, item)
+				}
+			}
+			resp = map[string]interface{}{"results": results}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := cyborgdb.NewClient(srv.URL, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := client.LoadIndex(context.Background(), "docs", make([]byte, cyborgdb.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(index, 2), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		ids := make([]string, 0, len(items))
+		for id := range items {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+}
+
+func TestChunkAndUpsertDeletesSurplusChunks(t *testing.T) {
+	store, stored := newTestStore(t)
+	ctx := context.Background()
+	chunker := FixedSizeChunker{Size: 10}
+
+	if _, err := store.ChunkAndUpsert(ctx, Document{ID: "d", Text: strings.Repeat("x", 50)}, chunker, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(stored()); n != 5 {
+		t.Fatalf("stored %d chunks, want 5", n)
+	}
+	ids, err := store.ChunkAndUpsert(ctx, Document{ID: "d", Text: strings.Repeat("x", 20)}, chunker, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stored(); !reflect.DeepEqual(got, ids) {
+		t.Errorf("stored chunks after shrinking = %v, want %v", got, ids)
+	}
+
+	if err := store.DeleteDocument(ctx, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if got := stored(); len(got) != 0 {
+		t.Errorf("chunks left after DeleteDocument: %v", got)
+	}
+	if err := store.DeleteDocument(ctx, "missing"); err != nil {
+		t.Errorf("DeleteDocument of an unknown document: %v", err)
+	}
+}
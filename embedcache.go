@@ -0,0 +1,398 @@
+// embedcache.go implements an Embedder wrapper that caches embeddings by
+// text, so repeated query texts are not re-embedded on every call.
+package cyborgdb
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultEmbeddingCacheSize is the number of embeddings kept in memory
+	// when EmbeddingCacheOptions.Size is not set.
+	DefaultEmbeddingCacheSize = 10000
+	// DefaultEmbeddingCacheTTL is the entry lifetime when
+	// EmbeddingCacheOptions.TTL is not set.
+	DefaultEmbeddingCacheTTL = 24 * time.Hour
+)
+
+// EmbeddingStore is a persistent second-level store behind a CachedEmbedder,
+// shared across processes or restarts. Keys are hex strings safe for use as
+// file names or database keys. Implementations must be safe for concurrent
+// use.
+type EmbeddingStore interface {
+	// Get returns the vector stored under key, or ok == false if there is
+	// none or it has expired.
+	Get(ctx context.Context, key string) (vector []float32, ok bool, err error)
+	// Put stores vector under key until expires.
+	Put(ctx context.Context, key string, vector []float32, expires time.Time) error
+}
+
+// EmbeddingCacheOptions configures NewCachedEmbedder. Zero values fall back
+// to defaults.
+type EmbeddingCacheOptions struct {
+	// Size is the number of embeddings kept in memory, least recently used
+	// first out. Default: DefaultEmbeddingCacheSize.
+	Size int
+
+	// TTL is how long an embedding is reused. Default: DefaultEmbeddingCacheTTL.
+	TTL time.Duration
+
+	// Namespace separates the entries of different models sharing a Store.
+	// Default: the wrapped Embedder's Model(), if it has that method.
+	Namespace string
+
+	// Store, if set, is consulted on memory misses and written on every
+	// embedding. Store errors are counted in Stats and otherwise ignored:
+	// a failing store degrades to re-embedding, never to a failed call.
+	Store EmbeddingStore
+
+	// QueriesOnly limits caching to the QueryContents of EncryptedIndex
+	// queries; other calls, such as embedding upserted Contents, pass
+	// straight through so documents do not evict query embeddings.
+	QueriesOnly bool
+}
+
+// EmbeddingCacheStats reports cache effectiveness.
+type EmbeddingCacheStats struct {
+	// Hits is the number of texts answered from memory.
+	Hits int64
+	// StoreHits is the number of texts answered from the Store.
+	StoreHits int64
+	// Misses is the number of texts sent to the wrapped Embedder.
+	Misses int64
+	// Evictions is the number of entries dropped to stay within Size.
+	Evictions int64
+	// StoreErrors is the number of failed Store reads and writes.
+	StoreErrors int64
+}
+
+// CachedEmbedder wraps an Embedder with an in-memory LRU cache with
+// per-entry expiry and an optional persistent EmbeddingStore. It is safe for
+// concurrent use if the wrapped Embedder is.
+type CachedEmbedder struct {
+	embedder  Embedder
+	opts      EmbeddingCacheOptions
+	namespace string
+
+	mu      sync.Mutex
+	lru     *list.List // of *embeddingEntry, most recent first
+	entries map[string]*list.Element
+	stats   EmbeddingCacheStats
+}
+
+type embeddingEntry struct {
+	key     string
+	vector  []float32
+	expires time.Time
+}
+
+// Compile-time checks that CachedEmbedder passes through the optional
+// Embedder interfaces.
+var (
+	_ Embedder          = (*CachedEmbedder)(nil)
+	_ DimensionReporter = (*CachedEmbedder)(nil)
+)
+
+// NewCachedEmbedder returns embedder wrapped with an embedding cache.
+//
+// Parameters:
+//   - embedder: The Embedder whose results are cached
+//   - opts: Cache size, TTL, persistent store, and scope
+//
+// Returns:
+//   - *CachedEmbedder: The caching Embedder
+//
+// Example:
+//
+//	embedder, _ := openai.NewEmbedder(openai.Config{APIKey: apiKey})
+//	index.SetEmbedder(cyborgdb.NewCachedEmbedder(embedder,
+//		cyborgdb.EmbeddingCacheOptions{QueriesOnly: true}))
+//	results, err := index.Query(ctx, cyborgdb.QueryParams{QueryContents: &question})
+func NewCachedEmbedder(embedder Embedder, opts EmbeddingCacheOptions) *CachedEmbedder {
+	if opts.Size <= 0 {
+		opts.Size = DefaultEmbeddingCacheSize
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultEmbeddingCacheTTL
+	}
+	namespace := opts.Namespace
+	if m, ok := embedder.(interface{ Model() string }); ok && namespace == "" {
+		namespace = m.Model()
+	}
+	return &CachedEmbedder{
+		embedder:  embedder,
+		opts:      opts,
+		namespace: namespace,
+		lru:       list.New(),
+		entries:   make(map[string]*list.Element),
+	}
+}
+
+// Model returns the wrapped Embedder's Model(), or "" if it has none.
+func (c *CachedEmbedder) Model() string {
+	if m, ok := c.embedder.(interface{ Model() string }); ok {
+		return m.Model()
+	}
+	return ""
+}
+
+// Dimension returns the wrapped Embedder's Dimension(), or 0 if it does not
+// implement DimensionReporter.
+func (c *CachedEmbedder) Dimension() int {
+	if r, ok := c.embedder.(DimensionReporter); ok {
+		return r.Dimension()
+	}
+	return 0
+}
+
+// Stats returns running totals since creation.
+func (c *CachedEmbedder) Stats() EmbeddingCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Purge drops all in-memory entries. The Store is left untouched.
+func (c *CachedEmbedder) Purge() {
+	c.mu.Lock()
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+	c.mu.Unlock()
+}
+
+// Embed returns one embedding per text, embedding only the texts not found
+// in memory or the Store; duplicate texts within a call are embedded once.
+// Returned vectors are copies and may be modified.
+func (c *CachedEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if c.opts.QueriesOnly && !isQueryEmbedding(ctx) {
+		return c.embedder.Embed(ctx, texts)
+	}
+
+	out := make([][]float32, len(texts))
+	var (
+		missKeys  []string
+		missTexts []string
+		missing   = make(map[string][]int)
+	)
+	for i, text := range texts {
+		key := c.key(text)
+		if positions, ok := missing[key]; ok {
+			missing[key] = append(positions, i)
+			continue
+		}
+		if vector, ok := c.lookup(ctx, key); ok {
+			out[i] = vector
+			continue
+		}
+		missing[key] = []int{i}
+		missKeys = append(missKeys, key)
+		missTexts = append(missTexts, text)
+	}
+	if len(missTexts) == 0 {
+		return out, nil
+	}
+
+	c.mu.Lock()
+	c.stats.Misses += int64(len(missTexts))
+	c.mu.Unlock()
+
+	vectors, err := c.embedder.Embed(ctx, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(missTexts) {
+		return nil, fmt.Errorf("%w: got %d for %d texts", ErrEmbeddingCountMismatch, len(vectors), len(missTexts))
+	}
+	expires := time.Now().Add(c.opts.TTL)
+	for i, key := range missKeys {
+		c.put(key, vectors[i], expires)
+		if c.opts.Store != nil {
+			if err := c.opts.Store.Put(ctx, key, vectors[i], expires); err != nil {
+				c.storeError()
+			}
+		}
+		for _, pos := range missing[key] {
+			out[pos] = append([]float32(nil), vectors[i]...)
+		}
+	}
+	return out, nil
+}
+
+// key derives the cache key of text in this cache's namespace.
+func (c *CachedEmbedder) key(text string) string {
+	h := sha256.New()
+	io.WriteString(h, c.namespace)
+	h.Write([]byte{0})
+	io.WriteString(h, text)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lookup returns a copy of the vector under key from memory or the Store,
+// promoting Store hits into memory.
+func (c *CachedEmbedder) lookup(ctx context.Context, key string) ([]float32, bool) {
+	now := time.Now()
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*embeddingEntry)
+		if now.Before(entry.expires) {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			vector := append([]float32(nil), entry.vector...)
+			c.mu.Unlock()
+			return vector, true
+		}
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	if c.opts.Store == nil {
+		return nil, false
+	}
+	vector, ok, err := c.opts.Store.Get(ctx, key)
+	if err != nil {
+		c.storeError()
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	// The Store does not report the remaining lifetime, so memory keeps a
+	// Store hit for at most a full TTL; the Store remains the authority.
+	c.put(key, vector, now.Add(c.opts.TTL))
+	c.mu.Lock()
+	c.stats.StoreHits++
+	c.mu.Unlock()
+	return append([]float32(nil), vector...), true
+}
+
+// put stores vector in memory, evicting the least recently used entries
+// beyond Size.
+func (c *CachedEmbedder) put(key string, vector []float32, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &embeddingEntry{key: key, vector: append([]float32(nil), vector...), expires: expires}
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.opts.Size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*embeddingEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+func (c *CachedEmbedder) storeError() {
+	c.mu.Lock()
+	c.stats.StoreErrors++
+	c.mu.Unlock()
+}
+
+// queryEmbeddingKey marks contexts of query-time embedding calls.
+type queryEmbeddingKey struct{}
+
+// withQueryEmbedding marks ctx as embedding query text, for
+// EmbeddingCacheOptions.QueriesOnly.
+func withQueryEmbedding(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryEmbeddingKey{}, true)
+}
+
+func isQueryEmbedding(ctx context.Context) bool {
+	v, _ := ctx.Value(queryEmbeddingKey{}).(bool)
+	return v
+}
+
+// ErrCorruptEmbedding is returned by DirEmbeddingStore for a file that is
+// not a stored embedding.
+var ErrCorruptEmbedding = errors.New("corrupt cached embedding")
+
+// DirEmbeddingStore is an EmbeddingStore keeping one file per embedding in
+// a directory. It suits a single host; use a shared database or cache
+// service behind the EmbeddingStore interface to share across hosts.
+type DirEmbeddingStore struct {
+	dir string
+}
+
+// NewDirEmbeddingStore returns a store in dir, creating it if needed.
+// Expired files are removed when read.
+//
+// Example:
+//
+//	store, err := cyborgdb.NewDirEmbeddingStore(filepath.Join(os.TempDir(), "embeddings"))
+//	embedder = cyborgdb.NewCachedEmbedder(embedder, cyborgdb.EmbeddingCacheOptions{Store: store})
+func NewDirEmbeddingStore(dir string) (*DirEmbeddingStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create embedding store: %w", err)
+	}
+	return &DirEmbeddingStore{dir: dir}, nil
+}
+
+// Get implements EmbeddingStore.
+func (s *DirEmbeddingStore) Get(ctx context.Context, key string) ([]float32, bool, error) {
+	path := filepath.Join(s.dir, key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	// Layout: expiry in Unix nanoseconds, then the vector, little-endian.
+	if len(data) < 8 || (len(data)-8)%4 != 0 {
+		return nil, false, fmt.Errorf("%w: %s", ErrCorruptEmbedding, key)
+	}
+	if time.Now().UnixNano() >= int64(binary.LittleEndian.Uint64(data)) {
+		os.Remove(path)
+		return nil, false, nil
+	}
+	vector := make([]float32, (len(data)-8)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[8+4*i:]))
+	}
+	return vector, true, nil
+}
+
+// Put implements EmbeddingStore. Files are written atomically.
+func (s *DirEmbeddingStore) Put(ctx context.Context, key string, vector []float32, expires time.Time) error {
+	data := make([]byte, 8+4*len(vector))
+	binary.LittleEndian.PutUint64(data, uint64(expires.UnixNano()))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[8+4*i:], math.Float32bits(v))
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// embedder.go defines the Embedder interface used for client-side embedding.
+// When an Embedder is attached to an EncryptedIndex, items upserted with only
+// Contents and queries made with QueryContents are embedded locally before
+// being sent to the server, instead of relying on a server-side embedding model.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+// ErrEmbeddingCountMismatch is returned when an Embedder returns a different
+// number of vectors than the number of texts it was given.
+var ErrEmbeddingCountMismatch = fmt.Errorf("embedder returned an unexpected number of vectors")
+
+// Embedder converts text into embedding vectors.
+//
+// Implementations must return exactly one vector per input text, in input
+// order. They should be safe for concurrent use.
+type Embedder interface {
+	// Embed returns one embedding vector per input text.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EmbedderFunc adapts an ordinary function to the Embedder interface.
+type EmbedderFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Embed calls f(ctx, texts).
+func (f EmbedderFunc) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f(ctx, texts)
+}
+
+// SetEmbedder attaches an Embedder used to embed Contents and QueryContents
+// client-side. Pass nil to fall back to server-side embedding.
+func (e *EncryptedIndex) SetEmbedder(embedder Embedder) {
+	e.mu.Lock()
+	e.embedder = embedder
+	e.mu.Unlock()
+}
+
+// GetEmbedder returns the Embedder attached to this index, or nil if none.
+func (e *EncryptedIndex) GetEmbedder() Embedder {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.embedder
+}
+
+// embedTexts runs embedder and validates the result count.
+func embedTexts(ctx context.Context, embedder Embedder, texts []string) ([][]float32, error) {
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed contents: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d for %d texts", ErrEmbeddingCountMismatch, len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// embedItems fills in the Vector of every item that has string Contents but
+// no vector. The input slice is not modified; a copy is returned when any
+// item needs embedding.
+func (e *EncryptedIndex) embedItems(ctx context.Context, items []VectorItem) ([]VectorItem, error) {
+	embedder := e.GetEmbedder()
+	if embedder == nil {
+		return items, nil
+	}
+
+	var (
+		positions []int
+		texts     []string
+	)
+	for i := range items {
+		if len(items[i].Vector) > 0 {
+			continue
+		}
+		if text, ok := contentsText(items[i].Contents); ok {
+			positions = append(positions, i)
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return items, nil
+	}
+
+	vectors, err := embedTexts(ctx, embedder, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]VectorItem, len(items))
+	copy(out, items)
+	for i, pos := range positions {
+		out[pos].Vector = vectors[i]
+	}
+	return out, nil
+}
+
+// embedQuery replaces QueryContents with a client-side embedded QueryVector
+// when an embedder is attached and no query vector was supplied.
+func (e *EncryptedIndex) embedQuery(ctx context.Context, params QueryParams) (QueryParams, error) {
+	embedder := e.GetEmbedder()
+	if embedder == nil || params.QueryContents == nil ||
+		len(params.QueryVector) > 0 || len(params.BatchQueryVectors) > 0 {
+		return params, nil
+	}
+
+	vectors, err := embedTexts(withQueryEmbedding(ctx), embedder, []string{*params.QueryContents})
+	if err != nil {
+		return params, err
+	}
+
+	params.QueryVector = vectors[0]
+	params.QueryContents = nil
+	return params, nil
+}
+
+// contentsText extracts the string form of an item's Contents, if any.
+func contentsText(contents internal.NullableContents) (string, bool) {
+	c := contents.Get()
+	if c == nil || c.String == nil || *c.String == "" {
+		return "", false
+	}
+	return *c.String, true
+}
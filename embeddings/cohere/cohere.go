@@ -0,0 +1,292 @@
+// Package cohere provides a cyborgdb.Embedder backed by the Cohere v2 /embed
+// endpoint.
+//
+// Importing the package registers it with the embeddings registry under
+// ProviderName. The registry's ProviderConfig.Options accepts "input_type".
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+	"github.com/cyborginc/cyborgdb-go/embeddings"
+)
+
+const (
+	// ProviderName is the name the package registers with embeddings.Register.
+	ProviderName = "cohere"
+	// DefaultBaseURL is the default Cohere API base URL.
+	DefaultBaseURL = "https://api.cohere.com/v2"
+	// DefaultModel is the default embedding model.
+	DefaultModel = "embed-english-v3.0"
+	// MaxBatchSize is the largest number of texts Cohere accepts per request.
+	MaxBatchSize = 96
+	// DefaultMaxRetries is the default number of retries for retryable failures.
+	DefaultMaxRetries = 3
+	// DefaultTimeout is the default per-request timeout.
+	DefaultTimeout = 60 * time.Second
+)
+
+// Input types, telling v3 models what the text is used for.
+const (
+	InputTypeSearchDocument = "search_document"
+	InputTypeSearchQuery    = "search_query"
+	InputTypeClassification = "classification"
+	InputTypeClustering     = "clustering"
+)
+
+var (
+	// ErrMissingAPIKey is returned when no API key is configured.
+	ErrMissingAPIKey = errors.New("cohere: API key is required")
+	// ErrRequestFailed is returned when the endpoint responds with a non-2xx status.
+	ErrRequestFailed = errors.New("cohere: embed request failed")
+)
+
+// initialBackoff is the delay before the first retry; it doubles on each attempt.
+var initialBackoff = 500 * time.Millisecond
+
+// Compile-time checks that Embedder satisfies cyborgdb.Embedder and
+// cyborgdb.DimensionReporter.
+var (
+	_ cyborgdb.Embedder          = (*Embedder)(nil)
+	_ cyborgdb.DimensionReporter = (*Embedder)(nil)
+)
+
+func init() {
+	embeddings.Register(ProviderName, func(cfg embeddings.ProviderConfig) (cyborgdb.Embedder, error) {
+		return NewEmbedder(Config{
+			APIKey:     cfg.APIKey,
+			BaseURL:    cfg.BaseURL,
+			Model:      cfg.Model,
+			InputType:  cfg.Options["input_type"],
+			Dimensions: cfg.Dimensions,
+			BatchSize:  cfg.BatchSize,
+			MaxRetries: cfg.MaxRetries,
+		})
+	})
+}
+
+// Config configures an Embedder. Zero values fall back to the package defaults.
+type Config struct {
+	// APIKey is sent as a Bearer token (required).
+	APIKey string
+
+	// BaseURL is the API root (default DefaultBaseURL).
+	BaseURL string
+
+	// Model is the embedding model name (default DefaultModel).
+	Model string
+
+	// InputType tells the model what the texts are for (default
+	// InputTypeSearchDocument). Use a second Embedder with
+	// InputTypeSearchQuery to embed queries for best retrieval quality.
+	InputType string
+
+	// Dimensions optionally requests shortened embeddings from models that
+	// support it.
+	Dimensions int
+
+	// BatchSize is the maximum number of texts per request (default and
+	// maximum MaxBatchSize).
+	BatchSize int
+
+	// MaxRetries is the number of retries on 429 and 5xx responses (default DefaultMaxRetries).
+	// Set to a negative value to disable retries.
+	MaxRetries int
+
+	// HTTPClient is the HTTP client used for requests (default has DefaultTimeout).
+	HTTPClient *http.Client
+}
+
+// Embedder embeds text through the Cohere API. It is safe for concurrent use.
+type Embedder struct {
+	cfg Config
+}
+
+// NewEmbedder returns an Embedder for the given configuration.
+//
+// Usage:
+//
+//	embedder, err := cohere.NewEmbedder(cohere.Config{APIKey: os.Getenv("COHERE_API_KEY")})
+//	index.SetEmbedder(embedder)
+func NewEmbedder(cfg Config) (*Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.InputType == "" {
+		cfg.InputType = InputTypeSearchDocument
+	}
+	if cfg.BatchSize <= 0 || cfg.BatchSize > MaxBatchSize {
+		cfg.BatchSize = MaxBatchSize
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Embedder{cfg: cfg}, nil
+}
+
+// Model returns the configured embedding model name.
+func (e *Embedder) Model() string { return e.cfg.Model }
+
+// modelDimensions lists the default output dimension of known models.
+var modelDimensions = map[string]int{
+	"embed-english-v3.0":            1024,
+	"embed-multilingual-v3.0":       1024,
+	"embed-english-light-v3.0":      384,
+	"embed-multilingual-light-v3.0": 384,
+	"embed-v4.0":                    1536,
+}
+
+// Dimension returns the length of the embeddings returned: Config.Dimensions
+// if set, the default of a known model otherwise, or 0 if unknown. It
+// implements cyborgdb.DimensionReporter.
+func (e *Embedder) Dimension() int {
+	if e.cfg.Dimensions > 0 {
+		return e.cfg.Dimensions
+	}
+	return modelDimensions[e.cfg.Model]
+}
+
+// Embed returns one embedding per input text, splitting the input into
+// batches of at most BatchSize texts.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := start + e.cfg.BatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		vectors, err := e.embedBatchWithRetry(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, nil
+}
+
+type embedRequest struct {
+	Texts           []string `json:"texts"`
+	Model           string   `json:"model"`
+	InputType       string   `json:"input_type"`
+	EmbeddingTypes  []string `json:"embedding_types"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+}
+
+type embedResponse struct {
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+}
+
+// statusError records a non-2xx response so the retry loop can inspect it.
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%v with status %d: %s", ErrRequestFailed, e.status, e.body)
+}
+
+func (e *statusError) Unwrap() error { return ErrRequestFailed }
+
+func (e *statusError) retryable() bool {
+	return e.status == http.StatusTooManyRequests || e.status >= http.StatusInternalServerError
+}
+
+// embedBatchWithRetry sends one batch, retrying with exponential backoff on
+// rate limiting, server errors, and transport failures.
+func (e *Embedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
+	backoff := initialBackoff
+	var lastErr error
+	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		vectors, err := e.embedBatch(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		lastErr = err
+
+		var se *statusError
+		if errors.As(err, &se) && !se.retryable() {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, lastErr
+}
+
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	payload, err := json.Marshal(embedRequest{
+		Texts:           texts,
+		Model:           e.cfg.Model,
+		InputType:       e.cfg.InputType,
+		EmbeddingTypes:  []string{"float"},
+		OutputDimension: e.cfg.Dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cohere: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.BaseURL+"/embed", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("cohere: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cohere: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cohere: failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &statusError{status: resp.StatusCode, body: string(body)}
+	}
+
+	var parsed embedResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("cohere: failed to parse response: %w", err)
+	}
+	if len(parsed.Embeddings.Float) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d for %d texts", cyborgdb.ErrEmbeddingCountMismatch, len(parsed.Embeddings.Float), len(texts))
+	}
+	return parsed.Embeddings.Float, nil
+}
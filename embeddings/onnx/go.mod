@@ -0,0 +1,19 @@
+module github.com/cyborginc/cyborgdb-go/embeddings/onnx
+
+go 1.18
+
+// A separate module, so that only applications using ONNX Runtime inherit its
+// cgo dependency; the runtime session is built only with the onnxruntime tag.
+
+require (
+	github.com/cyborginc/cyborgdb-go v0.0.0-00010101000000-000000000000
+	github.com/yalue/onnxruntime_go v1.36.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+)
+
+replace github.com/cyborginc/cyborgdb-go => ../..
@@ -0,0 +1,296 @@
+// Package onnx provides a cyborgdb.Embedder that runs sentence-transformer
+// models exported to ONNX on the local machine, so raw text never leaves the
+// process.
+//
+// The Embedder tokenizes texts with a Tokenizer (NewWordPieceTokenizer reads
+// the vocab.txt shipped with BERT-family models), runs batches through a
+// Session, and pools the token embeddings into one vector per text.
+//
+// NewRuntimeSession runs models with ONNX Runtime through
+// github.com/yalue/onnxruntime_go. This package is its own module, so the
+// SDK never depends on ONNX Runtime, and NewRuntimeSession is compiled only
+// with the onnxruntime build tag, so even this module needs cgo and the ONNX
+// Runtime shared library only when asked:
+//
+//	go get github.com/cyborginc/cyborgdb-go/embeddings/onnx
+//	go build -tags onnxruntime ./...
+//
+// Any other inference engine can be used by implementing Session.
+package onnx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+	"github.com/cyborginc/cyborgdb-go/vecmath"
+)
+
+const (
+	// DefaultBatchSize is the default number of texts per Session run.
+	DefaultBatchSize = 32
+	// DefaultMaxLength is the default maximum number of tokens per text,
+	// including the special tokens; longer texts are truncated.
+	DefaultMaxLength = 256
+)
+
+var (
+	// ErrMissingSession is returned when no Session is configured.
+	ErrMissingSession = errors.New("onnx: session is required")
+	// ErrMissingTokenizer is returned when no Tokenizer is configured.
+	ErrMissingTokenizer = errors.New("onnx: tokenizer is required")
+	// ErrUnexpectedOutput is returned when a Session returns an output whose
+	// shape does not match the batch.
+	ErrUnexpectedOutput = errors.New("onnx: unexpected model output shape")
+)
+
+// Compile-time checks that Embedder satisfies cyborgdb.Embedder and
+// cyborgdb.DimensionReporter.
+var (
+	_ cyborgdb.Embedder          = (*Embedder)(nil)
+	_ cyborgdb.DimensionReporter = (*Embedder)(nil)
+)
+
+// Pooling selects how token embeddings are combined into a text embedding.
+type Pooling int
+
+const (
+	// PoolingMean averages the embeddings of the non-padding tokens, as most
+	// sentence-transformer models do.
+	PoolingMean Pooling = iota
+	// PoolingCLS uses the embedding of the first ([CLS]) token.
+	PoolingCLS
+	// PoolingMax takes the element-wise maximum over non-padding tokens.
+	PoolingMax
+)
+
+// Encoding is a tokenized text.
+type Encoding struct {
+	// IDs are the token IDs, including special tokens.
+	IDs []int64
+	// TypeIDs are the segment IDs; nil means all zero.
+	TypeIDs []int64
+}
+
+// Tokenizer converts text into model input tokens.
+type Tokenizer interface {
+	// Encode tokenizes text into at most maxLength tokens, including special
+	// tokens.
+	Encode(text string, maxLength int) Encoding
+	// PadID returns the ID of the padding token.
+	PadID() int64
+}
+
+// Session runs a model on a padded batch of tokens.
+//
+// Implementations must be safe for concurrent use.
+type Session interface {
+	// Run returns the model output for a batch of batchSize sequences of
+	// seqLen tokens, each input flattened row by row. The output is either
+	// token embeddings shaped [batchSize, seqLen, dim] or already pooled
+	// text embeddings shaped [batchSize, dim], flattened row by row.
+	Run(ctx context.Context, inputIDs, attentionMask, typeIDs []int64, batchSize, seqLen int) (output []float32, shape []int64, err error)
+}
+
+// Config configures an Embedder. Zero values fall back to the package defaults.
+type Config struct {
+	// Session runs the model (required). See NewRuntimeSession.
+	Session Session
+
+	// Tokenizer tokenizes input texts (required). See NewWordPieceTokenizer.
+	Tokenizer Tokenizer
+
+	// BatchSize is the maximum number of texts per Session run (default DefaultBatchSize).
+	BatchSize int
+
+	// MaxLength is the maximum number of tokens per text (default DefaultMaxLength).
+	MaxLength int
+
+	// Pooling combines token embeddings (default PoolingMean). It is ignored
+	// when the model outputs pooled embeddings.
+	Pooling Pooling
+
+	// Normalize scales every embedding to unit L2 norm, as expected by
+	// cosine and dot-product indexes.
+	Normalize bool
+
+	// Dimension is the model's output dimension, if known, reported by
+	// Dimension without running the model.
+	Dimension int
+}
+
+// Embedder embeds text with a local model. It is safe for concurrent use if
+// its Session is.
+type Embedder struct {
+	cfg Config
+}
+
+// NewEmbedder returns an Embedder for the given configuration.
+//
+// Usage:
+//
+//	session, err := onnx.NewRuntimeSession(onnx.RuntimeConfig{
+//		LibraryPath: "/usr/lib/libonnxruntime.so",
+//		ModelPath:   "all-MiniLM-L6-v2/model.onnx",
+//	})
+//	tokenizer, err := onnx.LoadWordPieceTokenizer("all-MiniLM-L6-v2/vocab.txt", true)
+//	embedder, err := onnx.NewEmbedder(onnx.Config{
+//		Session:   session,
+//		Tokenizer: tokenizer,
+//		Normalize: true,
+//	})
+//	index.SetEmbedder(embedder)
+func NewEmbedder(cfg Config) (*Embedder, error) {
+	if cfg.Session == nil {
+		return nil, ErrMissingSession
+	}
+	if cfg.Tokenizer == nil {
+		return nil, ErrMissingTokenizer
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxLength <= 0 {
+		cfg.MaxLength = DefaultMaxLength
+	}
+	return &Embedder{cfg: cfg}, nil
+}
+
+// Dimension returns Config.Dimension. It implements cyborgdb.DimensionReporter.
+func (e *Embedder) Dimension() int { return e.cfg.Dimension }
+
+// Embed returns one embedding per input text, running the model on batches
+// of at most BatchSize texts.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := start + e.cfg.BatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		vectors, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, nil
+}
+
+// embedBatch tokenizes and pads texts, runs the model, and pools the output.
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	encodings := make([]Encoding, len(texts))
+	seqLen := 0
+	for i, text := range texts {
+		encodings[i] = e.cfg.Tokenizer.Encode(text, e.cfg.MaxLength)
+		if n := len(encodings[i].IDs); n > seqLen {
+			seqLen = n
+		}
+	}
+
+	batch := len(texts)
+	ids := make([]int64, batch*seqLen)
+	mask := make([]int64, batch*seqLen)
+	typeIDs := make([]int64, batch*seqLen)
+	pad := e.cfg.Tokenizer.PadID()
+	for i, enc := range encodings {
+		row := i * seqLen
+		for j := 0; j < seqLen; j++ {
+			if j < len(enc.IDs) {
+				ids[row+j] = enc.IDs[j]
+				mask[row+j] = 1
+				if j < len(enc.TypeIDs) {
+					typeIDs[row+j] = enc.TypeIDs[j]
+				}
+			} else {
+				ids[row+j] = pad
+			}
+		}
+	}
+
+	output, shape, err := e.cfg.Session.Run(ctx, ids, mask, typeIDs, batch, seqLen)
+	if err != nil {
+		return nil, fmt.Errorf("onnx: model run failed: %w", err)
+	}
+	vectors, err := pool(output, shape, mask, batch, seqLen, e.cfg.Pooling)
+	if err != nil {
+		return nil, err
+	}
+	if e.cfg.Normalize {
+		vecmath.NormalizeBatch(vectors)
+	}
+	return vectors, nil
+}
+
+// pool turns the model output into one vector per sequence.
+func pool(output []float32, shape []int64, mask []int64, batch, seqLen int, pooling Pooling) ([][]float32, error) {
+	switch {
+	case len(shape) == 2 && int(shape[0]) == batch:
+		dim := int(shape[1])
+		if len(output) != batch*dim {
+			return nil, fmt.Errorf("%w: %d values for shape %v", ErrUnexpectedOutput, len(output), shape)
+		}
+		vectors := make([][]float32, batch)
+		for i := range vectors {
+			vectors[i] = append([]float32(nil), output[i*dim:(i+1)*dim]...)
+		}
+		return vectors, nil
+
+	case len(shape) == 3 && int(shape[0]) == batch && int(shape[1]) == seqLen:
+		dim := int(shape[2])
+		if len(output) != batch*seqLen*dim {
+			return nil, fmt.Errorf("%w: %d values for shape %v", ErrUnexpectedOutput, len(output), shape)
+		}
+		vectors := make([][]float32, batch)
+		for i := range vectors {
+			vectors[i] = poolSequence(output[i*seqLen*dim:(i+1)*seqLen*dim], mask[i*seqLen:(i+1)*seqLen], dim, pooling)
+		}
+		return vectors, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %v for batch %d of %d tokens", ErrUnexpectedOutput, shape, batch, seqLen)
+	}
+}
+
+// poolSequence pools the token embeddings of one sequence, skipping padding.
+func poolSequence(tokens []float32, mask []int64, dim int, pooling Pooling) []float32 {
+	v := make([]float32, dim)
+	if pooling == PoolingCLS {
+		copy(v, tokens[:dim])
+		return v
+	}
+	if pooling == PoolingMax {
+		for j := range v {
+			v[j] = float32(math.Inf(-1))
+		}
+	}
+	n := 0
+	for t, m := range mask {
+		if m == 0 {
+			continue
+		}
+		n++
+		token := tokens[t*dim : (t+1)*dim]
+		for j, x := range token {
+			if pooling == PoolingMax {
+				if x > v[j] {
+					v[j] = x
+				}
+			} else {
+				v[j] += x
+			}
+		}
+	}
+	if n == 0 {
+		return make([]float32, dim)
+	}
+	if pooling == PoolingMean {
+		vecmath.Scale(v, 1/float32(n))
+	}
+	return v
+}
@@ -0,0 +1,146 @@
+//go:build onnxruntime
+
+package onnx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// Default tensor names of sentence-transformer models exported to ONNX.
+var (
+	DefaultInputNames = []string{"input_ids", "attention_mask", "token_type_ids"}
+	DefaultOutputName = "last_hidden_state"
+)
+
+// ErrMissingModel is returned when RuntimeConfig has no ModelPath.
+var ErrMissingModel = errors.New("onnx: model path is required")
+
+// initOnce guards the process-wide ONNX Runtime environment.
+var (
+	initOnce sync.Once
+	initErr  error
+)
+
+// RuntimeConfig configures NewRuntimeSession. Zero values fall back to defaults.
+type RuntimeConfig struct {
+	// LibraryPath is the path of the ONNX Runtime shared library. It is
+	// used by the first session created in the process; if empty, the
+	// library is looked up by its default name.
+	LibraryPath string
+
+	// ModelPath is the path of the .onnx model file (required).
+	ModelPath string
+
+	// InputNames are the model's input tensors, in the order input IDs,
+	// attention mask, and optionally token type IDs (default
+	// DefaultInputNames). Give two names for models without token types.
+	InputNames []string
+
+	// OutputName is the model's output tensor (default DefaultOutputName).
+	OutputName string
+
+	// IntraOpThreads limits the threads used per run; 0 lets ONNX Runtime
+	// decide.
+	IntraOpThreads int
+}
+
+// RuntimeSession runs a model with ONNX Runtime. It is safe for concurrent
+// use.
+type RuntimeSession struct {
+	session    *ort.DynamicAdvancedSession
+	inputCount int
+}
+
+// Compile-time check that RuntimeSession satisfies Session.
+var _ Session = (*RuntimeSession)(nil)
+
+// NewRuntimeSession loads a model into ONNX Runtime. Call Close when done.
+func NewRuntimeSession(cfg RuntimeConfig) (*RuntimeSession, error) {
+	if cfg.ModelPath == "" {
+		return nil, ErrMissingModel
+	}
+	inputs := cfg.InputNames
+	if len(inputs) == 0 {
+		inputs = DefaultInputNames
+	}
+	if len(inputs) < 2 || len(inputs) > 3 {
+		return nil, fmt.Errorf("onnx: expected 2 or 3 input names, got %d", len(inputs))
+	}
+	output := cfg.OutputName
+	if output == "" {
+		output = DefaultOutputName
+	}
+
+	initOnce.Do(func() {
+		if cfg.LibraryPath != "" {
+			ort.SetSharedLibraryPath(cfg.LibraryPath)
+		}
+		initErr = ort.InitializeEnvironment()
+	})
+	if initErr != nil {
+		return nil, fmt.Errorf("onnx: failed to initialize ONNX Runtime: %w", initErr)
+	}
+
+	opts, err := ort.NewSessionOptions()
+	if err != nil {
+		return nil, fmt.Errorf("onnx: failed to create session options: %w", err)
+	}
+	defer opts.Destroy()
+	if cfg.IntraOpThreads > 0 {
+		if err := opts.SetIntraOpNumThreads(cfg.IntraOpThreads); err != nil {
+			return nil, fmt.Errorf("onnx: failed to set thread count: %w", err)
+		}
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(cfg.ModelPath, inputs, []string{output}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("onnx: failed to load model: %w", err)
+	}
+	return &RuntimeSession{session: session, inputCount: len(inputs)}, nil
+}
+
+// Run implements Session. ONNX Runtime cannot be interrupted mid-run, so ctx
+// is only checked before the run starts.
+func (s *RuntimeSession) Run(ctx context.Context, inputIDs, attentionMask, typeIDs []int64, batchSize, seqLen int) ([]float32, []int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	shape := ort.NewShape(int64(batchSize), int64(seqLen))
+	data := [][]int64{inputIDs, attentionMask, typeIDs}[:s.inputCount]
+	inputs := make([]ort.Value, 0, len(data))
+	defer func() {
+		for _, v := range inputs {
+			v.Destroy()
+		}
+	}()
+	for _, d := range data {
+		tensor, err := ort.NewTensor(shape, d)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputs = append(inputs, tensor)
+	}
+
+	// A nil output is allocated by ONNX Runtime with the shape it computes.
+	outputs := []ort.Value{nil}
+	if err := s.session.Run(inputs, outputs); err != nil {
+		return nil, nil, err
+	}
+	defer outputs[0].Destroy()
+	tensor, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: output is not a float32 tensor", ErrUnexpectedOutput)
+	}
+	out := append([]float32(nil), tensor.GetData()...)
+	return out, append([]int64(nil), tensor.GetShape()...), nil
+}
+
+// Close releases the model.
+func (s *RuntimeSession) Close() error {
+	return s.session.Destroy()
+}
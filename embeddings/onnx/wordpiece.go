@@ -0,0 +1,174 @@
+package onnx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Special tokens of BERT-family vocabularies.
+const (
+	tokenCLS = "[CLS]"
+	tokenSEP = "[SEP]"
+	tokenPAD = "[PAD]"
+	tokenUNK = "[UNK]"
+)
+
+// maxWordRunes is the longest word split into word pieces; longer words
+// become [UNK], as in the reference implementation.
+const maxWordRunes = 100
+
+// WordPieceTokenizer implements the BERT tokenizer used by most
+// sentence-transformer models: whitespace and punctuation splitting followed
+// by greedy longest-match word pieces.
+//
+// Accents are not stripped, so uncased models see accented characters as
+// written; this only affects texts containing them.
+type WordPieceTokenizer struct {
+	vocab     map[string]int64
+	lowercase bool
+	cls, sep  int64
+	pad, unk  int64
+}
+
+// LoadWordPieceTokenizer reads a vocab.txt file. See NewWordPieceTokenizer.
+func LoadWordPieceTokenizer(path string, lowercase bool) (*WordPieceTokenizer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("onnx: failed to open vocabulary: %w", err)
+	}
+	defer f.Close()
+	return NewWordPieceTokenizer(f, lowercase)
+}
+
+// NewWordPieceTokenizer reads a vocabulary with one token per line, the line
+// number being the token ID. Set lowercase for uncased models.
+func NewWordPieceTokenizer(r io.Reader, lowercase bool) (*WordPieceTokenizer, error) {
+	t := &WordPieceTokenizer{vocab: make(map[string]int64), lowercase: lowercase}
+	scanner := bufio.NewScanner(r)
+	var id int64
+	for scanner.Scan() {
+		token := strings.TrimRight(scanner.Text(), "\r")
+		if _, dup := t.vocab[token]; !dup {
+			t.vocab[token] = id
+		}
+		id++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("onnx: failed to read vocabulary: %w", err)
+	}
+	for _, special := range []struct {
+		token string
+		id    *int64
+	}{{tokenCLS, &t.cls}, {tokenSEP, &t.sep}, {tokenPAD, &t.pad}, {tokenUNK, &t.unk}} {
+		v, ok := t.vocab[special.token]
+		if !ok {
+			return nil, fmt.Errorf("onnx: vocabulary has no %s token", special.token)
+		}
+		*special.id = v
+	}
+	return t, nil
+}
+
+// PadID returns the ID of [PAD].
+func (t *WordPieceTokenizer) PadID() int64 { return t.pad }
+
+// Encode returns [CLS] + word pieces of text + [SEP], truncated to
+// maxLength tokens.
+func (t *WordPieceTokenizer) Encode(text string, maxLength int) Encoding {
+	if maxLength < 2 {
+		maxLength = 2
+	}
+	ids := []int64{t.cls}
+	for _, word := range t.words(text) {
+		if len(ids) >= maxLength-1 {
+			break
+		}
+		ids = append(ids, t.wordPieces(word)...)
+	}
+	if len(ids) > maxLength-1 {
+		ids = ids[:maxLength-1]
+	}
+	ids = append(ids, t.sep)
+	return Encoding{IDs: ids}
+}
+
+// words splits text on whitespace and around punctuation and CJK characters,
+// dropping control characters.
+func (t *WordPieceTokenizer) words(text string) []string {
+	if t.lowercase {
+		text = strings.ToLower(text)
+	}
+	var (
+		words   []string
+		current strings.Builder
+	)
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case r == 0 || r == unicode.ReplacementChar || (unicode.IsControl(r) && !unicode.IsSpace(r)):
+		case unicode.IsSpace(r):
+			flush()
+		case isPunctuation(r) || isCJK(r):
+			flush()
+			words = append(words, string(r))
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return words
+}
+
+// wordPieces splits word into the longest vocabulary pieces, continuation
+// pieces prefixed with "##", or returns [UNK] if it cannot.
+func (t *WordPieceTokenizer) wordPieces(word string) []int64 {
+	runes := []rune(word)
+	if len(runes) > maxWordRunes {
+		return []int64{t.unk}
+	}
+	var pieces []int64
+	for start := 0; start < len(runes); {
+		end := len(runes)
+		found := int64(-1)
+		for ; end > start; end-- {
+			piece := string(runes[start:end])
+			if start > 0 {
+				piece = "##" + piece
+			}
+			if id, ok := t.vocab[piece]; ok {
+				found = id
+				break
+			}
+		}
+		if found < 0 {
+			return []int64{t.unk}
+		}
+		pieces = append(pieces, found)
+		start = end
+	}
+	return pieces
+}
+
+// isPunctuation reports whether r splits words, treating all non-letter,
+// non-digit ASCII symbols as punctuation like BERT does.
+func isPunctuation(r rune) bool {
+	if (r >= 33 && r <= 47) || (r >= 58 && r <= 64) || (r >= 91 && r <= 96) || (r >= 123 && r <= 126) {
+		return true
+	}
+	return unicode.IsPunct(r)
+}
+
+// isCJK reports whether r is a CJK ideograph, which BERT tokenizes as a word
+// of its own.
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
@@ -0,0 +1,277 @@
+// Package openai provides a cyborgdb.Embedder backed by OpenAI-compatible
+// /embeddings endpoints, including OpenAI itself, Azure-style gateways, and
+// self-hosted servers that implement the same API (vLLM, Ollama, LocalAI).
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+	"github.com/cyborginc/cyborgdb-go/embeddings"
+)
+
+const (
+	// DefaultBaseURL is the default OpenAI API base URL.
+	DefaultBaseURL = "https://api.openai.com/v1"
+	// DefaultModel is the default embedding model.
+	DefaultModel = "text-embedding-3-small"
+	// DefaultBatchSize is the default number of texts sent per request.
+	DefaultBatchSize = 256
+	// DefaultMaxRetries is the default number of retries for retryable failures.
+	DefaultMaxRetries = 3
+	// DefaultTimeout is the default per-request timeout.
+	DefaultTimeout = 60 * time.Second
+)
+
+var (
+	// ErrMissingAPIKey is returned when no API key is configured.
+	ErrMissingAPIKey = errors.New("openai: API key is required")
+	// ErrRequestFailed is returned when the endpoint responds with a non-2xx status.
+	ErrRequestFailed = errors.New("openai: embeddings request failed")
+)
+
+// initialBackoff is the delay before the first retry; it doubles on each attempt.
+var initialBackoff = 500 * time.Millisecond
+
+// Compile-time checks that Embedder satisfies cyborgdb.Embedder and
+// cyborgdb.DimensionReporter.
+var (
+	_ cyborgdb.Embedder          = (*Embedder)(nil)
+	_ cyborgdb.DimensionReporter = (*Embedder)(nil)
+)
+
+// Config configures an Embedder. Zero values fall back to the package defaults.
+type Config struct {
+	// APIKey is sent as a Bearer token (required).
+	APIKey string
+
+	// BaseURL is the API root, e.g. "https://api.openai.com/v1" (default DefaultBaseURL).
+	BaseURL string
+
+	// Model is the embedding model name (default DefaultModel).
+	Model string
+
+	// Dimensions optionally requests shortened embeddings from models that support it.
+	Dimensions int
+
+	// BatchSize is the maximum number of texts per request (default DefaultBatchSize).
+	BatchSize int
+
+	// MaxRetries is the number of retries on 429 and 5xx responses (default DefaultMaxRetries).
+	// Set to a negative value to disable retries.
+	MaxRetries int
+
+	// HTTPClient is the HTTP client used for requests (default has DefaultTimeout).
+	HTTPClient *http.Client
+}
+
+// ProviderName is the name the package registers with embeddings.Register.
+const ProviderName = "openai"
+
+func init() {
+	embeddings.Register(ProviderName, func(cfg embeddings.ProviderConfig) (cyborgdb.Embedder, error) {
+		return NewEmbedder(Config{
+			APIKey:     cfg.APIKey,
+			BaseURL:    cfg.BaseURL,
+			Model:      cfg.Model,
+			Dimensions: cfg.Dimensions,
+			BatchSize:  cfg.BatchSize,
+			MaxRetries: cfg.MaxRetries,
+		})
+	})
+}
+
+// Embedder embeds text through an OpenAI-compatible /embeddings endpoint.
+// It is safe for concurrent use.
+type Embedder struct {
+	cfg Config
+}
+
+// NewEmbedder returns an Embedder for the given configuration.
+//
+// Usage:
+//
+//	embedder, err := openai.NewEmbedder(openai.Config{APIKey: os.Getenv("OPENAI_API_KEY")})
+//	index.SetEmbedder(embedder)
+func NewEmbedder(cfg Config) (*Embedder, error) {
+	if cfg.APIKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Embedder{cfg: cfg}, nil
+}
+
+// Model returns the configured embedding model name.
+func (e *Embedder) Model() string { return e.cfg.Model }
+
+// modelDimensions lists the default output dimension of known models.
+var modelDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+// Dimension returns the length of the embeddings returned: Config.Dimensions
+// if set, the default of a known model otherwise, or 0 if unknown. It
+// implements cyborgdb.DimensionReporter.
+func (e *Embedder) Dimension() int {
+	if e.cfg.Dimensions > 0 {
+		return e.cfg.Dimensions
+	}
+	return modelDimensions[e.cfg.Model]
+}
+
+// Embed returns one embedding per input text, splitting the input into
+// batches of at most BatchSize texts.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := start + e.cfg.BatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		vectors, err := e.embedBatchWithRetry(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, nil
+}
+
+type embeddingsRequest struct {
+	Input      []string `json:"input"`
+	Model      string   `json:"model"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// statusError records a non-2xx response so the retry loop can inspect it.
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%v with status %d: %s", ErrRequestFailed, e.status, e.body)
+}
+
+func (e *statusError) Unwrap() error { return ErrRequestFailed }
+
+func (e *statusError) retryable() bool {
+	return e.status == http.StatusTooManyRequests || e.status >= http.StatusInternalServerError
+}
+
+// embedBatchWithRetry sends one batch, retrying with exponential backoff on
+// rate limiting, server errors, and transport failures.
+func (e *Embedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
+	backoff := initialBackoff
+	var lastErr error
+	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		vectors, err := e.embedBatch(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		lastErr = err
+
+		var se *statusError
+		if errors.As(err, &se) && !se.retryable() {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, lastErr
+}
+
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	payload, err := json.Marshal(embeddingsRequest{
+		Input:      texts,
+		Model:      e.cfg.Model,
+		Dimensions: e.cfg.Dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.BaseURL+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+
+	resp, err := e.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &statusError{status: resp.StatusCode, body: string(body)}
+	}
+
+	var parsed embeddingsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("openai: failed to parse response: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d for %d texts", cyborgdb.ErrEmbeddingCountMismatch, len(parsed.Data), len(texts))
+	}
+
+	// Results are documented to be in input order, but sort by index to be safe.
+	sort.Slice(parsed.Data, func(i, j int) bool { return parsed.Data[i].Index < parsed.Data[j].Index })
+
+	vectors := make([][]float32, len(parsed.Data))
+	for i, d := range parsed.Data {
+		vectors[i] = d.Embedding
+	}
+	return vectors, nil
+}
@@ -0,0 +1,91 @@
+// Package embeddings selects a cyborgdb.Embedder implementation by provider
+// name, so applications can switch embedding providers through
+// configuration instead of code.
+//
+// Provider packages register themselves when imported, in the manner of
+// database/sql drivers:
+//
+//	import (
+//		"github.com/cyborginc/cyborgdb-go/embeddings"
+//		_ "github.com/cyborginc/cyborgdb-go/embeddings/cohere"
+//		_ "github.com/cyborginc/cyborgdb-go/embeddings/openai"
+//	)
+//
+//	embedder, err := embeddings.New(os.Getenv("EMBEDDING_PROVIDER"), embeddings.ProviderConfig{
+//		APIKey: os.Getenv("EMBEDDING_API_KEY"),
+//		Model:  os.Getenv("EMBEDDING_MODEL"),
+//	})
+package embeddings
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// ErrUnknownProvider is returned by New for a provider that is not registered.
+var ErrUnknownProvider = errors.New("embeddings: unknown provider")
+
+// ProviderConfig holds the settings common to embedding providers. Zero
+// values fall back to the provider's defaults.
+type ProviderConfig struct {
+	// APIKey authenticates with the provider.
+	APIKey string
+	// Model is the embedding model name.
+	Model string
+	// BaseURL overrides the provider's API endpoint.
+	BaseURL string
+	// Dimensions requests shortened embeddings from models that support it.
+	Dimensions int
+	// BatchSize is the maximum number of texts per request, capped at the
+	// provider's limit.
+	BatchSize int
+	// MaxRetries is the number of retries of rate-limited and failed
+	// requests; negative disables retries.
+	MaxRetries int
+	// Options holds provider-specific settings, documented by each provider
+	// package, e.g. "input_type" for Cohere or "project" for Vertex AI.
+	Options map[string]string
+}
+
+// Factory creates an Embedder from a ProviderConfig.
+type Factory func(cfg ProviderConfig) (cyborgdb.Embedder, error)
+
+var (
+	mu        sync.RWMutex
+	providers = make(map[string]Factory)
+)
+
+// Register makes a provider available to New under name, replacing any
+// provider registered under the same name.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	providers[name] = factory
+}
+
+// New creates an Embedder with the provider registered under name.
+func New(name string, cfg ProviderConfig) (cyborgdb.Embedder, error) {
+	mu.RLock()
+	factory, ok := providers[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q (registered: %q)", ErrUnknownProvider, name, Providers())
+	}
+	return factory(cfg)
+}
+
+// Providers returns the names of the registered providers, sorted.
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
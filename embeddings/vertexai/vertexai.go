@@ -0,0 +1,368 @@
+// Package vertexai provides a cyborgdb.Embedder backed by Google Vertex AI
+// text embedding models.
+//
+// Requests are authorized with an OAuth 2.0 access token, supplied as a
+// static AccessToken or through a TokenSource such as one built on
+// golang.org/x/oauth2/google, so the SDK does not depend on Google's client
+// libraries.
+//
+// Importing the package registers it with the embeddings registry under
+// ProviderName. There ProviderConfig.APIKey is used as the access token, and
+// Options accepts "project" (required), "location", and "task_type".
+package vertexai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+	"github.com/cyborginc/cyborgdb-go/embeddings"
+)
+
+const (
+	// ProviderName is the name the package registers with embeddings.Register.
+	ProviderName = "vertexai"
+	// DefaultLocation is the default Google Cloud region.
+	DefaultLocation = "us-central1"
+	// DefaultModel is the default embedding model.
+	DefaultModel = "text-embedding-005"
+	// DefaultTaskType is the default task type.
+	DefaultTaskType = "RETRIEVAL_DOCUMENT"
+	// MaxBatchSize is the largest number of texts Vertex AI accepts per
+	// request for most models; see modelBatchLimits for exceptions.
+	MaxBatchSize = 250
+	// DefaultMaxRetries is the default number of retries for retryable failures.
+	DefaultMaxRetries = 3
+	// DefaultTimeout is the default per-request timeout.
+	DefaultTimeout = 60 * time.Second
+)
+
+var (
+	// ErrMissingProject is returned when no project is configured.
+	ErrMissingProject = errors.New("vertexai: project is required")
+	// ErrMissingCredentials is returned when neither an access token nor a
+	// token source is configured.
+	ErrMissingCredentials = errors.New("vertexai: access token or token source is required")
+	// ErrRequestFailed is returned when the endpoint responds with a non-2xx status.
+	ErrRequestFailed = errors.New("vertexai: predict request failed")
+)
+
+// initialBackoff is the delay before the first retry; it doubles on each attempt.
+var initialBackoff = 500 * time.Millisecond
+
+// modelBatchLimits lists models that accept fewer texts per request than
+// MaxBatchSize.
+var modelBatchLimits = map[string]int{
+	"gemini-embedding-001": 1,
+}
+
+// modelDimensions lists the default output dimension of known models.
+var modelDimensions = map[string]int{
+	"text-embedding-005":              768,
+	"text-embedding-004":              768,
+	"text-multilingual-embedding-002": 768,
+	"gemini-embedding-001":            3072,
+}
+
+// Compile-time checks that Embedder satisfies cyborgdb.Embedder and
+// cyborgdb.DimensionReporter.
+var (
+	_ cyborgdb.Embedder          = (*Embedder)(nil)
+	_ cyborgdb.DimensionReporter = (*Embedder)(nil)
+)
+
+func init() {
+	embeddings.Register(ProviderName, func(cfg embeddings.ProviderConfig) (cyborgdb.Embedder, error) {
+		return NewEmbedder(Config{
+			Project:     cfg.Options["project"],
+			Location:    cfg.Options["location"],
+			AccessToken: cfg.APIKey,
+			BaseURL:     cfg.BaseURL,
+			Model:       cfg.Model,
+			TaskType:    cfg.Options["task_type"],
+			Dimensions:  cfg.Dimensions,
+			BatchSize:   cfg.BatchSize,
+			MaxRetries:  cfg.MaxRetries,
+		})
+	})
+}
+
+// TokenSource returns a current OAuth 2.0 access token.
+type TokenSource func(ctx context.Context) (string, error)
+
+// Config configures an Embedder. Zero values fall back to the package defaults.
+type Config struct {
+	// Project is the Google Cloud project ID (required).
+	Project string
+
+	// Location is the region of the endpoint (default DefaultLocation).
+	Location string
+
+	// AccessToken is a static OAuth 2.0 access token. Tokens expire, so
+	// prefer TokenSource for long-running processes.
+	AccessToken string
+
+	// TokenSource is called for a token before each request; it takes
+	// precedence over AccessToken.
+	TokenSource TokenSource
+
+	// BaseURL overrides the endpoint root (default
+	// "https://{Location}-aiplatform.googleapis.com/v1").
+	BaseURL string
+
+	// Model is the embedding model name (default DefaultModel).
+	Model string
+
+	// TaskType tells the model what the texts are for, e.g.
+	// "RETRIEVAL_QUERY" (default DefaultTaskType).
+	TaskType string
+
+	// Dimensions optionally requests shortened embeddings.
+	Dimensions int
+
+	// BatchSize is the maximum number of texts per request (default and
+	// maximum MaxBatchSize, or the model's lower limit).
+	BatchSize int
+
+	// MaxRetries is the number of retries on 429 and 5xx responses (default DefaultMaxRetries).
+	// Set to a negative value to disable retries.
+	MaxRetries int
+
+	// HTTPClient is the HTTP client used for requests (default has DefaultTimeout).
+	HTTPClient *http.Client
+}
+
+// Embedder embeds text through Vertex AI. It is safe for concurrent use if
+// its TokenSource is.
+type Embedder struct {
+	cfg Config
+}
+
+// NewEmbedder returns an Embedder for the given configuration.
+//
+// Usage:
+//
+//	creds, _ := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
+//	embedder, err := vertexai.NewEmbedder(vertexai.Config{
+//		Project: "my-project",
+//		TokenSource: func(ctx context.Context) (string, error) {
+//			t, err := creds.TokenSource.Token()
+//			if err != nil {
+//				return "", err
+//			}
+//			return t.AccessToken, nil
+//		},
+//	})
+//	index.SetEmbedder(embedder)
+func NewEmbedder(cfg Config) (*Embedder, error) {
+	if cfg.Project == "" {
+		return nil, ErrMissingProject
+	}
+	if cfg.AccessToken == "" && cfg.TokenSource == nil {
+		return nil, ErrMissingCredentials
+	}
+	if cfg.Location == "" {
+		cfg.Location = DefaultLocation
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://" + cfg.Location + "-aiplatform.googleapis.com/v1"
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.TaskType == "" {
+		cfg.TaskType = DefaultTaskType
+	}
+	limit := MaxBatchSize
+	if l, ok := modelBatchLimits[cfg.Model]; ok {
+		limit = l
+	}
+	if cfg.BatchSize <= 0 || cfg.BatchSize > limit {
+		cfg.BatchSize = limit
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Embedder{cfg: cfg}, nil
+}
+
+// Model returns the configured embedding model name.
+func (e *Embedder) Model() string { return e.cfg.Model }
+
+// Dimension returns the length of the embeddings returned: Config.Dimensions
+// if set, the default of a known model otherwise, or 0 if unknown. It
+// implements cyborgdb.DimensionReporter.
+func (e *Embedder) Dimension() int {
+	if e.cfg.Dimensions > 0 {
+		return e.cfg.Dimensions
+	}
+	return modelDimensions[e.cfg.Model]
+}
+
+// Embed returns one embedding per input text, splitting the input into
+// batches of at most BatchSize texts.
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := start + e.cfg.BatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		vectors, err := e.embedBatchWithRetry(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, nil
+}
+
+type predictInstance struct {
+	Content  string `json:"content"`
+	TaskType string `json:"task_type,omitempty"`
+}
+
+type predictParameters struct {
+	OutputDimensionality int `json:"outputDimensionality,omitempty"`
+}
+
+type predictRequest struct {
+	Instances  []predictInstance  `json:"instances"`
+	Parameters *predictParameters `json:"parameters,omitempty"`
+}
+
+type predictResponse struct {
+	Predictions []struct {
+		Embeddings struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	} `json:"predictions"`
+}
+
+// statusError records a non-2xx response so the retry loop can inspect it.
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%v with status %d: %s", ErrRequestFailed, e.status, e.body)
+}
+
+func (e *statusError) Unwrap() error { return ErrRequestFailed }
+
+func (e *statusError) retryable() bool {
+	return e.status == http.StatusTooManyRequests || e.status >= http.StatusInternalServerError
+}
+
+// embedBatchWithRetry sends one batch, retrying with exponential backoff on
+// rate limiting, server errors, and transport failures.
+func (e *Embedder) embedBatchWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
+	backoff := initialBackoff
+	var lastErr error
+	for attempt := 0; attempt <= e.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		vectors, err := e.embedBatch(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		lastErr = err
+
+		var se *statusError
+		if errors.As(err, &se) && !se.retryable() {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, lastErr
+}
+
+// token returns the access token for the next request.
+func (e *Embedder) token(ctx context.Context) (string, error) {
+	if e.cfg.TokenSource != nil {
+		token, err := e.cfg.TokenSource(ctx)
+		if err != nil {
+			return "", fmt.Errorf("vertexai: failed to get access token: %w", err)
+		}
+		return token, nil
+	}
+	return e.cfg.AccessToken, nil
+}
+
+func (e *Embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body := predictRequest{Instances: make([]predictInstance, len(texts))}
+	for i, text := range texts {
+		body.Instances[i] = predictInstance{Content: text, TaskType: e.cfg.TaskType}
+	}
+	if e.cfg.Dimensions > 0 {
+		body.Parameters = &predictParameters{OutputDimensionality: e.cfg.Dimensions}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("vertexai: failed to marshal request: %w", err)
+	}
+
+	token, err := e.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/projects/%s/locations/%s/publishers/google/models/%s:predict",
+		e.cfg.BaseURL, e.cfg.Project, e.cfg.Location, e.cfg.Model)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("vertexai: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := e.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vertexai: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("vertexai: failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &statusError{status: resp.StatusCode, body: string(respBody)}
+	}
+
+	var parsed predictResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("vertexai: failed to parse response: %w", err)
+	}
+	if len(parsed.Predictions) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d for %d texts", cyborgdb.ErrEmbeddingCountMismatch, len(parsed.Predictions), len(texts))
+	}
+	vectors := make([][]float32, len(parsed.Predictions))
+	for i, p := range parsed.Predictions {
+		vectors[i] = p.Embeddings.Values
+	}
+	return vectors, nil
+}
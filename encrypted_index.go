@@ -5,7 +5,11 @@ package cyborgdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cyborginc/cyborgdb-go/internal"
 )
@@ -36,6 +40,10 @@ var (
 // The index maintains a persistent connection to the CyborgDB service and
 // caches metadata to avoid unnecessary API calls.
 //
+// An EncryptedIndex is safe for concurrent use by multiple goroutines; the
+// cached metadata is guarded internally, so one handle can be shared across a
+// server's request handlers.
+//
 // Instances should be created via Client.CreateIndex() or Client.LoadIndex().
 type EncryptedIndex struct {
 	// indexName is the unique identifier for this index
@@ -44,19 +52,55 @@ type EncryptedIndex struct {
 	// indexKey is the hex-encoded encryption key for end-to-end encryption
 	indexKey string
 
+	// mu guards the cached metadata below (indexType through autoTrain)
+	mu sync.RWMutex
+
 	// indexType indicates the index algorithm ("ivf", "ivfflat", "ivfpq")
 	indexType string
 
-	// config holds the detailed index configuration, may be nil for loaded indexes
+	// config holds the detailed index configuration
 	config *internal.IndexConfig
 
+	// nLists is the number of IVF clusters reported by the server, 0 if unknown
+	nLists int32
+
+	// metric is the distance metric, empty if unknown
+	metric string
+
 	// trained indicates whether the index has been optimized via training
 	trained bool
 
 	// client provides access to the underlying API client
 	client *internal.Client
+
+	// opts holds the client options, including default timeouts
+	opts *clientOptions
+
+	// embedder optionally embeds Contents and QueryContents client-side
+	embedder Embedder
+
+	// autoTrain is the automatic training policy, nil if disabled
+	autoTrain *autoTrainState
+
+	// schema is the index's metadata schema, nil if none; schemaLoaded
+	// records whether schema reflects the stored record
+	schema       *Schema
+	schemaLoaded bool
+
+	// hasSchemaIndex records that the schema side index is known to exist,
+	// saving a ListIndexes call per schema operation
+	hasSchemaIndex bool
+}
+
+// String describes the index without revealing its key, so handles can be
+// logged safely.
+func (e *EncryptedIndex) String() string {
+	return fmt.Sprintf("EncryptedIndex{name: %q, type: %q, key: %s}", e.indexName, e.GetIndexType(), redacted)
 }
 
+// GoString implements fmt.GoStringer without revealing the index key.
+func (e *EncryptedIndex) GoString() string { return e.String() }
+
 // GetIndexName returns the unique name of this index.
 //
 // This is a cached value that doesn't require an API call.
@@ -71,20 +115,74 @@ func (e *EncryptedIndex) GetIndexName() string { return e.indexName }
 //
 // Returns:
 //   - string: Index type ("ivf", "ivfflat", or "ivfpq")
-func (e *EncryptedIndex) GetIndexType() string { return e.indexType }
+func (e *EncryptedIndex) GetIndexType() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.indexType
+}
 
 // GetIndexConfig returns the detailed configuration of this index.
 //
-// This is a cached value that doesn't require an API call. For indexes
-// loaded via LoadIndex(), the configuration may be incomplete.
+// This is a cached value that doesn't require an API call. Use RefreshInfo()
+// to re-sync it with the server.
 //
 // Returns:
-//   - internal.IndexConfig: The index configuration, or empty if not available
-func (e *EncryptedIndex) GetIndexConfig() internal.IndexConfig {
-	if e.config != nil {
-		return *e.config
+//   - IndexConfig: The index configuration, or the zero value if not available
+func (e *EncryptedIndex) GetIndexConfig() IndexConfig {
+	return newIndexConfig(e.cachedConfig())
+}
+
+// cachedConfig returns the cached internal configuration, nil if unknown.
+// The configuration is replaced, never modified, so the result may be read
+// without holding the lock.
+func (e *EncryptedIndex) cachedConfig() *internal.IndexConfig {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config
+}
+
+// GetNLists returns the number of IVF clusters reported by the server.
+//
+// This is a cached value that doesn't require an API call. It is only known
+// for indexes loaded via LoadIndex() or refreshed via RefreshInfo().
+//
+// Returns:
+//   - int32: The number of IVF lists, or 0 if unknown
+func (e *EncryptedIndex) GetNLists() int32 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.nLists
+}
+
+// GetMetric returns the distance metric of this index.
+//
+// This is a cached value that doesn't require an API call. It is known for
+// indexes created with an explicit metric, or when reported by the server.
+//
+// Returns:
+//   - string: The distance metric (e.g. "euclidean", "cosine"), or "" if unknown
+func (e *EncryptedIndex) GetMetric() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.metric
+}
+
+// configDimension returns the vector dimension from the cached config, or 0 if unknown.
+func (e *EncryptedIndex) configDimension() int32 {
+	config := e.cachedConfig()
+	if config == nil {
+		return 0
+	}
+	switch {
+	case config.IndexIVFModel != nil:
+		return config.IndexIVFModel.GetDimension()
+	case config.IndexIVFFlatModel != nil:
+		return config.IndexIVFFlatModel.GetDimension()
+	case config.IndexIVFPQModel != nil:
+		return config.IndexIVFPQModel.GetDimension()
+	default:
+		return 0
 	}
-	return internal.IndexConfig{}
 }
 
 // IsTrained reports whether this index has been optimized through training.
@@ -94,7 +192,18 @@ func (e *EncryptedIndex) GetIndexConfig() internal.IndexConfig {
 //
 // Returns:
 //   - bool: true if the index has been trained, false otherwise
-func (e *EncryptedIndex) IsTrained() bool { return e.trained }
+func (e *EncryptedIndex) IsTrained() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.trained
+}
+
+// setTrained updates the cached trained status.
+func (e *EncryptedIndex) setTrained(trained bool) {
+	e.mu.Lock()
+	e.trained = trained
+	e.mu.Unlock()
+}
 
 // CheckTrainingStatus queries the server to check if this index is currently being trained
 // and updates the cached training status if training has completed.
@@ -103,52 +212,178 @@ func (e *EncryptedIndex) IsTrained() bool { return e.trained }
 //   - bool: true if the index is currently being trained, false otherwise
 //   - error: Any error encountered during the status check
 func (e *EncryptedIndex) CheckTrainingStatus(ctx context.Context) (bool, error) {
-	// Get training status from server
-	result, _, err := e.client.APIClient.DefaultAPI.GetTrainingStatusV1IndexesTrainingStatusGet(ctx).Execute()
+	statusMap, err := e.trainingStatus(ctx)
 	if err != nil {
-		return false, fmt.Errorf("failed to get training status: %w", err)
+		return false, err
 	}
 
 	// Parse the result to check if this index is being trained
-	if statusMap, ok := result.(map[string]interface{}); ok {
-		if trainingIndexes, ok := statusMap["training_indexes"].([]interface{}); ok {
-			isTraining := false
-			for _, idx := range trainingIndexes {
-				if idxName, ok := idx.(string); ok && idxName == e.indexName {
-					isTraining = true
-					break
-				}
-			}
-
-			// If not training anymore but was previously untrained, update the cached status
-			if !isTraining && !e.trained {
-				// Check if the index is actually trained by querying its info
-				describeReq := internal.IndexOperationRequest{
-					IndexName: e.indexName,
-					IndexKey:  e.indexKey,
-				}
-
-				resp, _, err := e.client.APIClient.DefaultAPI.GetIndexInfoV1IndexesDescribePost(ctx).
-					IndexOperationRequest(describeReq).
-					Execute()
-				if err == nil && resp != nil {
-					e.trained = resp.GetIsTrained()
-				}
+	if trainingIndexes, ok := statusMap["training_indexes"].([]interface{}); ok {
+		isTraining := false
+		for _, idx := range trainingIndexes {
+			if idxName, ok := idx.(string); ok && idxName == e.indexName {
+				isTraining = true
+				break
 			}
+		}
 
-			return isTraining, nil
+		// If not training anymore but was previously untrained, update the cached status
+		if !isTraining && !e.IsTrained() {
+			// Check if the index is actually trained by querying its info
+			_ = e.RefreshInfo(ctx)
 		}
+
+		return isTraining, nil
 	}
 
 	return false, ErrUnexpectedTrainingStatus
 }
 
+// trainingStatus fetches the server's training status document.
+func (e *EncryptedIndex) trainingStatus(ctx context.Context) (map[string]interface{}, error) {
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+
+	// Get training status from server
+	result, _, err := e.client.APIClient.DefaultAPI.GetTrainingStatusV1IndexesTrainingStatusGet(ctx).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get training status: %w", err)
+	}
+	statusMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, ErrUnexpectedTrainingStatus
+	}
+	return statusMap, nil
+}
+
+// RefreshInfo re-syncs the cached index metadata with the server.
+//
+// It calls the describe endpoint and updates the index type, configuration
+// (dimension, n_lists, PQ parameters), and trained status of this handle.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//
+// Returns:
+//   - error: Any error encountered while fetching index info
+func (e *EncryptedIndex) RefreshInfo(ctx context.Context) error {
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+
+	describeReq := internal.IndexOperationRequest{
+		IndexName: e.indexName,
+		IndexKey:  e.indexKey,
+	}
+
+	info, _, err := e.client.APIClient.DefaultAPI.GetIndexInfoV1IndexesDescribePost(ctx).
+		IndexOperationRequest(describeReq).
+		Execute()
+	if err != nil {
+		return fmt.Errorf("failed to get index info: %w", err)
+	}
+
+	e.applyIndexInfo(info)
+	return nil
+}
+
+// applyIndexInfo updates the cached metadata from a describe response.
+func (e *EncryptedIndex) applyIndexInfo(info *internal.IndexInfoResponseModel) {
+	if info == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.trained = info.IsTrained
+
+	indexType := strings.ToLower(info.IndexType)
+	if t, ok := info.IndexConfig["type"].(string); ok && indexType == "" {
+		indexType = strings.ToLower(t)
+	}
+	if indexType != "" {
+		e.indexType = indexType
+	}
+
+	if len(info.IndexConfig) == 0 {
+		return
+	}
+
+	if config := indexConfigFromMap(e.indexType, info.IndexConfig); config != nil {
+		e.config = config
+	}
+	if nLists, ok := configInt32(info.IndexConfig, "n_lists"); ok {
+		e.nLists = nLists
+	}
+	if metric, ok := info.IndexConfig["metric"].(string); ok && metric != "" {
+		e.metric = metric
+	}
+}
+
+// indexConfigFromMap converts the untyped index_config returned by the
+// describe endpoint into the typed internal.IndexConfig for the given index type.
+func indexConfigFromMap(indexType string, raw map[string]interface{}) *internal.IndexConfig {
+	dimension, hasDimension := configInt32(raw, "dimension")
+
+	switch indexType {
+	case "ivf":
+		model := internal.NewIndexIVFModel()
+		model.SetType(indexType)
+		if hasDimension {
+			model.SetDimension(dimension)
+		}
+		return &internal.IndexConfig{IndexIVFModel: model}
+	case "ivfflat":
+		model := internal.NewIndexIVFFlatModel()
+		model.SetType(indexType)
+		if hasDimension {
+			model.SetDimension(dimension)
+		}
+		return &internal.IndexConfig{IndexIVFFlatModel: model}
+	case "ivfpq":
+		pqDim, _ := configInt32(raw, "pq_dim")
+		pqBits, _ := configInt32(raw, "pq_bits")
+		model := internal.NewIndexIVFPQModel(pqDim, pqBits)
+		model.SetType(indexType)
+		if hasDimension {
+			model.SetDimension(dimension)
+		}
+		return &internal.IndexConfig{IndexIVFPQModel: model}
+	default:
+		return nil
+	}
+}
+
+// configInt32 reads a numeric value from an untyped config map.
+// JSON numbers decode as float64, so both float and integer kinds are accepted.
+func configInt32(raw map[string]interface{}, key string) (int32, bool) {
+	switch v := raw[key].(type) {
+	case float64:
+		return int32(v), true
+	case float32:
+		return int32(v), true
+	case int:
+		return int32(v), true
+	case int32:
+		return v, true
+	case int64:
+		return int32(v), true
+	default:
+		return 0, false
+	}
+}
+
 // Upsert inserts new vectors or updates existing ones in the index.
 //
 // Vector data is encrypted end-to-end before transmission. If a vector ID
 // already exists, it will be updated with the new vector data and metadata.
 // This operation is idempotent.
 //
+// If an Embedder is attached, items that carry string Contents but no Vector
+// are embedded client-side before the request is sent.
+//
+// Use UpsertWithResult to learn how many items were inserted or updated.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
 //   - items: Slice of VectorItem containing ID, vector, and optional metadata
@@ -164,25 +399,91 @@ func (e *EncryptedIndex) CheckTrainingStatus(ctx context.Context) (bool, error)
 //	}
 //	err := index.Upsert(ctx, items)
 func (e *EncryptedIndex) Upsert(ctx context.Context, items []VectorItem) error {
+	result, err := e.upsertItems(ctx, items)
+	if err != nil {
+		return err
+	}
+
+	// If training was triggered, we can note that the index is no longer trained
+	// (it will be retrained automatically)
+	if result.TrainingTriggered {
+		e.setTrained(false)
+	}
+
+	return nil
+}
+
+// upsertItems sends a single upsert request without touching cached state,
+// reporting what the server said about it. Inserted and Updated are not set.
+func (e *EncryptedIndex) upsertItems(ctx context.Context, items []VectorItem) (*UpsertResult, error) {
+	defer e.InvalidateQueryCache()
+	if e.opts.upsertRetries == 0 {
+		// With retries, the timeout bounds each attempt instead.
+		var cancel context.CancelFunc
+		ctx, cancel = withDefaultTimeout(ctx, e.opts.upsertTimeout)
+		defer cancel()
+	}
+
+	items, err := e.stampTimestamps(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.opts.validateItemsMetadata(items); err != nil {
+		return nil, err
+	}
+	if err := e.validateSchema(ctx, items); err != nil {
+		return nil, err
+	}
+	if items, err = e.hashItemsMetadata(items); err != nil {
+		return nil, err
+	}
+	if items, err = e.encryptItemsMetadata(items); err != nil {
+		return nil, err
+	}
+	if err := e.opts.checkItems(ctx, e.client, "vectors/upsert", len(items)); err != nil {
+		return nil, err
+	}
+	items, err = e.embedItems(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	req := internal.UpsertRequest{
 		IndexName: e.indexName,
 		IndexKey:  e.indexKey,
 		Items:     items,
 	}
-	resp, _, err := e.client.APIClient.DefaultAPI.UpsertVectorsV1VectorsUpsertPost(ctx).
-		UpsertRequest(req).
-		Execute()
+	start := time.Now()
+	resp, err := e.sendUpsertRetrying(ctx, req)
+	e.opts.audit(ctx, AuditUpsert, e.indexName, len(items), start, err)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// If training was triggered, we can note that the index is no longer trained
-	// (it will be retrained automatically)
-	if resp != nil && resp.HasTrainingTriggered() && resp.GetTrainingTriggered() {
-		e.trained = false
+	result := newUpsertResult(resp)
+	e.observeUpsert(ctx, len(items), result.TrainingTriggered)
+	return result, nil
+}
+
+// sendUpsert sends req using the configured vector encoding, falling back to
+// JSON when the server rejects packed vectors.
+func (e *EncryptedIndex) sendUpsert(ctx context.Context, req internal.UpsertRequest) (*internal.CyborgdbServiceApiSchemasVectorsSuccessResponseModel, error) {
+	packed := e.opts.vectorEncoding == VectorEncodingPacked && e.client.PackedSupported()
+	if packed {
+		resp, err := e.client.UpsertPacked(ctx, req)
+		if !errors.Is(err, internal.ErrPackedRejected) {
+			return resp, err
+		}
 	}
 
-	return nil
+	resp, _, err := e.client.APIClient.DefaultAPI.UpsertVectorsV1VectorsUpsertPost(ctx).
+		UpsertRequest(req).
+		Execute()
+	if err == nil && packed {
+		// The same request succeeded as JSON, so the rejection was the encoding.
+		e.client.DisablePacked()
+	}
+	return resp, err
 }
 
 // Query performs similarity search to find the nearest neighbors to query vector(s).
@@ -192,10 +493,17 @@ func (e *EncryptedIndex) Upsert(ctx context.Context, items []VectorItem) error {
 //   - Batch vector query: Set QueryParams.BatchQueryVectors
 //   - Content-based query: Set QueryParams.QueryContents (if supported by server)
 //
+// If an Embedder is attached, QueryContents is embedded client-side and sent
+// as a vector query instead. With RerankExact or MMRLambda, results are
+// re-ranked or diversified client-side, and MinScore drops weak matches.
+//
 // The search uses the distance metric specified during index creation.
 // Results are ordered by similarity (closest first) and can be filtered
 // by metadata using the Filters parameter.
 //
+// For vector queries, QueryOne and QueryBatch offer a typed alternative that
+// returns flat []QueryResult slices instead of the nested response union.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
 //   - params: QueryParams specifying query vectors, filters, and result preferences
@@ -214,8 +522,80 @@ func (e *EncryptedIndex) Upsert(ctx context.Context, items []VectorItem) error {
 //	}
 //	results, err := index.Query(ctx, params)
 func (e *EncryptedIndex) Query(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	if err := e.opts.checkInclude(params.Include); err != nil {
+		return nil, err
+	}
+	if err := e.opts.checkEncryptedFilter(params.Filters); err != nil {
+		return nil, err
+	}
+	filters, err := e.rewriteHashedFilter(params.Filters)
+	if err != nil {
+		return nil, err
+	}
+	params.Filters = filters
+	if params.Explain {
+		return e.queryExplain(ctx, params)
+	}
+	var resp *QueryResponse
+	if c := e.opts.queryCache; c != nil {
+		resp, err = c.query(ctx, e, params)
+	} else {
+		resp, err = e.query(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := e.decryptQueryResponse(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// queryExplain runs Query with a trace and attaches the QueryExplain.
+func (e *EncryptedIndex) queryExplain(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	ctx, trace := withQueryTrace(ctx)
+	params.Explain = false
+	start := time.Now()
+	resp, err := e.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	resp.Explain = trace.explain(e, params, resp, time.Since(start))
+	return resp, nil
+}
+
+// query performs Query without consulting the query cache.
+func (e *EncryptedIndex) query(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.queryTimeout)
+	defer cancel()
+
+	embedStart, hadContents := time.Now(), params.QueryContents != nil
+	params, err := e.embedQuery(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if hadContents && params.QueryContents == nil {
+		queryTraceFrom(ctx).embedded(embedStart)
+	}
+
+	if params.Offset > 0 {
+		return e.queryOffset(ctx, params)
+	}
+	if params.MinScore != nil {
+		return e.queryMinScore(ctx, params)
+	}
+	if params.RerankExact || params.MMRLambda != nil {
+		return e.queryPostProcess(ctx, params)
+	}
+	if params.SparseVector != nil {
+		return e.querySparse(ctx, params)
+	}
+
 	// Handle batch queries separately
 	if len(params.BatchQueryVectors) > 0 {
+		if err := e.opts.checkItems(ctx, e.client, "vectors/query", len(params.BatchQueryVectors)); err != nil {
+			return nil, err
+		}
 		batchReq := internal.BatchQueryRequest{
 			IndexName:    e.indexName,
 			IndexKey:     e.indexKey,
@@ -240,13 +620,35 @@ func (e *EncryptedIndex) Query(ctx context.Context, params QueryParams) (*QueryR
 		request := internal.Request{
 			BatchQueryRequest: &batchReq,
 		}
-		result, _, err := e.client.APIClient.DefaultAPI.QueryVectorsV1VectorsQueryPost(ctx).
+		start := time.Now()
+		result, httpResp, err := e.client.APIClient.DefaultAPI.QueryVectorsV1VectorsQueryPost(ctx).
 			Request(request).
 			Execute()
-		return result, err
+		queryTraceFrom(ctx).request(start, result)
+		if err != nil {
+			return nil, err
+		}
+		return result, redecodeQueryMetadata(e.opts.numberDecoding, httpResp, result)
 	}
 
 	// Handle single query
+	req := e.newQueryRequest(params)
+	request := internal.Request{
+		QueryRequest: &req,
+	}
+	start := time.Now()
+	result, httpResp, err := e.client.APIClient.DefaultAPI.QueryVectorsV1VectorsQueryPost(ctx).
+		Request(request).
+		Execute()
+	queryTraceFrom(ctx).request(start, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, redecodeQueryMetadata(e.opts.numberDecoding, httpResp, result)
+}
+
+// newQueryRequest builds the request for a single (non-batch) query.
+func (e *EncryptedIndex) newQueryRequest(params QueryParams) internal.QueryRequest {
 	req := internal.QueryRequest{
 		IndexName: e.indexName,
 		IndexKey:  e.indexKey,
@@ -274,13 +676,7 @@ func (e *EncryptedIndex) Query(ctx context.Context, params QueryParams) (*QueryR
 	if params.Greedy != nil {
 		req.Greedy = *internal.NewNullableBool(params.Greedy)
 	}
-	request := internal.Request{
-		QueryRequest: &req,
-	}
-	result, _, err := e.client.APIClient.DefaultAPI.QueryVectorsV1VectorsQueryPost(ctx).
-		Request(request).
-		Execute()
-	return result, err
+	return req
 }
 
 // Get retrieves specific vectors from the index by their IDs.
@@ -289,6 +685,10 @@ func (e *EncryptedIndex) Query(ctx context.Context, params QueryParams) (*QueryR
 // without performing similarity search. Useful for reconstructing original
 // data or examining specific vectors.
 //
+// Large ID lists are split into requests of at most DefaultGetChunkSize IDs,
+// sent with bounded concurrency, and merged back in chunk order. Use GetAll to
+// stream very large lists without holding every result in memory.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
 //   - ids: Slice of vector IDs to retrieve
@@ -304,18 +704,43 @@ func (e *EncryptedIndex) Query(ctx context.Context, params QueryParams) (*QueryR
 //	include := []string{"vector", "metadata"}
 //	results, err := index.Get(ctx, ids, include)
 func (e *EncryptedIndex) Get(ctx context.Context, ids []string, include []string) (*GetResponse, error) {
+	if len(ids) <= DefaultGetChunkSize {
+		return e.getChunk(ctx, ids, include)
+	}
+	return e.getChunked(ctx, ids, include)
+}
+
+// getChunk sends a single get request.
+func (e *EncryptedIndex) getChunk(ctx context.Context, ids []string, include []string) (*GetResponse, error) {
+	if err := e.opts.checkInclude(include); err != nil {
+		return nil, err
+	}
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+
 	req := internal.GetRequest{
 		IndexName: e.indexName,
 		IndexKey:  e.indexKey,
 		Ids:       ids,
 		Include:   include,
 	}
-	result, _, err := e.client.APIClient.DefaultAPI.GetVectorsV1VectorsGetPost(ctx).
+	result, httpResp, err := e.client.APIClient.DefaultAPI.GetVectorsV1VectorsGetPost(ctx).
 		GetRequest(req).
 		Execute()
 	if err != nil {
 		return nil, err
 	}
+	err = redecodeMetadata(e.opts.numberDecoding, httpResp, func(_, i int, m map[string]interface{}) {
+		if i < len(result.Results) {
+			result.Results[i].Metadata = m
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := e.decryptGetResponse(result); err != nil {
+		return nil, err
+	}
 	// Convert GetResponseModel to GetResponse
 	return result, nil
 }
@@ -324,7 +749,7 @@ func (e *EncryptedIndex) Get(ctx context.Context, ids []string, include []string
 //
 // This operation is irreversible. Deleted vectors are permanently removed
 // from the index and cannot be recovered. The operation succeeds even if
-// some IDs don't exist in the index.
+// some IDs don't exist in the index; use DeleteWithResult to learn which.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
@@ -338,14 +763,20 @@ func (e *EncryptedIndex) Get(ctx context.Context, ids []string, include []string
 //	ids := []string{"doc1", "doc2"}
 //	err := index.Delete(ctx, ids)
 func (e *EncryptedIndex) Delete(ctx context.Context, ids []string) error {
+	defer e.InvalidateQueryCache()
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+
 	req := internal.DeleteRequest{
 		IndexName: e.indexName,
 		IndexKey:  e.indexKey,
 		Ids:       ids,
 	}
+	start := time.Now()
 	_, _, err := e.client.APIClient.DefaultAPI.DeleteVectorsV1VectorsDeletePost(ctx).
 		DeleteRequest(req).
 		Execute()
+	e.opts.audit(ctx, AuditDelete, e.indexName, len(ids), start, err)
 	return err
 }
 
@@ -372,11 +803,15 @@ func (e *EncryptedIndex) Delete(ctx context.Context, ids []string) error {
 // Example:
 //
 //	params := TrainParams{
-//		BatchSize: &[]int32{1024}[0],  // Process 1024 vectors per batch
-//		MaxIters: &[]int32{200}[0],   // Allow up to 200 iterations
+//		BatchSize: Int32(1024), // Process 1024 vectors per batch
+//		MaxIters:  Int32(200),  // Allow up to 200 iterations
 //	}
 //	err := index.Train(ctx, params)
 func (e *EncryptedIndex) Train(ctx context.Context, params TrainParams) error {
+	defer e.InvalidateQueryCache()
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.trainTimeout)
+	defer cancel()
+
 	// Create request with required fields
 	req := internal.TrainRequest{
 		IndexKey:  e.indexKey,
@@ -407,11 +842,13 @@ func (e *EncryptedIndex) Train(ctx context.Context, params TrainParams) error {
 		req.NLists = *internal.NewNullableInt32(params.NLists)
 	}
 
+	start := time.Now()
 	_, _, err := e.client.APIClient.DefaultAPI.TrainIndexV1IndexesTrainPost(ctx).
 		TrainRequest(req).
 		Execute()
+	e.opts.audit(ctx, AuditTrain, e.indexName, 0, start, err)
 	if err == nil {
-		e.trained = true
+		e.setTrained(true)
 	}
 	return err
 }
@@ -435,14 +872,31 @@ func (e *EncryptedIndex) Train(ctx context.Context, params TrainParams) error {
 //	err := index.DeleteIndex(ctx)
 //	// index is now invalid and should not be used
 func (e *EncryptedIndex) DeleteIndex(ctx context.Context) error {
+	defer e.InvalidateQueryCache()
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+
 	req := internal.IndexOperationRequest{
 		IndexName: e.indexName,
 		IndexKey:  e.indexKey,
 	}
+	start := time.Now()
 	_, _, err := e.client.APIClient.DefaultAPI.DeleteIndexV1IndexesDeletePost(ctx).
 		IndexOperationRequest(req).
 		Execute()
-	return err
+	e.opts.audit(ctx, AuditDeleteIndex, e.indexName, 0, start, err)
+	if err != nil {
+		return err
+	}
+	if err := e.removeFromCatalog(ctx); err != nil {
+		return fmt.Errorf("index deleted but catalog entry not removed: %w", err)
+	}
+	if !isInternalIndex(e.indexName) {
+		if err := e.deleteSchemaIndex(ctx); err != nil {
+			return fmt.Errorf("index deleted but schema index %q not removed: %w", SchemaIndexName(e.indexName), err)
+		}
+	}
+	return nil
 }
 
 // ListIDs retrieves all vector IDs currently stored in the index.
@@ -454,8 +908,12 @@ func (e *EncryptedIndex) DeleteIndex(ctx context.Context) error {
 // For large indexes, this operation may take considerable time and return
 // a large response. Consider implementing pagination if needed.
 //
+// Use WithIDPrefix and WithMetadataFilter to list only some of the IDs, such
+// as one tenant's or one document's chunks.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeouts
+//   - opts: Optional ID prefix and metadata filter
 //
 // Returns:
 //   - *ListIDsResponse: Contains all vector IDs and total count
@@ -470,7 +928,22 @@ func (e *EncryptedIndex) DeleteIndex(ctx context.Context) error {
 //			fmt.Printf("Vector ID: %s\n", id)
 //		}
 //	}
-func (e *EncryptedIndex) ListIDs(ctx context.Context) (*ListIDsResponse, error) {
+func (e *EncryptedIndex) ListIDs(ctx context.Context, opts ...ListIDsOption) (*ListIDsResponse, error) {
+	var options listIDsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.prefix != "" || len(options.filter) > 0 {
+		return e.listIDsFiltered(ctx, options)
+	}
+	return e.listIDs(ctx)
+}
+
+// listIDs sends a single list request.
+func (e *EncryptedIndex) listIDs(ctx context.Context) (*ListIDsResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+
 	req := internal.ListIDsRequest{
 		IndexName: e.indexName,
 		IndexKey:  e.indexKey,
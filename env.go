@@ -0,0 +1,66 @@
+// env.go constructs a Client from environment variables.
+package cyborgdb
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	// EnvBaseURL holds the service URL, e.g. "https://cyborgdb.example.com". Required.
+	EnvBaseURL = "CYBORGDB_BASE_URL"
+	// EnvAPIKey holds the API key. Required.
+	EnvAPIKey = "CYBORGDB_API_KEY"
+	// EnvVerifySSL forces TLS verification on or off ("true"/"false"). Optional;
+	// auto-detected from the URL when unset.
+	EnvVerifySSL = "CYBORGDB_VERIFY_SSL"
+	// EnvProxy holds a proxy URL for service requests. Optional; when unset the
+	// standard HTTPS_PROXY, HTTP_PROXY, and NO_PROXY variables are honored.
+	EnvProxy = "CYBORGDB_PROXY"
+)
+
+// ErrMissingEnv is returned by NewClientFromEnv when a required variable is unset.
+var ErrMissingEnv = fmt.Errorf("missing required environment variable")
+
+// NewClientFromEnv constructs a client configured from environment variables:
+// CYBORGDB_BASE_URL and CYBORGDB_API_KEY (required), CYBORGDB_VERIFY_SSL, and
+// CYBORGDB_PROXY or the standard proxy variables.
+//
+// Options in opts are applied after the environment, so they take precedence.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientFromEnv(cyborgdb.WithQueryTimeout(5 * time.Second))
+//	if err != nil {
+//		log.Fatal(err) // e.g. "missing required environment variable: CYBORGDB_API_KEY"
+//	}
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	baseURL := os.Getenv(EnvBaseURL)
+	if baseURL == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMissingEnv, EnvBaseURL)
+	}
+	apiKey := os.Getenv(EnvAPIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMissingEnv, EnvAPIKey)
+	}
+
+	var envOpts []ClientOption
+	if v := os.Getenv(EnvVerifySSL); v != "" {
+		verify, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: must be true or false", EnvVerifySSL, v)
+		}
+		envOpts = append(envOpts, WithVerifySSL(verify))
+	}
+
+	if v := os.Getenv(EnvProxy); v != "" {
+		envOpts = append(envOpts, WithProxy(v))
+	} else {
+		envOpts = append(envOpts, withProxyFunc(http.ProxyFromEnvironment))
+	}
+
+	return NewClientWithOptions(baseURL, apiKey, append(envOpts, opts...)...)
+}
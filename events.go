@@ -0,0 +1,206 @@
+// events.go delivers index lifecycle events, either by polling the service
+// or from server webhooks, so applications can invalidate local state when
+// an index's structure changes.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultEventPollInterval is the polling interval used by WatchIndexEvents
+	// when interval is not positive.
+	DefaultEventPollInterval = 10 * time.Second
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of a webhook body.
+	WebhookSignatureHeader = "X-CyborgDB-Signature"
+	// maxWebhookBody bounds the size of an accepted webhook request.
+	maxWebhookBody = 1 << 20
+)
+
+// IndexEventType identifies a lifecycle event.
+type IndexEventType string
+
+const (
+	// EventTrainingStarted is sent when an index starts training.
+	EventTrainingStarted IndexEventType = "training_started"
+	// EventTrainingCompleted is sent when an index stops training.
+	EventTrainingCompleted IndexEventType = "training_completed"
+	// EventIndexDeleted is sent when an index is deleted.
+	EventIndexDeleted IndexEventType = "index_deleted"
+)
+
+// IndexEvent is a lifecycle event for one index.
+type IndexEvent struct {
+	Type      IndexEventType `json:"type"`
+	IndexName string         `json:"index_name"`
+	Time      time.Time      `json:"timestamp"`
+}
+
+// IndexEventHandlers receives lifecycle events. Nil callbacks are skipped.
+// Callbacks run on the watcher's or HTTP handler's goroutine.
+type IndexEventHandlers struct {
+	OnTrainingStarted   func(IndexEvent)
+	OnTrainingCompleted func(IndexEvent)
+	OnIndexDeleted      func(IndexEvent)
+}
+
+// dispatch calls the callback for ev's type, if set.
+func (h *IndexEventHandlers) dispatch(ev IndexEvent) {
+	var fn func(IndexEvent)
+	switch ev.Type {
+	case EventTrainingStarted:
+		fn = h.OnTrainingStarted
+	case EventTrainingCompleted:
+		fn = h.OnTrainingCompleted
+	case EventIndexDeleted:
+		fn = h.OnIndexDeleted
+	}
+	if fn != nil {
+		fn(ev)
+	}
+}
+
+// WatchIndexEvents polls the service every interval (DefaultEventPollInterval
+// if <= 0) and delivers lifecycle events for all indexes until ctx is done.
+// Events are derived from changes between polls, so a training run shorter
+// than the interval may go unnoticed. Transient poll errors are skipped.
+//
+// Parameters:
+//   - ctx: Context that stops the watcher
+//   - interval: Delay between polls
+//   - handlers: Event callbacks
+//
+// Returns:
+//   - error: ctx.Err() once the watcher stops
+//
+// Example:
+//
+//	go client.WatchIndexEvents(ctx, 0, cyborgdb.IndexEventHandlers{
+//		OnTrainingCompleted: func(ev cyborgdb.IndexEvent) { index.RefreshInfo(ctx) },
+//	})
+func (c *Client) WatchIndexEvents(ctx context.Context, interval time.Duration, handlers IndexEventHandlers) error {
+	if interval <= 0 {
+		interval = DefaultEventPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prevIndexes, prevTraining map[string]bool
+	for {
+		indexes, training, err := c.pollIndexState(ctx)
+		if err == nil {
+			now := time.Now()
+			if prevIndexes != nil {
+				for name := range training {
+					if !prevTraining[name] {
+						handlers.dispatch(IndexEvent{Type: EventTrainingStarted, IndexName: name, Time: now})
+					}
+				}
+				for name := range prevTraining {
+					if !training[name] && indexes[name] {
+						handlers.dispatch(IndexEvent{Type: EventTrainingCompleted, IndexName: name, Time: now})
+					}
+				}
+				for name := range prevIndexes {
+					if !indexes[name] {
+						handlers.dispatch(IndexEvent{Type: EventIndexDeleted, IndexName: name, Time: now})
+					}
+				}
+			}
+			prevIndexes, prevTraining = indexes, training
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollIndexState returns the set of indexes and the set currently training.
+func (c *Client) pollIndexState(ctx context.Context) (indexes, training map[string]bool, err error) {
+	names, err := c.ListIndexes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	indexes = make(map[string]bool, len(names))
+	for _, name := range names {
+		indexes[name] = true
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx, c.opts.operationTimeout)
+	defer cancel()
+	result, _, err := c.internal.APIClient.DefaultAPI.GetTrainingStatusV1IndexesTrainingStatusGet(ctx).Execute()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get training status: %w", err)
+	}
+	statusMap, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, nil, ErrUnexpectedTrainingStatus
+	}
+	list, ok := statusMap["training_indexes"].([]interface{})
+	if !ok {
+		return nil, nil, ErrUnexpectedTrainingStatus
+	}
+	training = make(map[string]bool, len(list))
+	for _, v := range list {
+		if name, ok := v.(string); ok {
+			training[name] = true
+		}
+	}
+	return indexes, training, nil
+}
+
+// NewWebhookHandler returns an http.Handler that accepts lifecycle events
+// POSTed by the server as JSON IndexEvent bodies and delivers them to
+// handlers. If secret is non-empty, requests must carry the hex HMAC-SHA256
+// of the body under secret in WebhookSignatureHeader; others are rejected
+// with 401.
+//
+// Example:
+//
+//	http.Handle("/hooks/cyborgdb", cyborgdb.NewWebhookHandler(handlers, []byte(os.Getenv("WEBHOOK_SECRET"))))
+func NewWebhookHandler(handlers IndexEventHandlers, secret []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		if len(secret) > 0 {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(body)
+			got, err := hex.DecodeString(r.Header.Get(WebhookSignatureHeader))
+			if err != nil || !hmac.Equal(got, mac.Sum(nil)) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var ev IndexEvent
+		if err := json.Unmarshal(body, &ev); err != nil || ev.Type == "" || ev.IndexName == "" {
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+		if ev.Time.IsZero() {
+			ev.Time = time.Now()
+		}
+		handlers.dispatch(ev)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,98 @@
+// explain.go derives QueryExplain diagnostics for queries run with
+// QueryParams.Explain.
+package cyborgdb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// queryTraceKey is the context key of the active queryTrace.
+type queryTraceKey struct{}
+
+// queryTrace accumulates what a single Query call did.
+type queryTrace struct {
+	mu         sync.Mutex
+	requests   int
+	candidates int
+	server     time.Duration
+	embed      time.Duration
+}
+
+// withQueryTrace returns ctx carrying a new trace.
+func withQueryTrace(ctx context.Context) (context.Context, *queryTrace) {
+	t := &queryTrace{}
+	return context.WithValue(ctx, queryTraceKey{}, t), t
+}
+
+// queryTraceFrom returns the trace in ctx, or nil when not explaining.
+func queryTraceFrom(ctx context.Context) *queryTrace {
+	t, _ := ctx.Value(queryTraceKey{}).(*queryTrace)
+	return t
+}
+
+// request records a query request started at start that returned resp.
+func (t *queryTrace) request(start time.Time, resp *QueryResponse) {
+	if t == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests++
+	t.server += elapsed
+	t.candidates += countResults(resp)
+}
+
+// embedded records client-side query embedding started at start.
+func (t *queryTrace) embedded(start time.Time) {
+	if t == nil {
+		return
+	}
+	elapsed := time.Since(start)
+	t.mu.Lock()
+	t.embed += elapsed
+	t.mu.Unlock()
+}
+
+// explain builds the QueryExplain for resp, a query with params that took
+// total.
+func (t *queryTrace) explain(e *EncryptedIndex, params QueryParams, resp *QueryResponse, total time.Duration) *QueryExplain {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := &QueryExplain{
+		NLists:     e.GetNLists(),
+		Candidates: t.candidates,
+		Returned:   countResults(resp),
+		Requests:   t.requests,
+		Cached:     t.requests == 0 && e.opts.queryCache != nil,
+		Timing: QueryTiming{
+			Total:  total,
+			Embed:  t.embed,
+			Server: t.server,
+		},
+	}
+	if client := total - t.embed - t.server; client > 0 {
+		out.Timing.Client = client
+	}
+	if params.NProbes != nil {
+		out.NProbes = *params.NProbes
+		out.ListsScanned = out.NProbes
+		if out.NLists > 0 && out.ListsScanned > out.NLists {
+			out.ListsScanned = out.NLists
+		}
+	}
+	return out
+}
+
+// countResults returns the number of result items across all result lists.
+func countResults(resp *QueryResponse) int {
+	n := 0
+	for i := 0; i < resp.Len(); i++ {
+		items, _ := resp.At(i)
+		n += len(items)
+	}
+	return n
+}
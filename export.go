@@ -0,0 +1,91 @@
+// export.go implements bulk export of an encrypted index to a record stream.
+package cyborgdb
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// exportBatchSize is the number of IDs fetched per Get call during export.
+const exportBatchSize = 500
+
+// Export streams every vector in the index to w in the given format.
+//
+// Contents are exported as strings. The service returns binary contents
+// (see BinaryContents) base64-encoded, and they are exported in that form;
+// importing the record stores the same string, which ContentsBytes decodes.
+//
+// IDs are enumerated with ListIDs and the items are fetched in chunks with
+// Get, so memory use is bounded by the chunk size rather than the index size.
+// Useful for backups, offline analysis, and migration.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - w: Destination for the serialized records
+//   - format: Output format (FormatJSONL, FormatParquet, or a registered format)
+//   - include: Fields to export in addition to the ID ("vector", "metadata", "contents")
+//
+// Returns:
+//   - int: Number of records written
+//   - error: Any error encountered; records already written remain in w
+//
+// Example:
+//
+//	f, _ := os.Create("backup.jsonl")
+//	defer f.Close()
+//	n, err := index.Export(ctx, f, cyborgdb.FormatJSONL, []string{"vector", "metadata", "contents"})
+func (e *EncryptedIndex) Export(ctx context.Context, w io.Writer, format RecordFormat, include []string) (int, error) {
+	rw, err := newRecordWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	listed, err := e.ListIDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list IDs for export: %w", err)
+	}
+
+	written := 0
+	for start := 0; start < len(listed.Ids); start += exportBatchSize {
+		end := start + exportBatchSize
+		if end > len(listed.Ids) {
+			end = len(listed.Ids)
+		}
+
+		resp, err := e.Get(ctx, listed.Ids[start:end], include)
+		if err != nil {
+			return written, fmt.Errorf("failed to fetch items for export: %w", err)
+		}
+
+		for _, item := range resp.Results {
+			rec := Record{
+				ID:       item.Id,
+				Vector:   item.Vector,
+				Metadata: item.Metadata,
+			}
+			if text, ok := exportContents(item.Contents); ok {
+				rec.Contents = &text
+			}
+			if err := rw.WriteRecord(rec); err != nil {
+				return written, fmt.Errorf("failed to write record %q: %w", item.Id, err)
+			}
+			written++
+		}
+	}
+
+	if err := rw.Flush(); err != nil {
+		return written, fmt.Errorf("failed to flush export: %w", err)
+	}
+	return written, nil
+}
+
+// exportContents returns contents as a string: text as is, and binary
+// contents base64-encoded.
+func exportContents(contents NullableContents) (string, bool) {
+	if c := contents.Get(); c != nil && c.Bytes != nil {
+		return base64.StdEncoding.EncodeToString(c.Bytes), true
+	}
+	return contentsText(contents)
+}
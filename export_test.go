@@ -0,0 +1,50 @@
+package cyborgdb_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestExportBinaryContents(t *testing.T) {
+	_, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	blob := []byte{0xff, 0x00, 0xfe}
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "bin", Vector: []float32{1, 0}, Contents: cyborgdb.BinaryContents(blob)},
+		{Id: "text", Vector: []float32{0, 1}, Contents: cyborgdb.TextContents("hello")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	n, err := index.Export(ctx, &out, cyborgdb.FormatJSONL, []string{"vector", "contents"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("exported %d records, want 2", n)
+	}
+	contents := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var rec cyborgdb.Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Contents != nil {
+			contents[rec.ID] = *rec.Contents
+		}
+	}
+	if want := base64.StdEncoding.EncodeToString(blob); contents["bin"] != want {
+		t.Errorf("binary contents = %q, want %q", contents["bin"], want)
+	}
+	if contents["text"] != "hello" {
+		t.Errorf("text contents = %q, want \"hello\"", contents["text"])
+	}
+}
@@ -0,0 +1,260 @@
+package cyborgdb_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// fakeService is an in-memory stand-in for the CyborgDB service, enough to
+// exercise the client-side features built on top of its API. Queries rank
+// items by Euclidean distance and support equality and $in filters on
+// top-level fields.
+type fakeService struct {
+	mu      sync.Mutex
+	indexes map[string]*fakeIndex
+	// requests counts requests by path.
+	requests map[string]int
+	// queries records the filters of every query.
+	queries []map[string]interface{}
+}
+
+type fakeIndex struct {
+	key    string
+	config map[string]interface{}
+	items  map[string]map[string]interface{}
+	// trained is reported by describe; the fake cannot train.
+	trained bool
+}
+
+// newFakeService starts a fake service and returns a client for it.
+func newFakeService(t *testing.T, opts ...cyborgdb.ClientOption) (*fakeService, *cyborgdb.Client) {
+	t.Helper()
+	f := &fakeService{indexes: make(map[string]*fakeIndex), requests: make(map[string]int)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "test-key", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+// index returns the named index, or nil.
+func (f *fakeService) index(name string) *fakeIndex {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.indexes[name]
+}
+
+// item returns a copy of a stored item, or nil.
+func (f *fakeService) item(index, id string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if idx := f.indexes[index]; idx != nil {
+		return idx.items[id]
+	}
+	return nil
+}
+
+// indexNames returns the names of every index, sorted.
+func (f *fakeService) indexNames() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(f.indexes))
+	for name := range f.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *fakeService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests[r.URL.Path]++
+
+	var req struct {
+		IndexName    string                   `json:"index_name"`
+		IndexKey     string                   `json:"index_key"`
+		IndexConfig  map[string]interface{}   `json:"index_config"`
+		Items        []map[string]interface{} `json:"items"`
+		Ids          []string                 `json:"ids"`
+		Include      []string                 `json:"include"`
+		QueryVectors []float64                `json:"query_vectors"`
+		TopK         int                      `json:"top_k"`
+		Filters      map[string]interface{}   `json:"filters"`
+	}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			fakeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	}
+
+	switch r.URL.Path {
+	case "/v1/indexes/list":
+		names := make([]string, 0, len(f.indexes))
+		for name := range f.indexes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fakeJSON(w, map[string]interface{}{"indexes": names})
+		return
+	case "/v1/indexes/create":
+		if _, ok := f.indexes[req.IndexName]; ok {
+			fakeError(w, http.StatusConflict, "index already exists")
+			return
+		}
+		f.indexes[req.IndexName] = &fakeIndex{key: req.IndexKey, config: req.IndexConfig, items: make(map[string]map[string]interface{})}
+		fakeJSON(w, map[string]interface{}{"status": "success", "message": "created"})
+		return
+	}
+
+	idx := f.indexes[req.IndexName]
+	if idx == nil {
+		fakeError(w, http.StatusNotFound, "index not found")
+		return
+	}
+	if idx.key != req.IndexKey {
+		fakeError(w, http.StatusUnauthorized, "wrong index key")
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/indexes/describe":
+		config := idx.config
+		if config == nil {
+			config = map[string]interface{}{"type": "ivfflat", "dimension": 2}
+		}
+		fakeJSON(w, map[string]interface{}{
+			"index_name": req.IndexName, "index_type": config["type"], "is_trained": idx.trained, "index_config": config,
+		})
+	case "/v1/indexes/delete":
+		delete(f.indexes, req.IndexName)
+		fakeJSON(w, map[string]interface{}{"status": "success", "message": "deleted"})
+	case "/v1/vectors/upsert":
+		for _, item := range req.Items {
+			idx.items[item["id"].(string)] = item
+		}
+		fakeJSON(w, map[string]interface{}{"status": "success", "message": "upserted"})
+	case "/v1/vectors/delete":
+		for _, id := range req.Ids {
+			delete(idx.items, id)
+		}
+		fakeJSON(w, map[string]interface{}{"status": "success", "message": "deleted"})
+	case "/v1/vectors/list_ids":
+		ids := make([]string, 0, len(idx.items))
+		for id := range idx.items {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fakeJSON(w, map[string]interface{}{"ids": ids, "count": len(ids)})
+	case "/v1/vectors/get":
+		results := []map[string]interface{}{}
+		for _, id := range req.Ids {
+			if item, ok := idx.items[id]; ok {
+				results = append(results, item)
+			}
+		}
+		fakeJSON(w, map[string]interface{}{"results": results})
+	case "/v1/vectors/query":
+		f.queries = append(f.queries, req.Filters)
+		fakeJSON(w, map[string]interface{}{"results": idx.query(req.QueryVectors, req.TopK, req.Filters, req.Include)})
+	default:
+		fakeError(w, http.StatusNotFound, "unknown path "+r.URL.Path)
+	}
+}
+
+// query returns the topK items closest to vector that match filters, with
+// their vectors if include lists "vector".
+func (idx *fakeIndex) query(vector []float64, topK int, filters map[string]interface{}, include []string) []map[string]interface{} {
+	type hit struct {
+		item     map[string]interface{}
+		distance float64
+	}
+	var hits []hit
+	for _, item := range idx.items {
+		meta, _ := item["metadata"].(map[string]interface{})
+		if !fakeMatch(meta, filters) {
+			continue
+		}
+		var sum float64
+		stored, _ := item["vector"].([]interface{})
+		for i, v := range stored {
+			if i < len(vector) {
+				d := v.(float64) - vector[i]
+				sum += d * d
+			}
+		}
+		hits = append(hits, hit{item, math.Sqrt(sum)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].distance != hits[j].distance {
+			return hits[i].distance < hits[j].distance
+		}
+		return hits[i].item["id"].(string) < hits[j].item["id"].(string)
+	})
+	if topK <= 0 {
+		topK = 100
+	}
+	results := []map[string]interface{}{}
+	for i := 0; i < len(hits) && i < topK; i++ {
+		result := map[string]interface{}{
+			"id": hits[i].item["id"], "distance": hits[i].distance, "metadata": hits[i].item["metadata"],
+		}
+		for _, field := range include {
+			if field == "vector" {
+				result["vector"] = hits[i].item["vector"]
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// fakeMatch reports whether meta matches filters' equality and $in
+// conditions.
+func fakeMatch(meta, filters map[string]interface{}) bool {
+	for field, cond := range filters {
+		value := meta[field]
+		ops, ok := cond.(map[string]interface{})
+		if !ok {
+			ops = map[string]interface{}{"$eq": cond}
+		}
+		for op, operand := range ops {
+			switch op {
+			case "$eq":
+				if value != operand {
+					return false
+				}
+			case "$in":
+				found := false
+				for _, v := range operand.([]interface{}) {
+					found = found || value == v
+				}
+				if !found {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func fakeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"detail": detail})
+}
@@ -0,0 +1,378 @@
+// fieldstats.go implements sampling of stored metadata to report per-field
+// value distributions, which helps design filters and spot stray fields.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultStatsSampleSize is the number of items sampled by MetadataStats
+	// when MetadataStatsOptions.SampleSize is not set.
+	DefaultStatsSampleSize = 1000
+	// DefaultStatsTopValues is the number of most frequent values reported
+	// per field when MetadataStatsOptions.TopValues is not set.
+	DefaultStatsTopValues = 10
+	// maxTrackedValues bounds the distinct values counted per field.
+	maxTrackedValues = 10000
+	// maxTypoDistance is the largest edit distance between field names
+	// reported as a suspected typo; names shorter than 8 characters allow
+	// only one edit.
+	maxTypoDistance = 2
+)
+
+// MetadataStatsOptions configures MetadataStats. Zero values fall back to
+// defaults.
+type MetadataStatsOptions struct {
+	// SampleSize is the number of items examined. Default:
+	// DefaultStatsSampleSize. Negative examines every item.
+	SampleSize int
+
+	// TopValues is the number of most frequent values reported per field.
+	// Default: DefaultStatsTopValues.
+	TopValues int
+
+	// Seed makes the sample reproducible. Default: a time-based seed.
+	Seed int64
+}
+
+// MetadataStats describes the metadata of a sample of an index's items.
+type MetadataStats struct {
+	// TotalItems is the number of items in the index.
+	TotalItems int `json:"total_items"`
+
+	// Sampled is the number of items examined.
+	Sampled int `json:"sampled"`
+
+	// Fields describes every field seen, most common first. Nested fields
+	// are named with dotted paths such as "author.name".
+	Fields []FieldStats `json:"fields"`
+
+	// SuspectedTypos lists rare fields whose names are close to a more
+	// common field, such as "catagory" next to "category".
+	SuspectedTypos []FieldTypo `json:"suspected_typos,omitempty"`
+}
+
+// FieldStats describes one metadata field within the sample.
+type FieldStats struct {
+	// Name is the field's dotted path.
+	Name string `json:"name"`
+
+	// Count is the number of sampled items with the field.
+	Count int `json:"count"`
+
+	// Coverage is Count divided by the number of items sampled.
+	Coverage float64 `json:"coverage"`
+
+	// Types counts the field's values by JSON type: "string", "number",
+	// "boolean", "list", "object", or "null".
+	Types map[string]int `json:"types"`
+
+	// Cardinality is the number of distinct values seen; list elements
+	// count individually and objects are not counted.
+	Cardinality int `json:"cardinality"`
+
+	// CardinalityCapped reports that counting stopped at 10000 distinct
+	// values, so Cardinality is a lower bound.
+	CardinalityCapped bool `json:"cardinality_capped,omitempty"`
+
+	// TopValues are the most frequent values, most frequent first.
+	TopValues []ValueCount `json:"top_values,omitempty"`
+
+	// Min and Max bound the numeric values, nil if there are none.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// ValueCount is a metadata value and the number of times it was seen.
+type ValueCount struct {
+	Value interface{} `json:"value"`
+	Count int         `json:"count"`
+}
+
+// FieldTypo pairs a rare field with the common field it likely misspells.
+type FieldTypo struct {
+	// Field is the rare field name.
+	Field string `json:"field"`
+	// Similar is the more common field name it resembles.
+	Similar string `json:"similar"`
+	// Distance is the edit distance between the two names.
+	Distance int `json:"distance"`
+}
+
+// MetadataStats samples items at random and reports, for every metadata
+// field, how often it is set, its value types, its cardinality, its most
+// frequent values, and its numeric range. Fields with low cardinality suit
+// equality filters; a field seen on a handful of items whose name is close
+// to a common one is reported in SuspectedTypos.
+//
+// The service has no aggregation endpoint, so the sample's metadata is
+// fetched with Get.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - opts: Sample size, number of top values, and seed
+//
+// Returns:
+//   - *MetadataStats: Per-field statistics of the sample
+//   - error: Any error listing or fetching items
+//
+// Example:
+//
+//	stats, err := index.MetadataStats(ctx, cyborgdb.MetadataStatsOptions{SampleSize: 5000})
+//	for _, f := range stats.Fields {
+//		fmt.Printf("%-20s %5.1f%%  %d distinct\n", f.Name, 100*f.Coverage, f.Cardinality)
+//	}
+//	for _, t := range stats.SuspectedTypos {
+//		fmt.Printf("%q looks like a typo of %q\n", t.Field, t.Similar)
+//	}
+func (e *EncryptedIndex) MetadataStats(ctx context.Context, opts MetadataStatsOptions) (*MetadataStats, error) {
+	sampleSize := opts.SampleSize
+	if sampleSize == 0 {
+		sampleSize = DefaultStatsSampleSize
+	}
+	topValues := opts.TopValues
+	if topValues <= 0 {
+		topValues = DefaultStatsTopValues
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	listed, err := e.listIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := listed.Ids
+	stats := &MetadataStats{TotalItems: len(ids), Fields: []FieldStats{}}
+
+	// Partial Fisher-Yates shuffle: the first sampleSize IDs are the sample.
+	if sampleSize > 0 && sampleSize < len(ids) {
+		rng := rand.New(rand.NewSource(seed))
+		for i := 0; i < sampleSize; i++ {
+			j := i + rng.Intn(len(ids)-i)
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+		ids = ids[:sampleSize]
+	}
+	if len(ids) == 0 {
+		return stats, nil
+	}
+
+	resp, err := e.Get(ctx, ids, []string{"metadata"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	acc := make(map[string]*fieldAccumulator)
+	for _, item := range resp.Results {
+		collectFields(acc, "", item.Metadata)
+	}
+	stats.Sampled = len(resp.Results)
+
+	for _, a := range acc {
+		stats.Fields = append(stats.Fields, a.stats(stats.Sampled, topValues))
+	}
+	sort.Slice(stats.Fields, func(i, j int) bool {
+		if stats.Fields[i].Count != stats.Fields[j].Count {
+			return stats.Fields[i].Count > stats.Fields[j].Count
+		}
+		return stats.Fields[i].Name < stats.Fields[j].Name
+	})
+	stats.SuspectedTypos = suspectTypos(stats.Fields)
+	return stats, nil
+}
+
+// fieldAccumulator gathers one field's statistics.
+type fieldAccumulator struct {
+	name     string
+	count    int
+	types    map[string]int
+	values   map[string]*ValueCount
+	capped   bool
+	min, max *float64
+}
+
+// collectFields adds every field of metadata, under prefix, to acc.
+func collectFields(acc map[string]*fieldAccumulator, prefix string, metadata map[string]interface{}) {
+	for key, value := range metadata {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		a := acc[name]
+		if a == nil {
+			a = &fieldAccumulator{name: name, types: make(map[string]int), values: make(map[string]*ValueCount)}
+			acc[name] = a
+		}
+		a.count++
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			a.types["object"]++
+			collectFields(acc, name, v)
+		case []interface{}:
+			a.types["list"]++
+			for _, elem := range v {
+				a.addValue(elem)
+			}
+		default:
+			a.types[jsonTypeName(value)]++
+			a.addValue(value)
+		}
+	}
+}
+
+// addValue counts one scalar value.
+func (a *fieldAccumulator) addValue(value interface{}) {
+	var key string
+	if n, ok := filterNumber(value); ok {
+		if a.min == nil || n < *a.min {
+			a.min = &n
+		}
+		if a.max == nil || n > *a.max {
+			a.max = &n
+		}
+		key = fmt.Sprintf("n:%v", n)
+		value = n
+	} else {
+		switch value.(type) {
+		case string, bool, nil:
+			key = fmt.Sprintf("%T:%v", value, value)
+		default:
+			// Nested lists and objects inside lists are not counted.
+			return
+		}
+	}
+
+	if vc, ok := a.values[key]; ok {
+		vc.Count++
+		return
+	}
+	if len(a.values) >= maxTrackedValues {
+		a.capped = true
+		return
+	}
+	a.values[key] = &ValueCount{Value: value, Count: 1}
+}
+
+// stats finalizes the accumulated statistics.
+func (a *fieldAccumulator) stats(sampled, topValues int) FieldStats {
+	fs := FieldStats{
+		Name:              a.name,
+		Count:             a.count,
+		Coverage:          float64(a.count) / float64(sampled),
+		Types:             a.types,
+		Cardinality:       len(a.values),
+		CardinalityCapped: a.capped,
+		Min:               a.min,
+		Max:               a.max,
+	}
+	values := make([]ValueCount, 0, len(a.values))
+	for _, vc := range a.values {
+		values = append(values, *vc)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return fmt.Sprint(values[i].Value) < fmt.Sprint(values[j].Value)
+	})
+	if len(values) > topValues {
+		values = values[:topValues]
+	}
+	if len(values) > 0 {
+		fs.TopValues = values
+	}
+	return fs
+}
+
+// jsonTypeName names the JSON type of a scalar metadata value.
+func jsonTypeName(value interface{}) string {
+	if _, ok := filterNumber(value); ok {
+		return "number"
+	}
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// suspectTypos pairs each field with a field whose name is within
+// maxTypoDistance edits and which is at least twice as common. Fields are
+// ordered most common first.
+func suspectTypos(fields []FieldStats) []FieldTypo {
+	var typos []FieldTypo
+	for i := len(fields) - 1; i >= 0; i-- {
+		rare := fields[i]
+		if len(rare.Name) < 4 {
+			continue
+		}
+		for _, common := range fields[:i] {
+			if common.Count < 2*rare.Count || len(common.Name) < 4 {
+				continue
+			}
+			limit := maxTypoDistance
+			if len(rare.Name) < 8 {
+				limit = 1
+			}
+			if d := editDistance(rare.Name, common.Name, limit); d <= limit {
+				typos = append(typos, FieldTypo{Field: rare.Name, Similar: common.Name, Distance: d})
+				break
+			}
+		}
+	}
+	return typos
+}
+
+// editDistance returns the Levenshtein distance between a and b, or
+// limit+1 once it is known to exceed limit.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < best {
+				best = curr[j]
+			}
+		}
+		if best > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
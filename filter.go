@@ -0,0 +1,258 @@
+// filter.go implements client-side evaluation of metadata filters, for
+// operations the service does not filter itself.
+package cyborgdb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MatchFilter reports whether metadata satisfies filter, using the same
+// syntax as QueryParams.Filters:
+//
+//   - {"field": value} and {"field": {"$eq": value}} match equal values
+//   - $ne, $gt, $gte, $lt, $lte compare numbers or strings
+//   - $in and $nin test membership in a list
+//   - $exists tests whether the field is set
+//   - $and and $or combine lists of filters
+//
+// Nested fields are addressed with dotted names such as "author.name". A nil
+// or empty filter matches everything.
+//
+// Parameters:
+//   - filter: The metadata filter
+//   - metadata: The item's metadata
+//
+// Returns:
+//   - bool: Whether metadata matches
+//   - error: An unknown operator or malformed operand
+//
+// Example:
+//
+//	ok, err := cyborgdb.MatchFilter(map[string]interface{}{
+//		"tenant": "acme",
+//		"year":   map[string]interface{}{"$gte": 2020},
+//	}, item.Metadata)
+func MatchFilter(filter, metadata map[string]interface{}) (bool, error) {
+	for key, cond := range filter {
+		var (
+			ok  bool
+			err error
+		)
+		switch key {
+		case "$and", "$or":
+			ok, err = matchLogical(key, cond, metadata)
+		default:
+			value, present := lookupField(metadata, key)
+			ok, err = matchCondition(key, cond, value, present)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchLogical evaluates an $and or $or clause.
+func matchLogical(op string, cond interface{}, metadata map[string]interface{}) (bool, error) {
+	clauses, ok := cond.([]interface{})
+	if !ok {
+		if typed, isMaps := cond.([]map[string]interface{}); isMaps {
+			for _, c := range typed {
+				clauses = append(clauses, c)
+			}
+		} else {
+			return false, fmt.Errorf("%s requires a list of filters, got %T", op, cond)
+		}
+	}
+	for _, clause := range clauses {
+		sub, ok := clause.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s requires a list of filters, got element %T", op, clause)
+		}
+		matched, err := MatchFilter(sub, metadata)
+		if err != nil {
+			return false, err
+		}
+		if matched == (op == "$or") {
+			return matched, nil
+		}
+	}
+	return op == "$and", nil
+}
+
+// matchCondition evaluates the condition for one field, either a literal
+// value or a map of operators.
+func matchCondition(field string, cond, value interface{}, present bool) (bool, error) {
+	ops, ok := cond.(map[string]interface{})
+	if !ok || !hasOperators(ops) {
+		return present && filterEqual(value, cond), nil
+	}
+	for op, operand := range ops {
+		var matched bool
+		switch op {
+		case "$eq":
+			matched = present && filterEqual(value, operand)
+		case "$ne":
+			matched = !present || !filterEqual(value, operand)
+		case "$gt", "$gte", "$lt", "$lte":
+			if !present {
+				break
+			}
+			c, comparable := filterCompare(value, operand)
+			switch {
+			case !comparable:
+			case op == "$gt":
+				matched = c > 0
+			case op == "$gte":
+				matched = c >= 0
+			case op == "$lt":
+				matched = c < 0
+			default:
+				matched = c <= 0
+			}
+		case "$in", "$nin":
+			list, isList := filterList(operand)
+			if !isList {
+				return false, fmt.Errorf("%s on %q requires a list, got %T", op, field, operand)
+			}
+			in := false
+			for _, candidate := range list {
+				if present && filterEqual(value, candidate) {
+					in = true
+					break
+				}
+			}
+			matched = in == (op == "$in")
+		case "$exists":
+			want, isBool := operand.(bool)
+			if !isBool {
+				return false, fmt.Errorf("$exists on %q requires a bool, got %T", field, operand)
+			}
+			matched = present == want
+		default:
+			return false, fmt.Errorf("unsupported filter operator %q on %q", op, field)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// hasOperators reports whether m is an operator map rather than a literal
+// object value.
+func hasOperators(m map[string]interface{}) bool {
+	for key := range m {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupField returns the value at the dotted path in metadata.
+func lookupField(metadata map[string]interface{}, path string) (interface{}, bool) {
+	if value, ok := metadata[path]; ok {
+		return value, true
+	}
+	var current interface{} = metadata
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// filterEqual compares a metadata value with a filter operand, treating all
+// numeric types as equal by value. A list value matches if any element does.
+func filterEqual(value, operand interface{}) bool {
+	if list, ok := value.([]interface{}); ok {
+		if _, operandIsList := operand.([]interface{}); !operandIsList {
+			for _, v := range list {
+				if filterEqual(v, operand) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if c, ok := filterCompare(value, operand); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(value, operand)
+}
+
+// filterCompare orders two numbers or two strings.
+func filterCompare(a, b interface{}) (int, bool) {
+	if x, ok := filterNumber(a); ok {
+		y, ok := filterNumber(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+// filterNumber converts any numeric metadata or operand value to float64.
+func filterNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case interface{ Float64() (float64, error) }: // json.Number
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// filterList returns operand as a list of values.
+func filterList(operand interface{}) ([]interface{}, bool) {
+	if list, ok := operand.([]interface{}); ok {
+		return list, true
+	}
+	rv := reflect.ValueOf(operand)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
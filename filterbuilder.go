@@ -0,0 +1,122 @@
+// filterbuilder.go implements a builder for metadata filters that can
+// type-check them against an index Schema.
+package cyborgdb
+
+// FilterBuilder builds a metadata filter for QueryParams.Filters. Conditions
+// added with its methods are combined with $and. With a Schema, Build rejects
+// unknown or unfilterable fields and operands of the wrong type, so mistakes
+// fail fast instead of silently matching nothing.
+type FilterBuilder struct {
+	schema  *Schema
+	clauses []interface{}
+}
+
+// NewFilterBuilder returns an empty FilterBuilder that checks filters against
+// schema, or checks nothing if schema is nil.
+//
+// Example:
+//
+//	schema, _ := index.GetSchema(ctx)
+//	filter, err := cyborgdb.NewFilterBuilder(schema).
+//		Eq("category", "news").
+//		Gte("year", 2020).
+//		Build()
+func NewFilterBuilder(schema *Schema) *FilterBuilder {
+	return &FilterBuilder{schema: schema}
+}
+
+// Eq requires field to equal value.
+func (b *FilterBuilder) Eq(field string, value interface{}) *FilterBuilder {
+	return b.op(field, "$eq", value)
+}
+
+// Ne requires field to differ from value.
+func (b *FilterBuilder) Ne(field string, value interface{}) *FilterBuilder {
+	return b.op(field, "$ne", value)
+}
+
+// Gt requires field to be greater than value.
+func (b *FilterBuilder) Gt(field string, value interface{}) *FilterBuilder {
+	return b.op(field, "$gt", value)
+}
+
+// Gte requires field to be greater than or equal to value.
+func (b *FilterBuilder) Gte(field string, value interface{}) *FilterBuilder {
+	return b.op(field, "$gte", value)
+}
+
+// Lt requires field to be less than value.
+func (b *FilterBuilder) Lt(field string, value interface{}) *FilterBuilder {
+	return b.op(field, "$lt", value)
+}
+
+// Lte requires field to be less than or equal to value.
+func (b *FilterBuilder) Lte(field string, value interface{}) *FilterBuilder {
+	return b.op(field, "$lte", value)
+}
+
+// In requires field to equal one of values.
+func (b *FilterBuilder) In(field string, values ...interface{}) *FilterBuilder {
+	return b.op(field, "$in", values)
+}
+
+// Nin requires field to equal none of values.
+func (b *FilterBuilder) Nin(field string, values ...interface{}) *FilterBuilder {
+	return b.op(field, "$nin", values)
+}
+
+// Exists requires field to be set, or unset if exists is false.
+func (b *FilterBuilder) Exists(field string, exists bool) *FilterBuilder {
+	return b.op(field, "$exists", exists)
+}
+
+// Or requires at least one of filters to match. Build the alternatives with
+// their own FilterBuilders, or pass literal filter maps.
+//
+// Example:
+//
+//	news, _ := cyborgdb.NewFilterBuilder(nil).Eq("category", "news").Build()
+//	recent, _ := cyborgdb.NewFilterBuilder(nil).Gte("year", 2024).Build()
+//	filter, err := cyborgdb.NewFilterBuilder(schema).Or(news, recent).Build()
+func (b *FilterBuilder) Or(filters ...map[string]interface{}) *FilterBuilder {
+	clauses := make([]interface{}, len(filters))
+	for i, f := range filters {
+		clauses[i] = f
+	}
+	b.clauses = append(b.clauses, map[string]interface{}{"$or": clauses})
+	return b
+}
+
+// Where adds a literal filter map.
+func (b *FilterBuilder) Where(filter map[string]interface{}) *FilterBuilder {
+	b.clauses = append(b.clauses, filter)
+	return b
+}
+
+func (b *FilterBuilder) op(field, op string, value interface{}) *FilterBuilder {
+	b.clauses = append(b.clauses, map[string]interface{}{field: map[string]interface{}{op: value}})
+	return b
+}
+
+// Build returns the filter, or nil if no conditions were added.
+//
+// Returns:
+//   - map[string]interface{}: The filter for QueryParams.Filters
+//   - error: An error wrapping ErrInvalidFilter if it does not match the schema
+func (b *FilterBuilder) Build() (map[string]interface{}, error) {
+	var filter map[string]interface{}
+	switch len(b.clauses) {
+	case 0:
+		return nil, nil
+	case 1:
+		filter = b.clauses[0].(map[string]interface{})
+	default:
+		filter = map[string]interface{}{"$and": append([]interface{}(nil), b.clauses...)}
+	}
+	if b.schema != nil {
+		if err := b.schema.ValidateFilter(filter); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,164 @@
+// get.go implements chunked retrieval of large ID lists.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	// DefaultGetChunkSize is the maximum number of IDs sent per get request.
+	DefaultGetChunkSize = 1000
+	// DefaultGetConcurrency is the number of concurrent get requests used for
+	// large ID lists.
+	DefaultGetConcurrency = 4
+)
+
+// getChunked runs Get for more than DefaultGetChunkSize IDs, fetching chunks
+// concurrently and concatenating the results in chunk order.
+func (e *EncryptedIndex) getChunked(ctx context.Context, ids []string, include []string) (*GetResponse, error) {
+	chunks := chunkIDs(ids, DefaultGetChunkSize)
+	results := make([][]GetResultItem, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	next := make(chan int)
+	for w := 0; w < DefaultGetConcurrency && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				resp, err := e.getChunk(ctx, chunks[i], include)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("get chunk %d of %d: %w", i+1, len(chunks), err)
+						cancel()
+					})
+					continue
+				}
+				results[i] = resp.Results
+			}
+		}()
+	}
+
+feed:
+	for i := range chunks {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	merged := &GetResponse{Results: make([]GetResultItem, 0, len(ids))}
+	for _, r := range results {
+		merged.Results = append(merged.Results, r...)
+	}
+	return merged, nil
+}
+
+// chunkIDs splits ids into slices of at most size IDs.
+func chunkIDs(ids []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(ids)+size-1)/size)
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	return chunks
+}
+
+// ItemIterator steps through items fetched lazily in chunks. It is returned
+// by GetAll and Scan and follows the bufio.Scanner pattern:
+//
+//	it := index.GetAll(ctx, ids, []string{"metadata"})
+//	for it.Next() {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// An ItemIterator is not safe for concurrent use.
+type ItemIterator struct {
+	ctx     context.Context
+	fetch   func(ctx context.Context) ([]GetResultItem, bool, error)
+	buf     []GetResultItem
+	current GetResultItem
+	done    bool
+	err     error
+}
+
+// Next advances to the next item, fetching the next chunk when needed. It
+// returns false when iteration is complete or an error occurred.
+func (it *ItemIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		items, more, err := it.fetch(it.ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf, it.done = items, !more
+	}
+	it.current, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Item returns the current item.
+func (it *ItemIterator) Item() GetResultItem { return it.current }
+
+// Err returns the first error encountered, or nil if iteration completed.
+func (it *ItemIterator) Err() error { return it.err }
+
+// GetAll returns an iterator over the items for ids, fetched one chunk of
+// DefaultGetChunkSize IDs at a time so only one chunk is held in memory.
+//
+// Parameters:
+//   - ctx: Context for cancellation; it applies to every chunk request
+//   - ids: Vector IDs to retrieve
+//   - include: Fields to include ("vector", "metadata", "contents")
+//
+// Returns:
+//   - *ItemIterator: Iterator over the retrieved items, in chunk order
+func (e *EncryptedIndex) GetAll(ctx context.Context, ids []string, include []string) *ItemIterator {
+	chunks := chunkIDs(ids, DefaultGetChunkSize)
+	next := 0
+	return &ItemIterator{
+		ctx:  ctx,
+		done: len(chunks) == 0,
+		fetch: func(ctx context.Context) ([]GetResultItem, bool, error) {
+			resp, err := e.getChunk(ctx, chunks[next], include)
+			if err != nil {
+				return nil, false, err
+			}
+			next++
+			return resp.Results, next < len(chunks), nil
+		},
+	}
+}
@@ -2,10 +2,15 @@ module github.com/cyborginc/cyborgdb-go
 
 go 1.18
 
-// No runtime dependencies - keeping the SDK lightweight!
+// Minimal runtime dependencies - keeping the SDK lightweight!
+// golang.org/x/crypto is used only for Argon2id passphrase key derivation
+// and HKDF derivation of metadata encryption keys. The ONNX embedder is a
+// separate module (embeddings/onnx), so its cgo dependency is opt-in.
 
 require (
 	github.com/google/uuid v1.6.0
 	github.com/joho/godotenv v1.5.1
-	github.com/stretchr/testify v1.11.1
+	golang.org/x/crypto v0.21.0
 )
+
+require golang.org/x/sys v0.18.0 // indirect
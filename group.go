@@ -0,0 +1,124 @@
+// group.go implements grouping of query results by a metadata field.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultGroupByFactor multiplies the number of results wanted to get the
+// number of candidates fetched by QueryGrouped when FetchK is not set.
+const DefaultGroupByFactor = 3
+
+// ErrEmptyGroupField is returned when GroupByOptions.Field is empty.
+var ErrEmptyGroupField = fmt.Errorf("group-by field must not be empty")
+
+// GroupByOptions configures QueryGrouped.
+type GroupByOptions struct {
+	// Field is the metadata field results are grouped by, e.g. "doc_id".
+	Field string
+
+	// GroupSize is the maximum number of results kept per group. Default: 1.
+	GroupSize int
+
+	// FetchK is the number of candidates fetched before grouping. Default:
+	// DefaultGroupByFactor times TopK times GroupSize.
+	FetchK int32
+}
+
+// QueryGroup is a set of results sharing the same value of the group field.
+type QueryGroup struct {
+	// Value is the group field's value, or nil for a result without the field.
+	Value interface{} `json:"value"`
+
+	// Results are the group's results, closest first.
+	Results []QueryResult `json:"results"`
+}
+
+// QueryGrouped performs a similarity search and collapses results that share
+// a metadata value, such as chunks of the same document, into groups.
+//
+// The service has no server-side grouping, so QueryGrouped over-fetches
+// candidates and groups them client-side. TopK (WithTopK) is the number of
+// groups returned; fewer are returned if the candidates run out. Results
+// without the field form a group of their own.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vector: The query vector
+//   - group: Group field and limits
+//   - opts: Optional settings such as WithTopK, WithFilters, WithInclude
+//
+// Returns:
+//   - []QueryGroup: Groups ordered by their closest result
+//   - error: Any error encountered during the search
+//
+// Example:
+//
+//	groups, err := index.QueryGrouped(ctx, vec,
+//		cyborgdb.GroupByOptions{Field: "doc_id", GroupSize: 3},
+//		cyborgdb.WithTopK(5))
+func (e *EncryptedIndex) QueryGrouped(ctx context.Context, vector []float32, group GroupByOptions, opts ...QueryOption) ([]QueryGroup, error) {
+	if group.Field == "" {
+		return nil, ErrEmptyGroupField
+	}
+	if group.GroupSize <= 0 {
+		group.GroupSize = 1
+	}
+
+	var params QueryParams
+	for _, opt := range opts {
+		opt(&params)
+	}
+	groups := int(params.TopK)
+	if groups <= 0 {
+		groups = defaultQueryTopK
+	}
+	fetchK := group.FetchK
+	if fetchK <= 0 {
+		fetchK = int32(groups * group.GroupSize * DefaultGroupByFactor)
+	}
+
+	include := params.Include
+	if include == nil {
+		include = []string{"metadata"}
+	} else if !containsString(include, "metadata") {
+		include = append(append([]string(nil), include...), "metadata")
+	}
+	fetchOpts := append(append([]QueryOption(nil), opts...), WithTopK(fetchK), WithInclude(include...))
+
+	hits, err := e.QueryOne(ctx, vector, fetchOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return groupResults(hits, group.Field, group.GroupSize, groups), nil
+}
+
+// groupResults groups hits by field, keeping at most size results per group
+// and at most limit groups, in order of each group's first hit.
+func groupResults(hits []QueryResult, field string, size, limit int) []QueryGroup {
+	var out []QueryGroup
+	index := make(map[string]int)
+	for _, hit := range hits {
+		value, ok := hit.Metadata[field]
+		if !ok {
+			if len(out) < limit {
+				out = append(out, QueryGroup{Results: []QueryResult{hit}})
+			}
+			continue
+		}
+
+		key := fmt.Sprintf("%T:%v", value, value)
+		if i, seen := index[key]; seen {
+			if len(out[i].Results) < size {
+				out[i].Results = append(out[i].Results, hit)
+			}
+			continue
+		}
+		if len(out) < limit {
+			index[key] = len(out)
+			out = append(out, QueryGroup{Value: value, Results: []QueryResult{hit}})
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+// headers.go attaches per-call HTTP headers, such as correlation IDs and
+// idempotency keys, to requests through the context.
+package cyborgdb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader is the header set by WithIdempotencyKey.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// headersKey is the context key for per-call headers.
+type headersKey struct{}
+
+// protectedHeaders are managed by the SDK and cannot be set per call.
+var protectedHeaders = map[string]bool{
+	http.CanonicalHeaderKey(apiKeyHeader): true,
+	"Content-Type":                        true,
+	"Content-Length":                      true,
+	"Content-Encoding":                    true,
+}
+
+// WithHeader returns a context that adds the header k: v to every request
+// made with it, e.g. a correlation ID for tracing. Calling it again with the
+// same key replaces the value. Headers the SDK manages (API key, content
+// type, length, and encoding) are ignored.
+//
+// Example:
+//
+//	ctx = cyborgdb.WithHeader(ctx, "X-Request-ID", requestID)
+//	resp, err := index.Query(ctx, params)
+func WithHeader(ctx context.Context, k, v string) context.Context {
+	prev, _ := ctx.Value(headersKey{}).(http.Header)
+	// Copy so contexts derived from ctx earlier keep their headers.
+	headers := prev.Clone()
+	if headers == nil {
+		headers = make(http.Header, 1)
+	}
+	headers.Set(k, v)
+	return context.WithValue(ctx, headersKey{}, headers)
+}
+
+// WithIdempotencyKey returns a context whose requests carry key in the
+// IdempotencyKeyHeader, so a server that supports it can safely deduplicate
+// retried upserts. Use one key per logical write and reuse it across that
+// write's retries; NewIdempotencyKey generates one. With WithUpsertRetries,
+// each upsert batch uses a key derived from this one instead.
+//
+// Example:
+//
+//	ctx := cyborgdb.WithIdempotencyKey(ctx, cyborgdb.NewIdempotencyKey())
+//	err := index.Upsert(ctx, items) // retries reuse the same key
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return WithHeader(ctx, IdempotencyKeyHeader, key)
+}
+
+// NewIdempotencyKey returns a new random idempotency key.
+func NewIdempotencyKey() string {
+	return uuid.NewString()
+}
+
+// headerTransport adds the per-call headers stored in the request context.
+type headerTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers, _ := req.Context().Value(headersKey{}).(http.Header)
+	if len(headers) == 0 {
+		return t.next.RoundTrip(req)
+	}
+	out := req.Clone(req.Context())
+	for k, v := range headers {
+		if !protectedHeaders[http.CanonicalHeaderKey(k)] {
+			out.Header[k] = v
+		}
+	}
+	return t.next.RoundTrip(out)
+}
@@ -0,0 +1,154 @@
+// health.go provides service health helpers on Client.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultHealthPollInterval is the polling interval used by WaitUntilHealthy
+// when interval is not positive.
+const DefaultHealthPollInterval = time.Second
+
+// healthyStatus is the status value reported by a healthy service.
+const healthyStatus = "healthy"
+
+// Backend types reported in HealthResponse.BackendType.
+const (
+	// BackendLite is the lightweight, in-memory service backend.
+	BackendLite = "lite"
+	// BackendFull is the full service backend.
+	BackendFull = "full"
+)
+
+// HealthResponse is the typed health report returned by GetHealth.
+//
+// Fields the server does not report are left at their zero values; the
+// complete server response is always available in Raw.
+type HealthResponse struct {
+	// Status is the service status, "healthy" when the service is up.
+	Status string `json:"status"`
+
+	// Version is the server version, if reported.
+	Version string `json:"version,omitempty"`
+
+	// Uptime is how long the server has been running, if reported.
+	Uptime time.Duration `json:"uptime,omitempty"`
+
+	// BackendType is BackendLite or BackendFull, if reported.
+	BackendType string `json:"backend_type,omitempty"`
+
+	// Raw holds every field of the server response.
+	Raw map[string]string `json:"raw,omitempty"`
+}
+
+// IsHealthy reports whether the service considers itself healthy.
+func (h *HealthResponse) IsHealthy() bool {
+	return h != nil && h.Status == healthyStatus
+}
+
+// Field names recognised in the server health response, in priority order.
+var (
+	healthVersionKeys = []string{"version", "server_version", "api_version"}
+	healthUptimeKeys  = []string{"uptime", "uptime_seconds"}
+	healthBackendKeys = []string{"backend_type", "backend", "type", "mode"}
+)
+
+// newHealthResponse maps the untyped server response onto HealthResponse.
+func newHealthResponse(raw map[string]string) *HealthResponse {
+	h := &HealthResponse{
+		Status:      raw["status"],
+		Version:     firstValue(raw, healthVersionKeys),
+		BackendType: firstValue(raw, healthBackendKeys),
+		Raw:         raw,
+	}
+	if v := firstValue(raw, healthUptimeKeys); v != "" {
+		h.Uptime = parseUptime(v)
+	}
+	return h
+}
+
+// firstValue returns the first non-empty value among keys.
+func firstValue(m map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v := m[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseUptime accepts seconds (integer or fractional) or a Go duration string.
+func parseUptime(v string) time.Duration {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return d
+	}
+	return 0
+}
+
+// GetHealth checks the health status of the CyborgDB service.
+//
+// Useful for readiness/liveness checks and connectivity diagnostics.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//
+// Returns:
+//   - *HealthResponse: Typed health status from the server
+//   - error: Any error encountered
+func (c *Client) GetHealth(ctx context.Context) (*HealthResponse, error) {
+	ctx, cancel := withDefaultTimeout(ctx, c.opts.operationTimeout)
+	defer cancel()
+
+	raw, err := c.internal.GetHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newHealthResponse(raw), nil
+}
+
+// WaitUntilHealthy polls GetHealth until the service reports healthy or ctx
+// is done. It is intended for startup scripts and tests that bring the
+// service up alongside the client, e.g. with docker-compose.
+//
+// Parameters:
+//   - ctx: Context bounding the total wait; use a deadline to avoid waiting forever
+//   - interval: Delay between polls (DefaultHealthPollInterval if <= 0)
+//
+// Returns:
+//   - error: nil once healthy, otherwise the context error wrapped with the
+//     last health check failure
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	if err := client.WaitUntilHealthy(ctx, 500*time.Millisecond); err != nil {
+//		log.Fatal(err)
+//	}
+func (c *Client) WaitUntilHealthy(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultHealthPollInterval
+	}
+
+	err := Poll(ctx, func(ctx context.Context) (bool, error) {
+		health, err := c.GetHealth(ctx)
+		switch {
+		case err != nil:
+			return false, err
+		case health.Status != "" && !health.IsHealthy():
+			return false, fmt.Errorf("service status is %q", health.Status)
+		default:
+			return true, nil
+		}
+	}, PollOptions{InitialInterval: interval, MaxInterval: interval})
+	if err != nil {
+		return fmt.Errorf("service not healthy: %w", err)
+	}
+	return nil
+}
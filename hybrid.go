@@ -0,0 +1,222 @@
+// hybrid.go implements hybrid search, fusing vector similarity with keyword
+// matches using reciprocal rank fusion.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultRRFK is the reciprocal rank fusion constant k: each list
+	// contributes 1/(k+rank) for a result at 1-based rank.
+	DefaultRRFK = 60
+	// DefaultHybridFactor multiplies TopK to get the number of candidates
+	// fetched from each search when HybridQueryParams.FetchK is not set.
+	DefaultHybridFactor = 4
+)
+
+// ErrEmptyHybridQuery is returned when a hybrid query has no vector or no terms.
+var ErrEmptyHybridQuery = fmt.Errorf("hybrid query requires a query vector and keyword terms")
+
+// HybridQueryParams defines a hybrid vector and keyword search.
+type HybridQueryParams struct {
+	// QueryVector is the dense query vector (required).
+	QueryVector []float32
+
+	// Terms are the keywords to match (required). Matching is
+	// case-insensitive and by whole word.
+	Terms []string
+
+	// TextField is the metadata field holding the searchable text: a string,
+	// or a list of strings such as tags (required).
+	TextField string
+
+	// KeywordFilter is the server-side filter used to fetch keyword
+	// candidates. Default: TextField "$in" Terms, which matches list fields
+	// containing a term and string fields equal to one.
+	KeywordFilter map[string]interface{}
+
+	// Filters is applied to both searches, e.g. to restrict to a tenant.
+	Filters map[string]interface{}
+
+	// TopK is the number of fused results returned. Default: 10.
+	TopK int32
+
+	// FetchK is the number of candidates fetched from each search. Default:
+	// DefaultHybridFactor times TopK.
+	FetchK int32
+
+	// VectorWeight and KeywordWeight scale each list's contribution to the
+	// fused score. Both default to 1.
+	VectorWeight, KeywordWeight float64
+
+	// RRFK is the reciprocal rank fusion constant. Default: DefaultRRFK.
+	RRFK int
+}
+
+// HybridResult is a fused hybrid search result.
+type HybridResult struct {
+	QueryResult
+
+	// VectorRank is the 1-based rank among vector candidates, 0 if absent.
+	VectorRank int `json:"vector_rank"`
+
+	// KeywordRank is the 1-based rank by keyword matches, 0 if no term matched.
+	KeywordRank int `json:"keyword_rank"`
+
+	// FusedScore is the weighted reciprocal rank fusion score.
+	FusedScore float64 `json:"fused_score"`
+}
+
+// HybridQuery runs a vector search and a keyword-filtered search, scores
+// keyword matches in TextField client-side, and fuses both rankings with
+// reciprocal rank fusion, so callers need not orchestrate and merge two
+// searches themselves.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - params: Query vector, terms, text field, and fusion settings
+//
+// Returns:
+//   - []HybridResult: Results ordered by fused score (best first)
+//   - error: Any error encountered during either search
+//
+// Example:
+//
+//	results, err := index.HybridQuery(ctx, cyborgdb.HybridQueryParams{
+//		QueryVector: vec,
+//		Terms:       []string{"refund", "policy"},
+//		TextField:   "keywords",
+//		TopK:        10,
+//	})
+func (e *EncryptedIndex) HybridQuery(ctx context.Context, params HybridQueryParams) ([]HybridResult, error) {
+	if len(params.QueryVector) == 0 || len(params.Terms) == 0 || params.TextField == "" {
+		return nil, ErrEmptyHybridQuery
+	}
+	if params.TopK <= 0 {
+		params.TopK = 10
+	}
+	if params.FetchK < params.TopK {
+		params.FetchK = params.TopK * DefaultHybridFactor
+	}
+	if params.VectorWeight == 0 && params.KeywordWeight == 0 {
+		params.VectorWeight, params.KeywordWeight = 1, 1
+	}
+	if params.RRFK <= 0 {
+		params.RRFK = DefaultRRFK
+	}
+	keywordFilter := params.KeywordFilter
+	if keywordFilter == nil {
+		keywordFilter = map[string]interface{}{params.TextField: map[string]interface{}{"$in": params.Terms}}
+	}
+	if params.Filters != nil {
+		keywordFilter = map[string]interface{}{"$and": []interface{}{params.Filters, keywordFilter}}
+	}
+
+	vectorHits, err := e.QueryOne(ctx, params.QueryVector,
+		WithTopK(params.FetchK), WithFilters(params.Filters), WithInclude("metadata"))
+	if err != nil {
+		return nil, fmt.Errorf("vector search failed: %w", err)
+	}
+	keywordHits, err := e.QueryOne(ctx, params.QueryVector,
+		WithTopK(params.FetchK), WithFilters(keywordFilter), WithInclude("metadata"))
+	if err != nil {
+		return nil, fmt.Errorf("keyword search failed: %w", err)
+	}
+
+	terms := make(map[string]struct{}, len(params.Terms))
+	for _, t := range params.Terms {
+		terms[strings.ToLower(t)] = struct{}{}
+	}
+
+	// Union the candidates, keeping vector order first.
+	byID := make(map[string]*HybridResult)
+	var candidates []*HybridResult
+	for i, hit := range vectorHits {
+		r := &HybridResult{QueryResult: hit, VectorRank: i + 1}
+		byID[hit.ID] = r
+		candidates = append(candidates, r)
+	}
+	for _, hit := range keywordHits {
+		if _, ok := byID[hit.ID]; !ok {
+			r := &HybridResult{QueryResult: hit}
+			byID[hit.ID] = r
+			candidates = append(candidates, r)
+		}
+	}
+
+	// Rank candidates by keyword matches; ties keep candidate order.
+	matches := make(map[string]int, len(candidates))
+	var matched []*HybridResult
+	for _, r := range candidates {
+		if n := countTermMatches(r.Metadata[params.TextField], terms); n > 0 {
+			matches[r.ID] = n
+			matched = append(matched, r)
+		}
+	}
+	sort.SliceStable(matched, func(a, b int) bool { return matches[matched[a].ID] > matches[matched[b].ID] })
+	for i, r := range matched {
+		r.KeywordRank = i + 1
+	}
+
+	k := float64(params.RRFK)
+	for _, r := range candidates {
+		if r.VectorRank > 0 {
+			r.FusedScore += params.VectorWeight / (k + float64(r.VectorRank))
+		}
+		if r.KeywordRank > 0 {
+			r.FusedScore += params.KeywordWeight / (k + float64(r.KeywordRank))
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].FusedScore > candidates[b].FusedScore })
+
+	if int32(len(candidates)) > params.TopK {
+		candidates = candidates[:params.TopK]
+	}
+	out := make([]HybridResult, len(candidates))
+	for i, r := range candidates {
+		out[i] = *r
+	}
+	return out, nil
+}
+
+// countTermMatches counts the words in value (a string or list of strings)
+// that are one of terms.
+func countTermMatches(value interface{}, terms map[string]struct{}) int {
+	count := func(text string) int {
+		n := 0
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, w := range words {
+			if _, ok := terms[w]; ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	switch v := value.(type) {
+	case string:
+		return count(v)
+	case []interface{}:
+		n := 0
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				n += count(s)
+			}
+		}
+		return n
+	case []string:
+		n := 0
+		for _, s := range v {
+			n += count(s)
+		}
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+// idempotency.go implements retries of upsert requests that carry a stable
+// idempotency key, so a retried batch that was partially applied is not
+// written twice.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+const (
+	// DefaultUpsertRetryInitialBackoff is the delay before the first retry
+	// of an upsert request; it doubles on each attempt.
+	DefaultUpsertRetryInitialBackoff = 500 * time.Millisecond
+	// DefaultUpsertRetryMaxBackoff caps the delay between upsert retries.
+	DefaultUpsertRetryMaxBackoff = 10 * time.Second
+)
+
+// WithUpsertRetries retries upsert requests that fail transiently (a
+// timeout, a dropped connection, 408, or a 5xx response) up to n times with
+// exponential backoff. Every upsert batch, including each batch of Import and
+// UpsertStream, gets an idempotency key (see IdempotencyKeyHeader) that all
+// of its attempts share, so a server that honors the key discards the
+// duplicate of a batch it had already applied before the failure.
+//
+// If the context carries a key from WithIdempotencyKey, each batch key is
+// derived from it and the batch's IDs, so retrying the whole logical write
+// reuses the same keys. Otherwise each batch gets a random key.
+//
+// With retries enabled, the upsert timeout (WithUpsertTimeout) bounds each
+// attempt rather than the whole call.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey, cyborgdb.WithUpsertRetries(3))
+func WithUpsertRetries(n int) ClientOption {
+	return func(o *clientOptions) { o.upsertRetries = n }
+}
+
+// sendUpsertRetrying sends req, retrying transient failures under a shared
+// idempotency key when upsert retries are enabled.
+func (e *EncryptedIndex) sendUpsertRetrying(ctx context.Context, req internal.UpsertRequest) (*internal.CyborgdbServiceApiSchemasVectorsSuccessResponseModel, error) {
+	retries := e.opts.upsertRetries
+	if retries <= 0 {
+		return e.sendUpsert(ctx, req)
+	}
+	ctx = WithIdempotencyKey(ctx, batchIdempotencyKey(ctx, req.Items))
+
+	backoff := DefaultUpsertRetryInitialBackoff
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := withDefaultTimeout(ctx, e.opts.upsertTimeout)
+		resp, err := e.sendUpsert(attemptCtx, req)
+		cancel()
+		if err == nil || attempt >= retries || ctx.Err() != nil || !retryableUpsertError(err) {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > DefaultUpsertRetryMaxBackoff {
+			backoff = DefaultUpsertRetryMaxBackoff
+		}
+	}
+}
+
+// batchIdempotencyKey returns the key for a batch of items: derived from the
+// caller's key and the item IDs if ctx carries one, random otherwise.
+func batchIdempotencyKey(ctx context.Context, items []VectorItem) string {
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+	base := headers.Get(IdempotencyKeyHeader)
+	if base == "" {
+		return NewIdempotencyKey()
+	}
+	h := sha256.New()
+	for _, item := range items {
+		h.Write([]byte(item.Id))
+		h.Write([]byte{0})
+	}
+	return base + "-" + hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// retryableUpsertError reports whether err is a transient failure worth
+// retrying: a timeout, a network error, or a 408 or 5xx response.
+func retryableUpsertError(err error) bool {
+	var apiErr *internal.GenericOpenAPIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.Error())
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, io.EOF),
+		errors.As(err, &netErr):
+		return true
+	}
+	return false
+}
+
+// retryableStatus reports whether an HTTP status line such as
+// "503 Service Unavailable" is 408 or a 5xx other than 501.
+func retryableStatus(status string) bool {
+	if len(status) < 3 {
+		return false
+	}
+	code, err := strconv.Atoi(status[:3])
+	if err != nil {
+		return false
+	}
+	return code == http.StatusRequestTimeout || (code >= 500 && code != http.StatusNotImplemented)
+}
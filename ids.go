@@ -0,0 +1,120 @@
+// ids.go provides vector ID generation helpers.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// ErrIDGeneration is returned when random ID generation fails.
+var ErrIDGeneration = fmt.Errorf("failed to generate ID")
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// IDGenerator returns a new unique ID.
+type IDGenerator func() (string, error)
+
+// NewULID returns a new ULID: a 26-character, lexicographically sortable ID
+// made of a millisecond timestamp and 80 random bits.
+func NewULID() (string, error) {
+	var b [16]byte
+	putMillis(b[:6], time.Now())
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIDGeneration, err)
+	}
+
+	// 128 bits encode as 26 base32 characters; the first carries 3 bits.
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out), nil
+}
+
+// NewUUIDv7 returns a new RFC 9562 version 7 UUID: time-ordered, with a
+// millisecond timestamp followed by random bits.
+func NewUUIDv7() (string, error) {
+	var b [16]byte
+	putMillis(b[:6], time.Now())
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIDGeneration, err)
+	}
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// DeterministicID derives an ID from content, so the same content always gets
+// the same ID and re-ingesting it overwrites rather than duplicates. The ID is
+// the first 128 bits of the SHA-256 digest, hex encoded.
+func DeterministicID(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:16])
+}
+
+// putMillis writes t as a 48-bit big-endian Unix millisecond timestamp.
+func putMillis(dst []byte, t time.Time) {
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	for i := 5; i >= 0; i-- {
+		dst[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// AssignIDs sets an ID from gen on every item whose Id is empty, and returns
+// the IDs of all items in order. Items are modified in place.
+func AssignIDs(items []VectorItem, gen IDGenerator) ([]string, error) {
+	if gen == nil {
+		gen = NewUUIDv7
+	}
+	ids := make([]string, len(items))
+	for i := range items {
+		if items[i].Id == "" {
+			id, err := gen()
+			if err != nil {
+				return nil, err
+			}
+			items[i].Id = id
+		}
+		ids[i] = items[i].Id
+	}
+	return ids, nil
+}
+
+// UpsertWithIDs upserts items, first assigning an ID from gen (NewUUIDv7 if
+// nil) to every item without one. The caller's slice is not modified.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - items: Items to upsert; empty Ids are generated
+//   - gen: ID generator, e.g. NewULID
+//
+// Returns:
+//   - []string: The ID of every item, in input order
+//   - error: Any error encountered
+//
+// Example:
+//
+//	ids, err := index.UpsertWithIDs(ctx, items, cyborgdb.NewULID)
+func (e *EncryptedIndex) UpsertWithIDs(ctx context.Context, items []VectorItem, gen IDGenerator) ([]string, error) {
+	items = append([]VectorItem(nil), items...)
+	ids, err := AssignIDs(items, gen)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.Upsert(ctx, items); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
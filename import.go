@@ -0,0 +1,392 @@
+// import.go implements bulk import of a record stream into an encrypted index.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultImportBatchSize is the default number of records per upsert during Import.
+	DefaultImportBatchSize = 500
+	// DefaultImportConcurrency is the default number of concurrent upserts during Import.
+	DefaultImportConcurrency = 4
+	// maxImportErrors caps the number of row errors kept in an ImportSummary.
+	maxImportErrors = 1000
+)
+
+// ImportOptions configures Import. Zero values fall back to defaults.
+type ImportOptions struct {
+	// BatchSize is the number of records sent per upsert request. Default: 500.
+	BatchSize int
+
+	// Concurrency is the maximum number of upsert requests in flight. Default: 4.
+	Concurrency int
+
+	// Dimension is the expected vector length. If 0, the index configuration is
+	// used, falling back to the length of the first vector read.
+	Dimension int32
+
+	// Dedup, if set, drops or merges records whose content was already
+	// ingested. Share one Deduper across imports to dedupe between them.
+	Dedup *Deduper
+
+	// Checkpoint, if set, records progress after each batch so an interrupted
+	// import of the same input resumes after the last committed row instead
+	// of starting over. The checkpoint is cleared when an import finishes
+	// without failures.
+	Checkpoint CheckpointStore
+
+	// CheckpointKey identifies this import in Checkpoint. Default: "import:"
+	// followed by the index name.
+	CheckpointKey string
+
+	// OnProgress, if set, is called after each batch with running totals.
+	// Calls are serialized; keep the callback fast.
+	OnProgress func(ImportProgress)
+}
+
+// ImportError describes a single row that was skipped or failed to import.
+type ImportError struct {
+	// Row is the 1-based position of the record in the input stream.
+	Row int `json:"row"`
+	// ID is the record ID, if it could be parsed.
+	ID string `json:"id,omitempty"`
+	// Reason explains why the row was not imported.
+	Reason string `json:"reason"`
+}
+
+// ImportSummary reports the outcome of an Import.
+type ImportSummary struct {
+	// Imported is the number of records successfully upserted.
+	Imported int `json:"imported"`
+	// Skipped is the number of records rejected before upsert (parse or validation errors).
+	Skipped int `json:"skipped"`
+	// Failed is the number of records in batches the server rejected.
+	Failed int `json:"failed"`
+	// Deduplicated is the number of duplicate records dropped by ImportOptions.Dedup.
+	Deduplicated int `json:"deduplicated,omitempty"`
+	// Resumed is the number of rows skipped because a checkpoint showed they
+	// were committed by an earlier run.
+	Resumed int `json:"resumed,omitempty"`
+	// Errors lists the reasons for skipped and failed rows (capped at 1000 entries).
+	Errors []ImportError `json:"errors,omitempty"`
+	// Batches reports every batch formed, in order; see BatchState. Rows
+	// of a BatchSkipped batch were read but neither imported nor counted
+	// as failed.
+	Batches []BatchResult `json:"batches,omitempty"`
+}
+
+// importBatch is a group of records sent in a single upsert.
+type importBatch struct {
+	seq     int
+	lastRow int
+	rows    []int
+	items   []VectorItem
+}
+
+// Import reads records from r and upserts them into the index in batches.
+//
+// Each record is validated before upload: it must have an ID, and its vector
+// (if any) must match the index dimension. Invalid rows are skipped and
+// reported in the summary rather than aborting the import. Batches are
+// upserted concurrently, bounded by ImportOptions.Concurrency.
+//
+// If ctx is done, no further batches are sent and the summary's Batches
+// report which rows were committed (see BatchState).
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - r: Source of serialized records
+//   - format: Input format (FormatJSONL, FormatCSV, or a registered format such as FormatParquet)
+//   - opts: Batching, concurrency, and validation options
+//
+// Returns:
+//   - *ImportSummary: Counts of imported, skipped, and failed rows with reasons
+//   - error: A fatal error (unreadable input, unsupported format, cancellation)
+//
+// Example:
+//
+//	f, _ := os.Open("dataset.jsonl")
+//	defer f.Close()
+//	summary, err := index.Import(ctx, f, cyborgdb.FormatJSONL, cyborgdb.ImportOptions{Concurrency: 8})
+func (e *EncryptedIndex) Import(ctx context.Context, r io.Reader, format RecordFormat, opts ImportOptions) (*ImportSummary, error) {
+	reader, err := newRecordReader(format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultImportConcurrency
+	}
+	dimension := int(opts.Dimension)
+	if dimension == 0 {
+		dimension = int(e.configDimension())
+	}
+
+	checkpointKey := opts.CheckpointKey
+	if checkpointKey == "" {
+		checkpointKey = "import:" + e.indexName
+	}
+	var resumed ImportCheckpoint
+	if opts.Checkpoint != nil {
+		cp, ok, err := opts.Checkpoint.Load(ctx, checkpointKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load import checkpoint: %w", err)
+		}
+		if ok {
+			resumed = cp
+		}
+	}
+	tracker := newImportTracker(resumed.Row)
+
+	summary := &ImportSummary{}
+	var (
+		mu            sync.Mutex
+		rowsRead      int64
+		checkpointErr error
+	)
+	record := func(row int, id, reason string) {
+		if len(summary.Errors) < maxImportErrors {
+			summary.Errors = append(summary.Errors, ImportError{Row: row, ID: id, Reason: reason})
+		}
+	}
+	// recordBatch adds the outcome of batch to the summary. Callers hold mu.
+	recordBatch := func(batch importBatch, state BatchState, err error) {
+		summary.Batches = append(summary.Batches, newBatchResult(batch.seq, batch.rows[0], batch.lastRow+1, state, err))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// finish commits a processed batch to the tracker, saving a checkpoint
+	// if the watermark advanced and reporting progress. Callers hold mu.
+	finish := func(batch importBatch, ok bool) {
+		if tracker.finish(batch.seq, batch.lastRow, ok) && opts.Checkpoint != nil && checkpointErr == nil {
+			cp := ImportCheckpoint{
+				Row:       tracker.committed,
+				Imported:  resumed.Imported + summary.Imported,
+				UpdatedAt: time.Now().UTC(),
+			}
+			if err := opts.Checkpoint.Save(ctx, checkpointKey, cp); err != nil {
+				checkpointErr = fmt.Errorf("failed to save import checkpoint: %w", err)
+				cancel()
+			}
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(ImportProgress{
+				RowsRead:     int(atomic.LoadInt64(&rowsRead)),
+				Imported:     summary.Imported,
+				Skipped:      summary.Skipped,
+				Failed:       summary.Failed,
+				Deduplicated: summary.Deduplicated,
+				CommittedRow: tracker.committed,
+			})
+		}
+	}
+
+	batches := make(chan importBatch)
+	trainingTriggered := false
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if opts.Dedup != nil {
+					remaining, ok := e.dedupImportBatch(ctx, opts.Dedup, &batch, summary, &mu, record)
+					if !remaining {
+						mu.Lock()
+						state := BatchCommitted
+						if !ok {
+							state = BatchFailed
+						}
+						recordBatch(batch, state, nil)
+						finish(batch, ok)
+						mu.Unlock()
+						continue
+					}
+				}
+				result, err := e.upsertItems(ctx, batch.items)
+				mu.Lock()
+				if err != nil {
+					summary.Failed += len(batch.items)
+					for j, item := range batch.items {
+						record(batch.rows[j], item.Id, err.Error())
+					}
+				} else {
+					summary.Imported += len(batch.items)
+					trainingTriggered = trainingTriggered || result.TrainingTriggered
+				}
+				recordBatch(batch, batchState(err), err)
+				finish(batch, err == nil)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var (
+		fatalErr error
+		current  importBatch
+		seq      int
+	)
+	send := func() bool {
+		if len(current.items) == 0 {
+			return true
+		}
+		current.seq, current.lastRow = seq, current.rows[len(current.rows)-1]
+		select {
+		case batches <- current:
+			current = importBatch{}
+			seq++
+			return true
+		case <-ctx.Done():
+			mu.Lock()
+			recordBatch(current, BatchSkipped, ctx.Err())
+			mu.Unlock()
+			current = importBatch{}
+			return false
+		}
+	}
+
+	for row := 1; ; row++ {
+		rec, err := reader.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, ErrMalformedRecord) {
+			fatalErr = fmt.Errorf("failed to read record %d: %w", row, err)
+			break
+		}
+		atomic.AddInt64(&rowsRead, 1)
+		if row <= resumed.Row {
+			mu.Lock()
+			summary.Resumed++
+			mu.Unlock()
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, ErrMalformedRecord) {
+				mu.Lock()
+				summary.Skipped++
+				record(row, "", err.Error())
+				mu.Unlock()
+				continue
+			}
+			fatalErr = fmt.Errorf("failed to read record %d: %w", row, err)
+			break
+		}
+
+		if reason := validateImportRecord(&rec, &dimension); reason != "" {
+			mu.Lock()
+			summary.Skipped++
+			record(row, rec.ID, reason)
+			mu.Unlock()
+			continue
+		}
+
+		item := VectorItem{Id: rec.ID, Vector: rec.Vector, Metadata: rec.Metadata}
+		if rec.Contents != nil {
+			item.Contents = TextContents(*rec.Contents)
+		}
+		current.rows = append(current.rows, row)
+		current.items = append(current.items, item)
+
+		if len(current.items) >= batchSize && !send() {
+			break
+		}
+	}
+	if fatalErr == nil {
+		send()
+	} else if len(current.items) > 0 {
+		current.seq, current.lastRow = seq, current.rows[len(current.rows)-1]
+		mu.Lock()
+		recordBatch(current, BatchSkipped, fatalErr)
+		mu.Unlock()
+	}
+	close(batches)
+	wg.Wait()
+	sort.Slice(summary.Batches, func(i, j int) bool { return summary.Batches[i].Seq < summary.Batches[j].Seq })
+
+	if trainingTriggered {
+		e.setTrained(false)
+	}
+	if fatalErr != nil {
+		return summary, fatalErr
+	}
+	if checkpointErr != nil {
+		return summary, checkpointErr
+	}
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+	if opts.Checkpoint != nil && summary.Failed == 0 {
+		if err := opts.Checkpoint.Clear(ctx, checkpointKey); err != nil {
+			return summary, fmt.Errorf("failed to clear import checkpoint: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// dedupImportBatch removes duplicates from batch, updating the summary. It
+// reports whether any items remain to upsert, and whether the check succeeded.
+func (e *EncryptedIndex) dedupImportBatch(
+	ctx context.Context,
+	d *Deduper,
+	batch *importBatch,
+	summary *ImportSummary,
+	mu *sync.Mutex,
+	record func(row int, id, reason string),
+) (remaining, ok bool) {
+	items, positions, err := d.filter(ctx, e, batch.items)
+	mu.Lock()
+	defer mu.Unlock()
+	if err != nil {
+		summary.Failed += len(batch.items)
+		for j, item := range batch.items {
+			record(batch.rows[j], item.Id, "dedup check failed: "+err.Error())
+		}
+		return false, false
+	}
+
+	summary.Deduplicated += len(batch.items) - len(items)
+	rows := make([]int, len(positions))
+	for i, p := range positions {
+		rows[i] = batch.rows[p]
+	}
+	batch.items, batch.rows = items, rows
+	return len(items) > 0, true
+}
+
+// validateImportRecord checks a record before upload and returns a
+// non-empty reason if it must be skipped. The first vector seen fixes the
+// dimension when it is not yet known.
+func validateImportRecord(rec *Record, dimension *int) string {
+	if rec.ID == "" {
+		return "missing id"
+	}
+	if len(rec.Vector) == 0 {
+		if rec.Contents == nil {
+			return "record has neither vector nor contents"
+		}
+		return ""
+	}
+	if *dimension == 0 {
+		*dimension = len(rec.Vector)
+	}
+	if len(rec.Vector) != *dimension {
+		return fmt.Sprintf("vector dimension %d does not match expected %d", len(rec.Vector), *dimension)
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+// include.go defines the result fields that can be requested with Include
+// and validates requested field names.
+package cyborgdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Fields accepted in QueryParams.Include, Get's include, and similar lists.
+const (
+	IncludeVector   = "vector"
+	IncludeMetadata = "metadata"
+	IncludeContents = "contents"
+	// IncludeDistance applies to queries only.
+	IncludeDistance = "distance"
+)
+
+// defaultQueryInclude is what the service returns for a query without an
+// include list.
+var defaultQueryInclude = []string{IncludeDistance, IncludeMetadata}
+
+// ErrInvalidInclude is returned for an unknown include field in strict mode.
+var ErrInvalidInclude = errors.New("invalid include field")
+
+// WithStrictInclude makes Query, Get, and the helpers built on them fail with
+// ErrInvalidInclude when an include list holds an unknown field (such as
+// "vectors"), instead of sending it to the server, which silently returns
+// fewer fields.
+func WithStrictInclude() ClientOption {
+	return func(o *clientOptions) { o.strictInclude = true }
+}
+
+// ValidateInclude returns an error wrapping ErrInvalidInclude for the first
+// field in include that is not one of the Include constants.
+func ValidateInclude(include []string) error {
+	for _, field := range include {
+		switch field {
+		case IncludeVector, IncludeMetadata, IncludeContents, IncludeDistance:
+		default:
+			return fmt.Errorf("%w: %q (want %q, %q, %q, or %q)", ErrInvalidInclude, field,
+				IncludeVector, IncludeMetadata, IncludeContents, IncludeDistance)
+		}
+	}
+	return nil
+}
+
+// checkInclude validates include when strict mode is enabled.
+func (o *clientOptions) checkInclude(include []string) error {
+	if o == nil || !o.strictInclude {
+		return nil
+	}
+	return ValidateInclude(include)
+}
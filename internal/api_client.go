@@ -661,10 +661,28 @@ type Client struct {
 	APIClient *APIClient
 	baseURL   string
 	apiKey    string
+
+	// packedUnsupported is set once the server rejects packed vectors
+	packedUnsupported int32
+
+	// sparseSupport caches whether the server accepts sparse vectors
+	sparseSupport int32
 }
 
 // NewClient creates a new internal client wrapper
 func NewClient(baseURL, apiKey string, verifySSL bool) (*Client, error) {
+	// Configure SSL verification
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: !verifySSL},
+		},
+	}
+	return NewClientWithHTTPClient(baseURL, apiKey, httpClient)
+}
+
+// NewClientWithHTTPClient creates a new internal client wrapper that sends
+// requests through httpClient
+func NewClientWithHTTPClient(baseURL, apiKey string, httpClient *http.Client) (*Client, error) {
 	parsedURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
@@ -686,12 +704,7 @@ func NewClient(baseURL, apiKey string, verifySSL bool) (*Client, error) {
 		cfg.AddDefaultHeader("X-API-Key", apiKey)
 	}
 
-	// Configure SSL verification
-	cfg.HTTPClient = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: !verifySSL},
-		},
-	}
+	cfg.HTTPClient = httpClient
 
 	apiClient := NewAPIClient(cfg)
 	return &Client{
@@ -0,0 +1,50 @@
+package internal
+
+import "time"
+
+// QueryExplain is the diagnostic information attached to a QueryResponse
+// when QueryParams.Explain is set. It is filled in by the SDK, not decoded
+// from the response body.
+type QueryExplain struct {
+	// NProbes is the n_probes sent with the query; 0 means the server chose
+	// the value itself.
+	NProbes int32 `json:"n_probes"`
+
+	// NLists is the number of IVF lists in the index, 0 if unknown.
+	NLists int32 `json:"n_lists"`
+
+	// ListsScanned is the number of lists searched per query vector: NProbes
+	// capped at NLists. It is 0 when the server chose n_probes, since the
+	// service does not report the value it used.
+	ListsScanned int32 `json:"lists_scanned"`
+
+	// Candidates is the number of results the server returned, before any
+	// client-side re-ranking, score cut-off, or diversification.
+	Candidates int `json:"candidates"`
+
+	// Returned is the number of results in the response.
+	Returned int `json:"returned"`
+
+	// Requests is the number of query requests sent to the server.
+	Requests int `json:"requests"`
+
+	// Cached reports that the response came from the query cache.
+	Cached bool `json:"cached"`
+
+	// Timing breaks down where the query spent its time.
+	Timing QueryTiming `json:"timing"`
+}
+
+// QueryTiming is the time breakdown of a query.
+type QueryTiming struct {
+	// Total is the wall time of the Query call.
+	Total time.Duration `json:"total"`
+	// Embed is the time spent embedding QueryContents client-side.
+	Embed time.Duration `json:"embed"`
+	// Server is the time spent waiting on query requests, including the
+	// network round trip.
+	Server time.Duration `json:"server"`
+	// Client is the remaining time: request encoding, response decoding, and
+	// client-side post-processing.
+	Client time.Duration `json:"client"`
+}
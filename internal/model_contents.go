@@ -21,6 +21,9 @@ import (
 type Contents struct {
 	OsFile *os.File
 	String *string
+	// Bytes holds binary contents, sent as a base64 string. Responses always
+	// decode into String; see cyborgdb.ContentsBytes.
+	Bytes []byte
 }
 
 // Unmarshal JSON data into any of the pointers in the struct
@@ -57,6 +60,10 @@ func (src Contents) MarshalJSON() ([]byte, error) {
 	// Note: os.File cannot be marshaled to JSON as it's not a JSON-serializable type
 	// We skip trying to marshal OsFile and only marshal String content
 	
+	if src.Bytes != nil {
+		return json.Marshal(src.Bytes)
+	}
+
 	if src.String != nil {
 		return json.Marshal(&src.String)
 	}
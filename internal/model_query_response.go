@@ -22,6 +22,9 @@ var _ MappedNullable = &QueryResponse{}
 // QueryResponse Response model for similarity search queries.  Attributes:     results (List[QueryResultItem]): List of search results.
 type QueryResponse struct {
 	Results Results `json:"results"`
+	// Explain is set by the SDK when the query asked for it; it is never
+	// sent or decoded.
+	Explain *QueryExplain `json:"-"`
 }
 
 type _QueryResponse QueryResponse
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+)
+
+// PackedContentType is the media type for requests whose vectors are sent as
+// base64-encoded little-endian float32 arrays instead of JSON number arrays.
+const PackedContentType = "application/vnd.cyborgdb.packed+json"
+
+// ErrPackedRejected is returned when the server does not accept a packed request.
+var ErrPackedRejected = errors.New("server rejected packed vector encoding")
+
+// PackFloat32 encodes v as base64 of its little-endian float32 bytes.
+func PackFloat32(v []float32) string {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// UnpackFloat32 decodes a string produced by PackFloat32.
+func UnpackFloat32(s string) ([]float32, error) {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf)%4 != 0 {
+		return nil, fmt.Errorf("packed vector length %d is not a multiple of 4", len(buf))
+	}
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v, nil
+}
+
+// PackedSupported reports whether packed requests should still be attempted.
+func (c *Client) PackedSupported() bool {
+	return atomic.LoadInt32(&c.packedUnsupported) == 0
+}
+
+// DisablePacked stops further packed requests on this client.
+func (c *Client) DisablePacked() {
+	atomic.StoreInt32(&c.packedUnsupported, 1)
+}
+
+// UpsertPacked sends an upsert request with packed vectors. It returns an
+// error wrapping ErrPackedRejected if the server answers 415 or 422, in which
+// case the caller should retry with the JSON encoding.
+func (c *Client) UpsertPacked(ctx context.Context, req UpsertRequest) (*CyborgdbServiceApiSchemasVectorsSuccessResponseModel, error) {
+	items := make([]map[string]interface{}, len(req.Items))
+	for i, item := range req.Items {
+		m, err := item.ToMap()
+		if err != nil {
+			return nil, err
+		}
+		if item.Vector != nil {
+			m["vector"] = PackFloat32(item.Vector)
+		}
+		items[i] = m
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"index_name": req.IndexName,
+		"index_key":  req.IndexKey,
+		"items":      items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	basePath, err := c.APIClient.cfg.ServerURLWithContext(ctx, "DefaultAPIService.UpsertVectorsV1VectorsUpsertPost")
+	if err != nil {
+		return nil, err
+	}
+	headers := map[string]string{
+		"Content-Type": PackedContentType,
+		"Accept":       "application/json",
+	}
+	httpReq, err := c.APIClient.prepareRequest(ctx, basePath+"/v1/vectors/upsert", http.MethodPost, body, headers, url.Values{}, url.Values{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := c.APIClient.callAPI(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(httpResp.Body)
+	httpResp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case httpResp.StatusCode == http.StatusUnsupportedMediaType || httpResp.StatusCode == http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("%w: %s", ErrPackedRejected, httpResp.Status)
+	case httpResp.StatusCode >= 300:
+		return nil, &GenericOpenAPIError{body: respBody, error: httpResp.Status}
+	}
+
+	var out CyborgdbServiceApiSchemasVectorsSuccessResponseModel
+	if err := c.APIClient.decode(&out, bytes.TrimSpace(respBody), httpResp.Header.Get("Content-Type")); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// PostJSON sends body as JSON to path on the server of the given operation
+// and decodes the response into out. It is used for request fields the
+// generated models do not carry, such as sparse vectors.
+func (c *Client) PostJSON(ctx context.Context, operation, path string, body, out interface{}) error {
+	var buf bytes.Buffer
+	contentType, err := c.PostJSONRaw(ctx, operation, path, body, &buf)
+	if err != nil {
+		return err
+	}
+	return c.APIClient.decode(out, bytes.TrimSpace(buf.Bytes()), contentType)
+}
+
+// PostJSONRaw is PostJSON that appends the raw response body to dst instead
+// of decoding it, so callers can reuse buffers and parse it themselves. It
+// returns the response content type.
+func (c *Client) PostJSONRaw(ctx context.Context, operation, path string, body interface{}, dst *bytes.Buffer) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	basePath, err := c.APIClient.cfg.ServerURLWithContext(ctx, operation)
+	if err != nil {
+		return "", err
+	}
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+	httpReq, err := c.APIClient.prepareRequest(ctx, basePath+path, http.MethodPost, data, headers, url.Values{}, url.Values{}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	httpResp, err := c.APIClient.callAPI(httpReq)
+	if err != nil {
+		return "", err
+	}
+	_, err = dst.ReadFrom(httpResp.Body)
+	httpResp.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	if httpResp.StatusCode >= 300 {
+		return "", &GenericOpenAPIError{body: append([]byte(nil), dst.Bytes()...), error: httpResp.Status}
+	}
+	return httpResp.Header.Get("Content-Type"), nil
+}
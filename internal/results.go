@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnexpectedResultShape is returned by the QueryResponse accessors when
+// the response does not have the requested shape.
+var ErrUnexpectedResultShape = errors.New("unexpected query result shape")
+
+// IsBatch reports whether the response holds one result list per query
+// vector (a batch query) rather than a single list.
+func (r *QueryResponse) IsBatch() bool {
+	return r != nil && r.Results.ArrayOfArrayOfQueryResultItem != nil
+}
+
+// Len returns the number of result lists: 1 for a single query, the number
+// of query vectors for a batch, 0 for an empty response.
+func (r *QueryResponse) Len() int {
+	switch {
+	case r == nil:
+		return 0
+	case r.Results.ArrayOfArrayOfQueryResultItem != nil:
+		return len(*r.Results.ArrayOfArrayOfQueryResultItem)
+	case r.Results.ArrayOfQueryResultItem != nil:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Single returns the results of a single-vector query. A batch response with
+// exactly one query is accepted too.
+func (r *QueryResponse) Single() ([]QueryResultItem, error) {
+	switch {
+	case r == nil:
+		return nil, fmt.Errorf("%w: nil response", ErrUnexpectedResultShape)
+	case r.Results.ArrayOfQueryResultItem != nil:
+		return *r.Results.ArrayOfQueryResultItem, nil
+	case r.Results.ArrayOfArrayOfQueryResultItem != nil:
+		batches := *r.Results.ArrayOfArrayOfQueryResultItem
+		if len(batches) != 1 {
+			return nil, fmt.Errorf("%w: batch response with %d queries", ErrUnexpectedResultShape, len(batches))
+		}
+		return batches[0], nil
+	default:
+		return nil, fmt.Errorf("%w: no results", ErrUnexpectedResultShape)
+	}
+}
+
+// At returns the results of the i-th query vector. For a single-vector
+// response only index 0 is valid.
+func (r *QueryResponse) At(i int) ([]QueryResultItem, error) {
+	if n := r.Len(); i < 0 || i >= n {
+		return nil, fmt.Errorf("%w: index %d out of range for %d result lists", ErrUnexpectedResultShape, i, n)
+	}
+	if r.Results.ArrayOfArrayOfQueryResultItem != nil {
+		return (*r.Results.ArrayOfArrayOfQueryResultItem)[i], nil
+	}
+	return *r.Results.ArrayOfQueryResultItem, nil
+}
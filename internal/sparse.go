@@ -0,0 +1,32 @@
+package internal
+
+import "sync/atomic"
+
+// Sparse support states cached on Client.
+const (
+	sparseUnknown int32 = iota
+	sparseSupported
+	sparseUnsupported
+)
+
+// SparseSupport reports whether the server accepts sparse vectors, and
+// whether that is known yet.
+func (c *Client) SparseSupport() (supported, known bool) {
+	switch atomic.LoadInt32(&c.sparseSupport) {
+	case sparseSupported:
+		return true, true
+	case sparseUnsupported:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// SetSparseSupport records whether the server accepts sparse vectors.
+func (c *Client) SetSparseSupport(supported bool) {
+	state := sparseUnsupported
+	if supported {
+		state = sparseSupported
+	}
+	atomic.StoreInt32(&c.sparseSupport, state)
+}
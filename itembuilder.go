@@ -0,0 +1,93 @@
+// itembuilder.go implements a builder that validates vector items before
+// they are sent to the server.
+package cyborgdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidVectorItem is returned when a VectorItem fails validation.
+var ErrInvalidVectorItem = errors.New("invalid vector item")
+
+// VectorItemBuilder builds a validated VectorItem.
+type VectorItemBuilder struct {
+	item VectorItem
+}
+
+// NewVectorItemBuilder starts building a VectorItem with the given ID.
+//
+// Example:
+//
+//	item, err := cyborgdb.NewVectorItemBuilder("doc1").
+//		WithVector(embedding).
+//		WithMetadata(map[string]interface{}{"source": "wiki"}).
+//		WithContents("Hello world").
+//		Build()
+func NewVectorItemBuilder(id string) *VectorItemBuilder {
+	return &VectorItemBuilder{item: VectorItem{Id: id}}
+}
+
+// WithVector sets the item's vector.
+func (b *VectorItemBuilder) WithVector(v []float32) *VectorItemBuilder {
+	b.item.Vector = v
+	return b
+}
+
+// WithMetadata sets the item's metadata.
+func (b *VectorItemBuilder) WithMetadata(m map[string]interface{}) *VectorItemBuilder {
+	b.item.Metadata = m
+	return b
+}
+
+// WithBinaryContents sets the item's binary contents. See BinaryContents.
+func (b *VectorItemBuilder) WithBinaryContents(data []byte) *VectorItemBuilder {
+	b.item.Contents = BinaryContents(data)
+	return b
+}
+
+// WithContents sets the item's text contents.
+func (b *VectorItemBuilder) WithContents(s string) *VectorItemBuilder {
+	b.item.Contents = TextContents(s)
+	return b
+}
+
+// Build validates the item and returns it. See ValidateVectorItem.
+func (b *VectorItemBuilder) Build() (VectorItem, error) {
+	if err := ValidateVectorItem(b.item); err != nil {
+		return VectorItem{}, err
+	}
+	return b.item, nil
+}
+
+// ValidateVectorItem checks item for mistakes the server would reject:
+// an empty ID, neither a vector nor contents to embed, a vector holding NaN
+// or Inf, or metadata that fails ValidateMetadata.
+//
+// Returns:
+//   - error: nil, an error wrapping ErrInvalidVectorItem, or for metadata
+//     problems an error wrapping a *MetadataError
+func ValidateVectorItem(item VectorItem) error {
+	if item.Id == "" {
+		return fmt.Errorf("%w: empty ID", ErrInvalidVectorItem)
+	}
+	if item.Vector == nil && !hasContents(item) {
+		return fmt.Errorf("%w: %q has neither a vector nor contents", ErrInvalidVectorItem, item.Id)
+	}
+	for i, x := range item.Vector {
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return fmt.Errorf("%w: %q: vector[%d] is %v", ErrInvalidVectorItem, item.Id, i, x)
+		}
+	}
+	if err := ValidateMetadata(item.Metadata); err != nil {
+		return fmt.Errorf("item %q: %w", item.Id, err)
+	}
+	return nil
+}
+
+// hasContents reports whether item carries contents.
+func hasContents(item VectorItem) bool {
+	c := item.Contents.Get()
+	return c != nil && (c.String != nil || c.Bytes != nil || c.OsFile != nil)
+}
@@ -0,0 +1,61 @@
+// keyprovider.go defines the KeyProvider interface for resolving index keys
+// at call time, e.g. by unwrapping a data key held in a KMS, instead of
+// passing raw key bytes around the application.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// KeyProvider resolves the 32-byte encryption key for an index.
+//
+// Implementations for AWS KMS, GCP KMS, and HashiCorp Vault are available in
+// the kms subpackage. Implementations should be safe for concurrent use.
+type KeyProvider interface {
+	// IndexKey returns the 32-byte key for the named index. The SDK takes
+	// ownership of the returned slice and zeroes it after use, so
+	// implementations must return a fresh copy on every call.
+	IndexKey(ctx context.Context, indexName string) ([]byte, error)
+}
+
+// StaticKey is a KeyProvider that always returns the same key.
+type StaticKey []byte
+
+// IndexKey implements KeyProvider. It returns a copy so callers may wipe it.
+func (k StaticKey) IndexKey(context.Context, string) ([]byte, error) {
+	return append([]byte(nil), k...), nil
+}
+
+// resolveKey fetches and validates a key from provider.
+func resolveKey(ctx context.Context, provider KeyProvider, indexName string) ([]byte, error) {
+	key, err := provider.IndexKey(ctx, indexName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve index key: %w", err)
+	}
+	if len(key) != KeySize {
+		Zeroize(key)
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(key))
+	}
+	return key, nil
+}
+
+// LoadIndexWithKeyProvider loads an existing encrypted index, resolving its
+// key through provider.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - indexName: Existing index name
+//   - provider: Source of the index key
+//
+// Returns:
+//   - *EncryptedIndex: Handle for vector operations
+//   - error: Any error encountered
+func (c *Client) LoadIndexWithKeyProvider(ctx context.Context, indexName string, provider KeyProvider) (*EncryptedIndex, error) {
+	key, err := resolveKey(ctx, provider, indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(key)
+	return c.LoadIndex(ctx, indexName, key)
+}
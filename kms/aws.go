@@ -0,0 +1,219 @@
+package kms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// Compile-time check that AWSProvider satisfies cyborgdb.KeyProvider.
+var _ cyborgdb.KeyProvider = (*AWSProvider)(nil)
+
+// AWSCredentials are the credentials used to sign AWS KMS requests.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is required for temporary (STS) credentials.
+	SessionToken string
+}
+
+// AWSProvider unwraps index keys with the AWS KMS Decrypt API.
+type AWSProvider struct {
+	// Region is the AWS region of the KMS key, e.g. "us-east-1" (required).
+	Region string
+
+	// KeyID optionally pins the KMS key used for decryption.
+	KeyID string
+
+	// Credentials sign each request (required).
+	Credentials AWSCredentials
+
+	// WrappedKeys holds the KMS ciphertext blob of each index key.
+	WrappedKeys WrappedKeys
+
+	// Endpoint overrides the KMS endpoint (default https://kms.<region>.amazonaws.com).
+	Endpoint string
+
+	// HTTPClient is used for requests (default http.DefaultClient).
+	HTTPClient *http.Client
+
+	// CacheTTL is how long an unwrapped key is reused before the KMS is asked
+	// again (default DefaultCacheTTL; negative disables caching).
+	CacheTTL time.Duration
+
+	cache keyCache
+}
+
+// IndexKey implements cyborgdb.KeyProvider.
+func (p *AWSProvider) IndexKey(ctx context.Context, indexName string) ([]byte, error) {
+	return p.cache.get(ctx, indexName, p.CacheTTL, func(ctx context.Context) ([]byte, error) {
+		wrapped, err := p.WrappedKeys.lookup(indexName)
+		if err != nil {
+			return nil, err
+		}
+		return p.decrypt(ctx, wrapped)
+	})
+}
+
+// Close zeroes and drops every cached key. The provider remains usable and
+// unwraps keys again on demand.
+func (p *AWSProvider) Close() error {
+	p.cache.close()
+	return nil
+}
+
+func (p *AWSProvider) decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", p.Region)
+	}
+
+	payload := map[string]string{"CiphertextBlob": base64.StdEncoding.EncodeToString(ciphertext)}
+	if p.KeyID != "" {
+		payload["KeyId"] = p.KeyID
+	}
+	req, body, err := newJSONRequest(ctx, endpoint+"/", payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	signAWSv4(req, body, p.Credentials, p.Region, "kms", time.Now().UTC())
+
+	var out struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := doJSON(p.HTTPClient, req, &out); err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(out.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("kms: invalid plaintext in AWS response: %w", err)
+	}
+	return key, nil
+}
+
+// signAWSv4 adds AWS Signature Version 4 headers to req, signing every
+// header already set on it plus Host and the X-Amz-* headers it adds.
+func signAWSv4(req *http.Request, body []byte, creds AWSCredentials, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if name == "Authorization" {
+			continue
+		}
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	signed := make([]string, 0, len(headers))
+	for name := range headers {
+		signed = append(signed, name)
+	}
+	sort.Strings(signed)
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	signature := hex.EncodeToString(hmacSHA256(awsSigningKey(creds.SecretAccessKey, date, region, service), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// awsSigningKey derives the SigV4 signing key for a date, region, and service.
+func awsSigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalQuery encodes query sorted by key, then value, as SigV4 requires.
+func canonicalQuery(query url.Values) string {
+	encoded := make(map[string][]string, len(query))
+	keys := make([]string, 0, len(query))
+	for key, values := range query {
+		k := awsURIEncode(key)
+		for _, v := range values {
+			encoded[k] = append(encoded[k], awsURIEncode(v))
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		sort.Strings(encoded[k])
+		for _, v := range encoded[k] {
+			pairs = append(pairs, k+"="+v)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes every byte except the RFC 3986 unreserved
+// characters.
+func awsURIEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
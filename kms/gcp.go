@@ -0,0 +1,91 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// Compile-time check that GCPProvider satisfies cyborgdb.KeyProvider.
+var _ cyborgdb.KeyProvider = (*GCPProvider)(nil)
+
+// GCPProvider unwraps index keys with the Google Cloud KMS decrypt API.
+type GCPProvider struct {
+	// KeyName is the full CryptoKey resource name (required), e.g.
+	// "projects/p/locations/global/keyRings/r/cryptoKeys/k".
+	KeyName string
+
+	// TokenSource returns an OAuth2 access token for each request (required),
+	// e.g. from golang.org/x/oauth2/google or the metadata server.
+	TokenSource func(ctx context.Context) (string, error)
+
+	// WrappedKeys holds the Cloud KMS ciphertext of each index key.
+	WrappedKeys WrappedKeys
+
+	// Endpoint overrides the API root (default https://cloudkms.googleapis.com).
+	Endpoint string
+
+	// HTTPClient is used for requests (default http.DefaultClient).
+	HTTPClient *http.Client
+
+	// CacheTTL is how long an unwrapped key is reused before the KMS is asked
+	// again (default DefaultCacheTTL; negative disables caching).
+	CacheTTL time.Duration
+
+	cache keyCache
+}
+
+// IndexKey implements cyborgdb.KeyProvider.
+func (p *GCPProvider) IndexKey(ctx context.Context, indexName string) ([]byte, error) {
+	return p.cache.get(ctx, indexName, p.CacheTTL, func(ctx context.Context) ([]byte, error) {
+		wrapped, err := p.WrappedKeys.lookup(indexName)
+		if err != nil {
+			return nil, err
+		}
+		return p.decrypt(ctx, wrapped)
+	})
+}
+
+// Close zeroes and drops every cached key. The provider remains usable and
+// unwraps keys again on demand.
+func (p *GCPProvider) Close() error {
+	p.cache.close()
+	return nil
+}
+
+func (p *GCPProvider) decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "https://cloudkms.googleapis.com"
+	}
+
+	token, err := p.TokenSource(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kms: failed to obtain GCP access token: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s:decrypt", endpoint, p.KeyName)
+	req, _, err := newJSONRequest(ctx, url, map[string]string{
+		"ciphertext": base64.StdEncoding.EncodeToString(ciphertext),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var out struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := doJSON(p.HTTPClient, req, &out); err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(out.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("kms: invalid plaintext in GCP response: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,154 @@
+// Package kms provides cyborgdb.KeyProvider implementations that unwrap index
+// keys held in external key management systems: AWS KMS, Google Cloud KMS,
+// and HashiCorp Vault's transit engine.
+//
+// All providers use envelope encryption: the application stores a wrapped
+// (encrypted) copy of each 32-byte index key, and the provider asks the KMS
+// to decrypt it when the index is created or loaded. The plaintext key never
+// needs to be written to disk.
+//
+// Each provider caches unwrapped keys for CacheTTL (DefaultCacheTTL by
+// default) and zeroes them when they expire or when the provider is closed.
+//
+// The providers talk to the KMS REST APIs directly and have no dependencies
+// beyond the standard library.
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+var (
+	// ErrNoWrappedKey is returned when no wrapped key is registered for an index.
+	ErrNoWrappedKey = errors.New("kms: no wrapped key for index")
+	// ErrRequestFailed is returned when the KMS responds with a non-2xx status.
+	ErrRequestFailed = errors.New("kms: request failed")
+)
+
+// WrappedKeys maps index names to their wrapped (KMS-encrypted) keys.
+// A single entry under the empty name "" acts as a default for all indexes.
+type WrappedKeys map[string][]byte
+
+// lookup returns the wrapped key for indexName, falling back to the default entry.
+func (w WrappedKeys) lookup(indexName string) ([]byte, error) {
+	if key, ok := w[indexName]; ok {
+		return key, nil
+	}
+	if key, ok := w[""]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrNoWrappedKey, indexName)
+}
+
+// DefaultCacheTTL is how long a provider reuses an unwrapped key when its
+// CacheTTL is zero.
+const DefaultCacheTTL = 5 * time.Minute
+
+// keyCache memoizes unwrapped keys so the KMS is called once per index per
+// TTL. Keys are zeroed when they expire and on close. Callers receive a
+// copy, since the SDK zeroes keys after use.
+type keyCache struct {
+	mu   sync.Mutex
+	keys map[string]*cachedKey
+}
+
+type cachedKey struct {
+	key   []byte
+	timer *time.Timer
+}
+
+// get returns the cached key for indexName, or unwraps and caches it for
+// ttl (DefaultCacheTTL if zero; a negative ttl disables caching).
+func (c *keyCache) get(ctx context.Context, indexName string, ttl time.Duration, unwrap func(context.Context) ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.keys[indexName]; ok {
+		return append([]byte(nil), entry.key...), nil
+	}
+	key, err := unwrap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ttl < 0 {
+		return key, nil
+	}
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+	if c.keys == nil {
+		c.keys = make(map[string]*cachedKey)
+	}
+	entry := &cachedKey{key: key}
+	entry.timer = time.AfterFunc(ttl, func() { c.evict(indexName, entry) })
+	c.keys[indexName] = entry
+	return append([]byte(nil), key...), nil
+}
+
+// evict removes and zeroes entry if it is still cached under indexName.
+func (c *keyCache) evict(indexName string, entry *cachedKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keys[indexName] == entry {
+		delete(c.keys, indexName)
+		cyborgdb.Zeroize(entry.key)
+	}
+}
+
+// close zeroes and drops every cached key.
+func (c *keyCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, entry := range c.keys {
+		entry.timer.Stop()
+		cyborgdb.Zeroize(entry.key)
+		delete(c.keys, name)
+	}
+}
+
+// doJSON sends req and decodes a JSON response into out.
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kms: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("kms: failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w with status %d: %s", ErrRequestFailed, resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("kms: failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// newJSONRequest builds a POST request with a JSON body.
+func newJSONRequest(ctx context.Context, url string, payload interface{}) (*http.Request, []byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("kms: failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("kms: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, body, nil
+}
@@ -0,0 +1,82 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestKeyCache(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	unwrap := func(context.Context) ([]byte, error) {
+		calls++
+		return bytes.Repeat([]byte{7}, 32), nil
+	}
+	var c keyCache
+
+	first, err := c.get(ctx, "docs", time.Hour, unwrap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0] = 0 // callers own their copy
+	second, _ := c.get(ctx, "docs", time.Hour, unwrap)
+	if calls != 1 || second[0] != 7 {
+		t.Fatalf("calls = %d, key[0] = %d; want a single unwrap and an intact cached key", calls, second[0])
+	}
+
+	cached := c.keys["docs"].key
+	c.close()
+	if !bytes.Equal(cached, make([]byte, 32)) {
+		t.Error("close did not zero the cached key")
+	}
+	if _, err := c.get(ctx, "docs", time.Hour, unwrap); err != nil || calls != 2 {
+		t.Errorf("get after close: calls = %d, err = %v; want a fresh unwrap", calls, err)
+	}
+	c.close()
+}
+
+func TestKeyCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	unwrap := func(context.Context) ([]byte, error) {
+		calls++
+		return bytes.Repeat([]byte{7}, 32), nil
+	}
+	var c keyCache
+
+	if _, err := c.get(ctx, "docs", 10*time.Millisecond, unwrap); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	cached := c.keys["docs"].key
+	c.mu.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		_, ok := c.keys["docs"]
+		c.mu.Unlock()
+		if !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("key was not evicted after its TTL")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	c.mu.Lock()
+	zeroed := bytes.Equal(cached, make([]byte, 32))
+	c.mu.Unlock()
+	if !zeroed {
+		t.Error("expired key was not zeroed")
+	}
+
+	if _, err := c.get(ctx, "docs", -1, unwrap); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.keys["docs"]; ok || calls != 2 {
+		t.Errorf("negative TTL cached the key (calls = %d)", calls)
+	}
+}
@@ -0,0 +1,94 @@
+package kms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// Compile-time check that VaultProvider satisfies cyborgdb.KeyProvider.
+var _ cyborgdb.KeyProvider = (*VaultProvider)(nil)
+
+// VaultProvider unwraps index keys with HashiCorp Vault's transit secrets engine.
+type VaultProvider struct {
+	// Address is the Vault server address, e.g. "https://vault.example.com:8200" (required).
+	Address string
+
+	// Token is the Vault token sent in X-Vault-Token (required).
+	Token string
+
+	// Namespace optionally sets X-Vault-Namespace (Vault Enterprise).
+	Namespace string
+
+	// Mount is the transit engine mount path (default "transit").
+	Mount string
+
+	// KeyName is the transit key used to wrap index keys (required).
+	KeyName string
+
+	// WrappedKeys holds the transit ciphertext ("vault:v1:...") of each index key.
+	WrappedKeys WrappedKeys
+
+	// HTTPClient is used for requests (default http.DefaultClient).
+	HTTPClient *http.Client
+
+	// CacheTTL is how long an unwrapped key is reused before the KMS is asked
+	// again (default DefaultCacheTTL; negative disables caching).
+	CacheTTL time.Duration
+
+	cache keyCache
+}
+
+// IndexKey implements cyborgdb.KeyProvider.
+func (p *VaultProvider) IndexKey(ctx context.Context, indexName string) ([]byte, error) {
+	return p.cache.get(ctx, indexName, p.CacheTTL, func(ctx context.Context) ([]byte, error) {
+		wrapped, err := p.WrappedKeys.lookup(indexName)
+		if err != nil {
+			return nil, err
+		}
+		return p.decrypt(ctx, string(wrapped))
+	})
+}
+
+// Close zeroes and drops every cached key. The provider remains usable and
+// unwraps keys again on demand.
+func (p *VaultProvider) Close() error {
+	p.cache.close()
+	return nil
+}
+
+func (p *VaultProvider) decrypt(ctx context.Context, ciphertext string) ([]byte, error) {
+	mount := p.Mount
+	if mount == "" {
+		mount = "transit"
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/decrypt/%s", strings.TrimRight(p.Address, "/"), strings.Trim(mount, "/"), p.KeyName)
+	req, _, err := newJSONRequest(ctx, url, map[string]string{"ciphertext": ciphertext})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	var out struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := doJSON(p.HTTPClient, req, &out); err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(out.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("kms: invalid plaintext in Vault response: %w", err)
+	}
+	return key, nil
+}
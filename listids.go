@@ -0,0 +1,98 @@
+// listids.go implements listing the IDs of an index that match an ID prefix
+// or a metadata filter.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ListIDsOption narrows the IDs returned by ListIDs.
+type ListIDsOption func(*listIDsOptions)
+
+type listIDsOptions struct {
+	prefix string
+	filter map[string]interface{}
+}
+
+// WithIDPrefix lists only IDs starting with prefix, e.g. "tenant-a/" or
+// "doc42#" for the chunks of one document.
+func WithIDPrefix(prefix string) ListIDsOption {
+	return func(o *listIDsOptions) { o.prefix = prefix }
+}
+
+// WithMetadataFilter lists only IDs whose metadata matches filter, in the
+// syntax of QueryParams.Filters (see MatchFilter).
+//
+// The service cannot filter ID listings, so the metadata of every listed ID
+// (after any WithIDPrefix) is fetched and matched client-side. Combine it
+// with WithIDPrefix where possible to limit the items fetched.
+//
+// Example:
+//
+//	resp, err := index.ListIDs(ctx,
+//		cyborgdb.WithIDPrefix("doc42#"),
+//		cyborgdb.WithMetadataFilter(map[string]interface{}{"lang": "en"}))
+func WithMetadataFilter(filter map[string]interface{}) ListIDsOption {
+	return func(o *listIDsOptions) { o.filter = filter }
+}
+
+// listIDsFiltered lists every ID and keeps those matching options.
+func (e *EncryptedIndex) listIDsFiltered(ctx context.Context, options listIDsOptions) (*ListIDsResponse, error) {
+	listed, err := e.listIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := listed.Ids
+	if options.prefix != "" {
+		kept := ids[:0:0]
+		for _, id := range ids {
+			if strings.HasPrefix(id, options.prefix) {
+				kept = append(kept, id)
+			}
+		}
+		ids = kept
+	}
+	if len(options.filter) > 0 {
+		if ids, err = e.filterIDs(ctx, ids, options.filter); err != nil {
+			return nil, err
+		}
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return &ListIDsResponse{Ids: ids, Count: int32(len(ids))}, nil
+}
+
+// filterIDs returns the IDs in ids whose metadata matches filter, in order.
+func (e *EncryptedIndex) filterIDs(ctx context.Context, ids []string, filter map[string]interface{}) ([]string, error) {
+	var kept []string
+	err := e.matchIDs(ctx, ids, filter, func(id string) { kept = append(kept, id) })
+	return kept, err
+}
+
+// matchIDs calls match for each ID in ids whose metadata matches filter, in
+// order. Metadata is fetched a few chunks at a time to bound memory use.
+func (e *EncryptedIndex) matchIDs(ctx context.Context, ids []string, filter map[string]interface{}, match func(id string)) error {
+	filter, err := e.rewriteHashedFilter(filter)
+	if err != nil {
+		return err
+	}
+	for _, batch := range chunkIDs(ids, DefaultGetChunkSize*DefaultGetConcurrency) {
+		resp, err := e.Get(ctx, batch, []string{"metadata"})
+		if err != nil {
+			return fmt.Errorf("failed to fetch metadata: %w", err)
+		}
+		for _, item := range resp.Results {
+			ok, err := MatchFilter(filter, item.Metadata)
+			if err != nil {
+				return err
+			}
+			if ok {
+				match(item.Id)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+// lowalloc.go implements a low-allocation query path for latency-sensitive
+// services: responses are read into reusable buffers and parsed by a small
+// purpose-built decoder instead of through map-based JSON decoding.
+package cyborgdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ErrMalformedResponse is returned when a query response cannot be parsed.
+var ErrMalformedResponse = fmt.Errorf("malformed query response")
+
+// QueryBuffer holds reusable storage for QueryInto. After a call, Results
+// holds the hits; they are valid until the buffer is reused or released.
+// A QueryBuffer must not be used by two queries at once.
+type QueryBuffer struct {
+	// Results holds the hits of the last query, closest first.
+	Results []QueryResult
+
+	// SkipMetadata omits metadata from requests and results, avoiding the
+	// cost of decoding it into maps.
+	SkipMetadata bool
+
+	body bytes.Buffer
+}
+
+// Reset clears the results while keeping their storage for reuse.
+func (b *QueryBuffer) Reset() {
+	for i := range b.Results {
+		b.Results[i].Metadata = nil
+	}
+	b.Results = b.Results[:0]
+	b.body.Reset()
+}
+
+var queryBufferPool = sync.Pool{New: func() interface{} { return new(QueryBuffer) }}
+
+// AcquireQueryBuffer returns an empty QueryBuffer from a shared pool.
+// Release it with ReleaseQueryBuffer once its results are no longer used.
+func AcquireQueryBuffer() *QueryBuffer {
+	return queryBufferPool.Get().(*QueryBuffer)
+}
+
+// ReleaseQueryBuffer resets buf and returns it to the shared pool.
+func ReleaseQueryBuffer(buf *QueryBuffer) {
+	buf.Reset()
+	buf.SkipMetadata = false
+	queryBufferPool.Put(buf)
+}
+
+// QueryInto performs a single-vector similarity search like QueryOne, but
+// writes the results into buf, reusing its result slice, vector storage, and
+// response buffer across calls. Set buf.SkipMetadata to skip metadata
+// entirely. Client-side post-processing options (RerankExact, MMR, MinScore,
+// sparse vectors) are not applied on this path; filters on encrypted and
+// hashed metadata fields, and decryption of encrypted fields, are.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vector: The query vector
+//   - buf: Buffer receiving the results, e.g. from AcquireQueryBuffer
+//   - opts: Optional settings such as WithTopK, WithFilters, WithInclude
+//
+// Returns:
+//   - error: Any error encountered; buf.Results is empty on error
+//
+// Example:
+//
+//	buf := cyborgdb.AcquireQueryBuffer()
+//	defer cyborgdb.ReleaseQueryBuffer(buf)
+//	buf.SkipMetadata = true
+//	if err := index.QueryInto(ctx, vec, buf, cyborgdb.WithTopK(10)); err != nil {
+//		return err
+//	}
+//	for _, hit := range buf.Results {
+//		use(hit.ID, hit.Distance)
+//	}
+func (e *EncryptedIndex) QueryInto(ctx context.Context, vector []float32, buf *QueryBuffer, opts ...QueryOption) error {
+	buf.Reset()
+	if len(vector) == 0 {
+		return ErrEmptyQueryVector
+	}
+
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.queryTimeout)
+	defer cancel()
+
+	params := QueryParams{QueryVector: vector}
+	for _, opt := range opts {
+		opt(&params)
+	}
+	if err := e.opts.checkInclude(params.Include); err != nil {
+		return err
+	}
+	if err := e.opts.checkEncryptedFilter(params.Filters); err != nil {
+		return err
+	}
+	filters, err := e.rewriteHashedFilter(params.Filters)
+	if err != nil {
+		return err
+	}
+	params.Filters = filters
+	if buf.SkipMetadata {
+		requested := params.Include
+		if requested == nil {
+			requested = defaultQueryInclude
+		}
+		include := make([]string, 0, len(requested))
+		for _, f := range requested {
+			if f != IncludeMetadata {
+				include = append(include, f)
+			}
+		}
+		params.Include = include
+	}
+
+	req := e.newQueryRequest(params)
+	if _, err := e.client.PostJSONRaw(ctx, "DefaultAPIService.QueryVectorsV1VectorsQueryPost", "/v1/vectors/query", &req, &buf.body); err != nil {
+		return err
+	}
+
+	d := respDecoder{data: buf.body.Bytes(), skipMetadata: buf.SkipMetadata, numbers: e.opts.numberDecoding}
+	results, err := d.decodeQueryResponse(buf.Results[:0])
+	if err != nil {
+		buf.Results = results[:0]
+		return err
+	}
+	buf.Results = results
+	for i := range buf.Results {
+		buf.Results[i].Score = Similarity(e.GetMetric(), buf.Results[i].Distance)
+	}
+	if err := e.decryptQueryResults(buf.Results); err != nil {
+		buf.Results = buf.Results[:0]
+		return err
+	}
+	return nil
+}
+
+// respDecoder parses a single-query response without reflection, appending
+// into reused result and vector storage.
+type respDecoder struct {
+	data         []byte
+	pos          int
+	skipMetadata bool
+	numbers      NumberDecoding
+}
+
+// decodeQueryResponse parses {"results": [item, ...]} into dst.
+func (d *respDecoder) decodeQueryResponse(dst []QueryResult) ([]QueryResult, error) {
+	if err := d.expect('{'); err != nil {
+		return dst, err
+	}
+	for first := true; ; first = false {
+		if d.peek() == '}' {
+			d.pos++
+			return dst, nil
+		}
+		if !first {
+			if err := d.expect(','); err != nil {
+				return dst, err
+			}
+		}
+		key, err := d.key()
+		if err != nil {
+			return dst, err
+		}
+		if string(key) != "results" {
+			if err := d.skipValue(); err != nil {
+				return dst, err
+			}
+			continue
+		}
+
+		if err := d.expect('['); err != nil {
+			return dst, err
+		}
+		for n := 0; d.peek() != ']'; n++ {
+			if n > 0 {
+				if err := d.expect(','); err != nil {
+					return dst, err
+				}
+			}
+			if d.peek() == '[' {
+				return dst, fmt.Errorf("%w: batch results are not supported", ErrMalformedResponse)
+			}
+			if len(dst) < cap(dst) {
+				dst = dst[:len(dst)+1]
+			} else {
+				dst = append(dst, QueryResult{})
+			}
+			if err := d.decodeItem(&dst[len(dst)-1]); err != nil {
+				return dst, err
+			}
+		}
+		d.pos++
+	}
+}
+
+// decodeItem parses one result object into r, reusing r.Vector's storage.
+func (d *respDecoder) decodeItem(r *QueryResult) error {
+	vector := r.Vector[:0]
+	*r = QueryResult{}
+	if err := d.expect('{'); err != nil {
+		return err
+	}
+	for first := true; ; first = false {
+		if d.peek() == '}' {
+			d.pos++
+			r.Vector = vector // empty but keeps its storage when no vector was sent
+			return nil
+		}
+		if !first {
+			if err := d.expect(','); err != nil {
+				return err
+			}
+		}
+		key, err := d.key()
+		if err != nil {
+			return err
+		}
+
+		switch string(key) {
+		case "id":
+			if r.ID, err = d.str(); err != nil {
+				return err
+			}
+		case "distance":
+			if d.peekLiteral("null") {
+				d.pos += 4
+				continue
+			}
+			f, err := d.number()
+			if err != nil {
+				return err
+			}
+			r.Distance = float32(f)
+		case "vector":
+			if d.peekLiteral("null") {
+				d.pos += 4
+				continue
+			}
+			if err := d.expect('['); err != nil {
+				return err
+			}
+			for n := 0; d.peek() != ']'; n++ {
+				if n > 0 {
+					if err := d.expect(','); err != nil {
+						return err
+					}
+				}
+				f, err := d.number()
+				if err != nil {
+					return err
+				}
+				vector = append(vector, float32(f))
+			}
+			d.pos++
+		case "metadata":
+			start := d.skipWS()
+			if err := d.skipValue(); err != nil {
+				return err
+			}
+			if !d.skipMetadata && d.data[start] == '{' {
+				if err := d.decodeMetadata(d.data[start:d.pos], &r.Metadata); err != nil {
+					return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+				}
+			}
+		default:
+			if err := d.skipValue(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// decodeMetadata decodes a metadata object using the configured number mode.
+func (d *respDecoder) decodeMetadata(data []byte, m *map[string]interface{}) error {
+	if d.numbers == NumberFloat64 {
+		return json.Unmarshal(data, m)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+	*m = convertNumbers(d.numbers, *m)
+	return nil
+}
+
+// skipWS advances past whitespace and returns the new position.
+func (d *respDecoder) skipWS() int {
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return d.pos
+		}
+	}
+	return d.pos
+}
+
+// peek returns the next non-space byte without consuming it, or 0 at the end.
+func (d *respDecoder) peek() byte {
+	if d.skipWS() >= len(d.data) {
+		return 0
+	}
+	return d.data[d.pos]
+}
+
+func (d *respDecoder) peekLiteral(lit string) bool {
+	d.skipWS()
+	return bytes.HasPrefix(d.data[d.pos:], []byte(lit))
+}
+
+func (d *respDecoder) expect(c byte) error {
+	if d.peek() != c {
+		return fmt.Errorf("%w: expected %q at offset %d", ErrMalformedResponse, c, d.pos)
+	}
+	d.pos++
+	return nil
+}
+
+// key parses an object key and the following colon. The returned bytes
+// alias the response and are only valid for comparison.
+func (d *respDecoder) key() ([]byte, error) {
+	raw, _, err := d.rawString()
+	if err != nil {
+		return nil, err
+	}
+	return raw, d.expect(':')
+}
+
+// str parses a string value.
+func (d *respDecoder) str() (string, error) {
+	start := d.skipWS()
+	raw, escaped, err := d.rawString()
+	if err != nil {
+		return "", err
+	}
+	if !escaped {
+		return string(raw), nil
+	}
+	s, err := strconv.Unquote(string(d.data[start:d.pos]))
+	if err != nil {
+		// JSON escapes such as \/ are not valid Go escapes.
+		if err := json.Unmarshal(d.data[start:d.pos], &s); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+		}
+	}
+	return s, nil
+}
+
+// rawString consumes a string and returns its raw contents and whether it
+// contains escapes.
+func (d *respDecoder) rawString() ([]byte, bool, error) {
+	if err := d.expect('"'); err != nil {
+		return nil, false, err
+	}
+	start, escaped := d.pos, false
+	for d.pos < len(d.data) {
+		switch d.data[d.pos] {
+		case '\\':
+			escaped = true
+			d.pos += 2
+			continue
+		case '"':
+			raw := d.data[start:d.pos]
+			d.pos++
+			return raw, escaped, nil
+		}
+		d.pos++
+	}
+	return nil, false, fmt.Errorf("%w: unterminated string", ErrMalformedResponse)
+}
+
+// number parses a JSON number.
+func (d *respDecoder) number() (float64, error) {
+	start := d.skipWS()
+	for d.pos < len(d.data) {
+		c := d.data[d.pos]
+		if (c < '0' || c > '9') && c != '-' && c != '+' && c != '.' && c != 'e' && c != 'E' {
+			break
+		}
+		d.pos++
+	}
+	f, err := strconv.ParseFloat(string(d.data[start:d.pos]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	return f, nil
+}
+
+// skipValue consumes any JSON value.
+func (d *respDecoder) skipValue() error {
+	switch c := d.peek(); c {
+	case '"':
+		_, _, err := d.rawString()
+		return err
+	case '{', '[':
+		depth := 0
+		for d.pos < len(d.data) {
+			switch d.data[d.pos] {
+			case '"':
+				if _, _, err := d.rawString(); err != nil {
+					return err
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					d.pos++
+					return nil
+				}
+			}
+			d.pos++
+		}
+		return fmt.Errorf("%w: unterminated value", ErrMalformedResponse)
+	case 0:
+		return fmt.Errorf("%w: unexpected end of response", ErrMalformedResponse)
+	default:
+		for d.pos < len(d.data) {
+			switch d.data[d.pos] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return nil
+			}
+			d.pos++
+		}
+		return nil
+	}
+}
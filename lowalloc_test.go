@@ -0,0 +1,74 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestQueryIntoInclude(t *testing.T) {
+	var mu sync.Mutex
+	var sent [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/indexes/describe":
+			w.Write([]byte(`{"index_name":"docs","index_type":"ivfflat","is_trained":false,` +
+				`"index_config":{"type":"ivfflat","dimension":2}}`))
+		case "/v1/vectors/query":
+			var req struct {
+				Include []string `json:"include"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			sent = append(sent, req.Include)
+			mu.Unlock()
+			w.Write([]byte(`{"results":[{"id":"a","distance":0.5}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithStrictInclude())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	index, err := client.LoadIndex(ctx, "docs", testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := cyborgdb.AcquireQueryBuffer()
+	defer cyborgdb.ReleaseQueryBuffer(buf)
+	buf.SkipMetadata = true
+	if err := index.QueryInto(ctx, []float32{1, 0}, buf); err != nil {
+		t.Fatal(err)
+	}
+	if len(buf.Results) != 1 || buf.Results[0].Distance != 0.5 {
+		t.Errorf("results = %+v, want a at distance 0.5", buf.Results)
+	}
+	if err := index.QueryInto(ctx, []float32{1, 0}, buf, cyborgdb.WithInclude("vector", "metadata")); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	got := sent
+	mu.Unlock()
+	if want := [][]string{{"distance"}, {"vector"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("include sent = %q, want %q", got, want)
+	}
+
+	// Strict mode rejects unknown fields as Query does.
+	_, queryErr := index.QueryOne(ctx, []float32{1, 0}, cyborgdb.WithInclude("vectors"))
+	intoErr := index.QueryInto(ctx, []float32{1, 0}, buf, cyborgdb.WithInclude("vectors"))
+	if !errors.Is(queryErr, cyborgdb.ErrInvalidInclude) || !errors.Is(intoErr, cyborgdb.ErrInvalidInclude) {
+		t.Errorf("unknown include field: QueryOne err = %v, QueryInto err = %v, want ErrInvalidInclude", queryErr, intoErr)
+	}
+}
@@ -0,0 +1,170 @@
+// metadata.go implements client-side validation of item metadata, so bad
+// payloads fail with the offending key path instead of an opaque server error.
+package cyborgdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+const (
+	// DefaultMaxMetadataBytes is the default limit on an item's encoded
+	// metadata size.
+	DefaultMaxMetadataBytes = 64 << 10
+	// DefaultMaxMetadataDepth is the default limit on metadata nesting; a
+	// flat map has depth 1.
+	DefaultMaxMetadataDepth = 16
+)
+
+// ErrInvalidMetadata is wrapped by every MetadataError.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// MetadataError reports metadata that failed validation.
+type MetadataError struct {
+	// Path locates the offending value, e.g. "tags[2].name"; empty for
+	// problems with the metadata as a whole.
+	Path string
+	// Reason explains the problem.
+	Reason string
+}
+
+func (e *MetadataError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%v: %s", ErrInvalidMetadata, e.Reason)
+	}
+	return fmt.Sprintf("%v at %q: %s", ErrInvalidMetadata, e.Path, e.Reason)
+}
+
+func (e *MetadataError) Unwrap() error {
+	return ErrInvalidMetadata
+}
+
+// MetadataLimits bounds the metadata accepted by ValidateMetadata. Zero
+// fields use the defaults above.
+type MetadataLimits struct {
+	// MaxBytes limits the JSON-encoded size of one item's metadata.
+	MaxBytes int
+	// MaxDepth limits how deeply maps and slices may nest.
+	MaxDepth int
+}
+
+// WithMetadataValidation validates the metadata of every upserted item
+// against limits before the request is sent, failing the upsert with a
+// *MetadataError on the first bad item.
+func WithMetadataValidation(limits MetadataLimits) ClientOption {
+	return func(o *clientOptions) { o.metadataLimits = &limits }
+}
+
+// ValidateMetadata checks m against the default MetadataLimits.
+func ValidateMetadata(m map[string]interface{}) error {
+	return MetadataLimits{}.Validate(m)
+}
+
+// Validate checks that m holds only JSON-encodable values (no channels,
+// functions, complex numbers, NaN or Inf), nests no deeper than MaxDepth, and
+// encodes to at most MaxBytes.
+//
+// Returns:
+//   - error: nil, or a *MetadataError wrapping ErrInvalidMetadata
+func (l MetadataLimits) Validate(m map[string]interface{}) error {
+	if m == nil {
+		return nil
+	}
+	maxDepth := l.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxMetadataDepth
+	}
+	if err := validateMetadataValue(reflect.ValueOf(m), "", 0, maxDepth); err != nil {
+		return err
+	}
+
+	maxBytes := l.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxMetadataBytes
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return &MetadataError{Reason: err.Error()}
+	}
+	if len(data) > maxBytes {
+		return &MetadataError{Reason: fmt.Sprintf("encoded size %d bytes exceeds limit of %d", len(data), maxBytes)}
+	}
+	return nil
+}
+
+// validateMetadataValue walks v, which sits at path and depth, and reports
+// the first value JSON cannot represent.
+func validateMetadataValue(v reflect.Value, path string, depth, maxDepth int) error {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if depth++; depth > maxDepth {
+			return &MetadataError{Path: path, Reason: fmt.Sprintf("nested deeper than %d levels", maxDepth)}
+		}
+		switch v.Type().Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return &MetadataError{Path: path, Reason: fmt.Sprintf("unsupported map key type %s", v.Type().Key())}
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			if err := validateMetadataValue(iter.Value(), child, depth, maxDepth); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices encode as base64 strings.
+			return nil
+		}
+		if depth++; depth > maxDepth {
+			return &MetadataError{Path: path, Reason: fmt.Sprintf("nested deeper than %d levels", maxDepth)}
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := validateMetadataValue(v.Index(i), path+"["+strconv.Itoa(i)+"]", depth, maxDepth); err != nil {
+				return err
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return &MetadataError{Path: path, Reason: fmt.Sprintf("unsupported float value %v", f)}
+		}
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return &MetadataError{Path: path, Reason: fmt.Sprintf("unsupported value type %s", v.Type())}
+	case reflect.Struct:
+		// Structs are encoded through their own JSON methods or fields.
+		if _, err := json.Marshal(v.Interface()); err != nil {
+			return &MetadataError{Path: path, Reason: err.Error()}
+		}
+	}
+	return nil
+}
+
+// validateItemsMetadata applies the configured metadata limits to items.
+func (o *clientOptions) validateItemsMetadata(items []VectorItem) error {
+	if o == nil || o.metadataLimits == nil {
+		return nil
+	}
+	for _, item := range items {
+		if err := o.metadataLimits.Validate(item.Metadata); err != nil {
+			return fmt.Errorf("item %q: %w", item.Id, err)
+		}
+	}
+	return nil
+}
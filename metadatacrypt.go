@@ -0,0 +1,327 @@
+// metadatacrypt.go implements client-side encryption of selected metadata
+// fields, for values that must stay opaque to the service itself.
+//
+// Values are sealed with AES-256-GCM under a key derived from the index key
+// with HKDF-SHA256, so no extra key has to be managed: any handle opened with
+// the index key can read them, and nothing else can. The item ID and field
+// name are authenticated with each value, so ciphertexts cannot be swapped
+// between items or fields unnoticed.
+package cyborgdb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// encryptedValuePrefix marks an encrypted metadata value.
+const encryptedValuePrefix = "cyborgdb:enc:v1:"
+
+// metadataKeyInfo is the HKDF info string of the metadata encryption key.
+const metadataKeyInfo = "cyborgdb metadata encryption v1"
+
+var (
+	// ErrMetadataDecryption is returned when an encrypted metadata value
+	// cannot be decrypted, because it was tampered with or sealed under a
+	// different index key.
+	ErrMetadataDecryption = errors.New("failed to decrypt metadata value")
+
+	// ErrEncryptedFieldFilter is returned when a query filters on an
+	// encrypted field, which the service can never match.
+	ErrEncryptedFieldFilter = errors.New("cannot filter on an encrypted metadata field")
+
+	// ErrReservedMetadataValue is returned when an upserted metadata value
+	// starts with the prefix that marks encrypted values. Reads would try to
+	// decrypt it: it is either plaintext that would be misread, or
+	// ciphertext sealed for another item, index, or key. Values read back
+	// through a handle with WithEncryptedMetadata are already decrypted.
+	ErrReservedMetadataValue = errors.New("metadata value starts with the reserved encrypted-value prefix")
+)
+
+// WithEncryptedMetadata encrypts the values of the named top-level metadata
+// fields client-side before upsert and decrypts them in Get and Query
+// results. Any JSON value can be encrypted; it is stored as an opaque string.
+//
+// The service cannot filter on encrypted fields, so queries filtering on them
+// fail with ErrEncryptedFieldFilter; client-side filters such as ListIDs with
+// WithMetadataFilter still work, since they see decrypted values. For
+// server-side exact matches, see WithSearchableHashes. Every
+// client writing the index must use the same field list, or some values will
+// be stored in plaintext. String values starting with the prefix that marks
+// encrypted values are rejected with ErrReservedMetadataValue.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithEncryptedMetadata("ssn", "diagnosis"))
+func WithEncryptedMetadata(fields ...string) ClientOption {
+	return func(o *clientOptions) {
+		if o.encryptedFields == nil {
+			o.encryptedFields = make(map[string]bool, len(fields))
+		}
+		for _, f := range fields {
+			if o.hashedFields[f] {
+				o.setErr(fmt.Errorf("metadata field %q cannot be both hashed and encrypted", f))
+				return
+			}
+			o.encryptedFields[f] = true
+		}
+	}
+}
+
+// deriveIndexSubkey derives a 32-byte key for info from the index key.
+func (e *EncryptedIndex) deriveIndexSubkey(info string) ([]byte, error) {
+	master, err := hex.DecodeString(e.indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid index key: %w", err)
+	}
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, nil, []byte(info)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// metadataAEAD returns the AES-GCM cipher for this index's metadata.
+func (e *EncryptedIndex) metadataAEAD() (cipher.AEAD, error) {
+	key, err := e.deriveIndexSubkey(metadataKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// metadataAAD binds a ciphertext to its item and field.
+func metadataAAD(id, field string) []byte {
+	return []byte(id + "\x00" + field)
+}
+
+// encryptItemsMetadata returns items with the configured fields encrypted,
+// copying the metadata of every item it changes.
+func (e *EncryptedIndex) encryptItemsMetadata(items []VectorItem) ([]VectorItem, error) {
+	fields := e.opts.encryptedFields
+	if len(fields) == 0 || isInternalIndex(e.indexName) {
+		return items, nil
+	}
+	aead, err := e.metadataAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []VectorItem
+	for i, item := range items {
+		// Reads decrypt every prefixed value, not only configured fields.
+		for field, value := range item.Metadata {
+			if isEncryptedValue(value) {
+				return nil, fmt.Errorf("item %q field %q: %w", item.Id, field, ErrReservedMetadataValue)
+			}
+		}
+		var meta map[string]interface{}
+		for field := range fields {
+			value, ok := item.Metadata[field]
+			if !ok || value == nil {
+				continue
+			}
+			plaintext, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("item %q: failed to encode %q for encryption: %w", item.Id, field, err)
+			}
+			nonce := make([]byte, aead.NonceSize())
+			if _, err := rand.Read(nonce); err != nil {
+				return nil, err
+			}
+			sealed := aead.Seal(nonce, nonce, plaintext, metadataAAD(item.Id, field))
+			if meta == nil {
+				meta = make(map[string]interface{}, len(item.Metadata))
+				for k, v := range item.Metadata {
+					meta[k] = v
+				}
+			}
+			meta[field] = encryptedValuePrefix + base64.RawURLEncoding.EncodeToString(sealed)
+		}
+		if meta == nil {
+			continue
+		}
+		if out == nil {
+			out = make([]VectorItem, len(items))
+			copy(out, items)
+		}
+		out[i].Metadata = meta
+	}
+	if out == nil {
+		return items, nil
+	}
+	return out, nil
+}
+
+// decryptMetadata returns metadata with every encrypted value decrypted,
+// decoding numbers according to mode, or metadata itself if it holds none.
+// It does not modify metadata, which may be shared with the query cache.
+func decryptMetadata(aead cipher.AEAD, mode NumberDecoding, id string, metadata map[string]interface{}) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	for field, value := range metadata {
+		s, ok := value.(string)
+		if !ok || !strings.HasPrefix(s, encryptedValuePrefix) {
+			continue
+		}
+		sealed, err := base64.RawURLEncoding.DecodeString(s[len(encryptedValuePrefix):])
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return nil, fmt.Errorf("%w: item %q field %q is malformed", ErrMetadataDecryption, id, field)
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, ciphertext, metadataAAD(id, field))
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %q field %q", ErrMetadataDecryption, id, field)
+		}
+		var decoded interface{}
+		dec := json.NewDecoder(bytes.NewReader(plaintext))
+		if mode != NumberFloat64 {
+			dec.UseNumber()
+		}
+		if err := dec.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("%w: item %q field %q: %v", ErrMetadataDecryption, id, field, err)
+		}
+		if mode == NumberInt64 {
+			decoded = convertNumber(decoded)
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(metadata))
+			for k, v := range metadata {
+				out[k] = v
+			}
+		}
+		out[field] = decoded
+	}
+	if out == nil {
+		return metadata, nil
+	}
+	return out, nil
+}
+
+// isEncryptedValue reports whether value carries the encrypted-value prefix.
+func isEncryptedValue(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, encryptedValuePrefix)
+}
+
+// decryptGetResponse decrypts the metadata of every item in resp in place.
+func (e *EncryptedIndex) decryptGetResponse(resp *GetResponse) error {
+	if len(e.opts.encryptedFields) == 0 || resp == nil {
+		return nil
+	}
+	aead, err := e.metadataAEAD()
+	if err != nil {
+		return err
+	}
+	for i := range resp.Results {
+		item := &resp.Results[i]
+		if item.Metadata, err = decryptMetadata(aead, e.opts.numberDecoding, item.Id, item.Metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decryptQueryResponse decrypts the metadata of every result in resp. The
+// result lists must not be shared with the query cache; their metadata maps
+// may be, and are replaced rather than modified.
+func (e *EncryptedIndex) decryptQueryResponse(resp *QueryResponse) error {
+	if len(e.opts.encryptedFields) == 0 {
+		return nil
+	}
+	aead, err := e.metadataAEAD()
+	if err != nil {
+		return err
+	}
+	decrypt := func(items []QueryResultItem) error {
+		for i := range items {
+			meta, err := decryptMetadata(aead, e.opts.numberDecoding, items[i].Id, items[i].Metadata)
+			if err != nil {
+				return err
+			}
+			items[i].Metadata = meta
+		}
+		return nil
+	}
+	if r := resp.Results.ArrayOfQueryResultItem; r != nil {
+		if err := decrypt(*r); err != nil {
+			return err
+		}
+	}
+	if r := resp.Results.ArrayOfArrayOfQueryResultItem; r != nil {
+		for _, items := range *r {
+			if err := decrypt(items); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decryptQueryResults decrypts the metadata of results in place.
+func (e *EncryptedIndex) decryptQueryResults(results []QueryResult) error {
+	if len(e.opts.encryptedFields) == 0 {
+		return nil
+	}
+	aead, err := e.metadataAEAD()
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		r := &results[i]
+		if r.Metadata, err = decryptMetadata(aead, e.opts.numberDecoding, r.ID, r.Metadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkEncryptedFilter rejects filters on encrypted fields.
+func (o *clientOptions) checkEncryptedFilter(filter map[string]interface{}) error {
+	if len(o.encryptedFields) == 0 {
+		return nil
+	}
+	for _, field := range filterFields(filter) {
+		if o.encryptedFields[field] {
+			return fmt.Errorf("%w %q", ErrEncryptedFieldFilter, field)
+		}
+	}
+	return nil
+}
+
+// filterFields returns the field names a filter refers to, including those
+// inside $and and $or clauses.
+func filterFields(filter map[string]interface{}) []string {
+	var fields []string
+	for key, cond := range filter {
+		if key != "$and" && key != "$or" {
+			fields = append(fields, key)
+			continue
+		}
+		clauses, ok := filterList(cond)
+		if !ok {
+			continue
+		}
+		for _, clause := range clauses {
+			if m, ok := clause.(map[string]interface{}); ok {
+				fields = append(fields, filterFields(m)...)
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,160 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// storedMetadata returns the metadata the fake service holds for an item.
+func storedMetadata(fake *fakeService, index, id string) map[string]interface{} {
+	meta, _ := fake.item(index, id)["metadata"].(map[string]interface{})
+	return meta
+}
+
+// setStoredMetadata overwrites a field of an item held by the fake service.
+func setStoredMetadata(fake *fakeService, index, id, field string, value interface{}) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.indexes[index].items[id]["metadata"].(map[string]interface{})[field] = value
+}
+
+func TestEncryptedMetadataRoundTrip(t *testing.T) {
+	fake, client := newFakeService(t, cyborgdb.WithEncryptedMetadata("ssn", "profile"))
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	profile := map[string]interface{}{"age": float64(42), "smoker": false, "tags": []interface{}{"a", "b"}}
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"ssn": "123-45-6789", "profile": profile, "lang": "en"}},
+		{Id: "b", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"ssn": float64(7)}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := storedMetadata(fake, "docs", "a")
+	for _, field := range []string{"ssn", "profile"} {
+		if s, ok := stored[field].(string); !ok || !strings.HasPrefix(s, "cyborgdb:enc:v1:") {
+			t.Errorf("stored %s = %v, want an encrypted value", field, stored[field])
+		}
+	}
+	if stored["lang"] != "en" {
+		t.Errorf("stored lang = %v, want the plaintext \"en\"", stored["lang"])
+	}
+
+	got, err := index.Get(ctx, []string{"a", "b"}, []string{"metadata"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]interface{}{
+		"a": {"ssn": "123-45-6789", "profile": profile, "lang": "en"},
+		"b": {"ssn": float64(7)},
+	}
+	for _, item := range got.Results {
+		if !reflect.DeepEqual(item.Metadata, want[item.Id]) {
+			t.Errorf("Get %s metadata = %v, want %v", item.Id, item.Metadata, want[item.Id])
+		}
+	}
+
+	results, err := index.QueryOne(ctx, []float32{1, 0}, cyborgdb.WithTopK(2), cyborgdb.WithInclude("metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("query returned %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if !reflect.DeepEqual(r.Metadata, want[r.ID]) {
+			t.Errorf("query %s metadata = %v, want %v", r.ID, r.Metadata, want[r.ID])
+		}
+	}
+
+	_, err = index.QueryOne(ctx, []float32{1, 0}, cyborgdb.WithFilters(map[string]interface{}{"ssn": "123-45-6789"}))
+	if !errors.Is(err, cyborgdb.ErrEncryptedFieldFilter) {
+		t.Errorf("query filtering on an encrypted field: err = %v, want ErrEncryptedFieldFilter", err)
+	}
+}
+
+func TestEncryptedMetadataBoundToItemAndField(t *testing.T) {
+	fake, client := newFakeService(t, cyborgdb.WithEncryptedMetadata("ssn", "dob"))
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"ssn": "111", "dob": "1990-01-01"}},
+		{Id: "b", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"ssn": "222"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	a := storedMetadata(fake, "docs", "a")
+
+	// a's ssn moved to b: same field, different item.
+	setStoredMetadata(fake, "docs", "b", "ssn", a["ssn"])
+	if _, err := index.Get(ctx, []string{"b"}, []string{"metadata"}); !errors.Is(err, cyborgdb.ErrMetadataDecryption) {
+		t.Errorf("ciphertext swapped between items: err = %v, want ErrMetadataDecryption", err)
+	}
+
+	// a's dob moved into its ssn: same item, different field.
+	setStoredMetadata(fake, "docs", "b", "ssn", "222")
+	setStoredMetadata(fake, "docs", "a", "ssn", a["dob"])
+	if _, err := index.Get(ctx, []string{"a"}, []string{"metadata"}); !errors.Is(err, cyborgdb.ErrMetadataDecryption) {
+		t.Errorf("ciphertext swapped between fields: err = %v, want ErrMetadataDecryption", err)
+	}
+}
+
+func TestEncryptedMetadataWrongKey(t *testing.T) {
+	fake, client := newFakeService(t, cyborgdb.WithEncryptedMetadata("ssn"))
+	ctx := context.Background()
+	first := createFakeIndex(t, client, "first", testKey(1))
+	second := createFakeIndex(t, client, "second", testKey(2))
+	item := cyborgdb.VectorItem{Id: "a", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"ssn": "111"}}
+	for _, index := range []*cyborgdb.EncryptedIndex{first, second} {
+		if err := index.Upsert(ctx, []cyborgdb.VectorItem{item}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Same item and field, sealed under another index key.
+	setStoredMetadata(fake, "second", "a", "ssn", storedMetadata(fake, "first", "a")["ssn"])
+	if _, err := second.Get(ctx, []string{"a"}, []string{"metadata"}); !errors.Is(err, cyborgdb.ErrMetadataDecryption) {
+		t.Errorf("ciphertext from another key: err = %v, want ErrMetadataDecryption", err)
+	}
+}
+
+func TestEncryptedMetadataRejectsPrefixedValues(t *testing.T) {
+	fake, client := newFakeService(t, cyborgdb.WithEncryptedMetadata("ssn"))
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"ssn": "111"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	foreign := storedMetadata(fake, "docs", "a")["ssn"]
+
+	for name, meta := range map[string]map[string]interface{}{
+		"plaintext in an encrypted field":  {"ssn": "cyborgdb:enc:v1:not-really"},
+		"ciphertext in an encrypted field": {"ssn": foreign},
+		"plaintext in another field":       {"note": "cyborgdb:enc:v1:"},
+	} {
+		err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: "b", Vector: []float32{0, 1}, Metadata: meta}})
+		if !errors.Is(err, cyborgdb.ErrReservedMetadataValue) {
+			t.Errorf("%s: err = %v, want ErrReservedMetadataValue", name, err)
+		}
+	}
+	if fake.item("docs", "b") != nil {
+		t.Error("a rejected item was stored")
+	}
+
+	// Items read back are decrypted, so they can be upserted again.
+	got, err := index.Get(ctx, []string{"a"}, []string{"vector", "metadata"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := got.Results[0]
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: item.Id, Vector: item.Vector, Metadata: item.Metadata}}); err != nil {
+		t.Errorf("re-upserting an item read back: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint records how far a copy has progressed so it can be resumed.
+type Checkpoint struct {
+	// SourceIndex is the name of the index being copied.
+	SourceIndex string `json:"source_index"`
+
+	// DestIndex is the name of the destination index.
+	DestIndex string `json:"dest_index"`
+
+	// Offset is the number of IDs (in sorted order) already copied.
+	Offset int `json:"offset"`
+
+	// Total is the number of IDs in the source when the copy started.
+	Total int `json:"total"`
+}
+
+// Checkpointer persists copy checkpoints between runs.
+type Checkpointer interface {
+	// Load returns the saved checkpoint, or nil if none exists.
+	Load() (*Checkpoint, error)
+
+	// Save persists the checkpoint.
+	Save(cp Checkpoint) error
+}
+
+// CheckpointClearer is implemented by Checkpointers that can remove a saved
+// checkpoint. CopyIndex calls Clear once a copy completes, so the next copy
+// with the same Checkpointer starts from the beginning. Without it, the
+// caller must discard the checkpoint before reusing the Checkpointer.
+type CheckpointClearer interface {
+	// Clear removes the saved checkpoint, if any.
+	Clear() error
+}
+
+// FileCheckpointer stores the checkpoint as JSON in a file.
+type FileCheckpointer struct {
+	// Path is the checkpoint file location.
+	Path string
+}
+
+// Load implements Checkpointer.
+func (f FileCheckpointer) Load() (*Checkpoint, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to read checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("migrate: failed to parse checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// Save implements Checkpointer. The file is replaced atomically so an
+// interrupted write never leaves a truncated checkpoint behind.
+func (f FileCheckpointer) Save(cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("migrate: failed to marshal checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("migrate: failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("migrate: failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("migrate: failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return fmt.Errorf("migrate: failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Clear implements CheckpointClearer by removing the file.
+func (f FileCheckpointer) Clear() error {
+	if err := os.Remove(f.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("migrate: failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Package migrate copies encrypted indexes between CyborgDB deployments,
+// for example when moving from a self-hosted service to managed CyborgDB.
+//
+// Vectors are streamed in batches from the source index and upserted into the
+// destination, with optional progress reporting and resumable checkpoints.
+// Because the destination is created with its own key, a copy doubles as a
+// re-keying operation.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// DefaultBatchSize is the default number of vectors copied per batch.
+const DefaultBatchSize = 500
+
+// ErrCheckpointMismatch is returned when a saved checkpoint belongs to a different copy.
+var ErrCheckpointMismatch = errors.New("migrate: checkpoint does not match source and destination")
+
+// Progress reports how many vectors have been copied so far.
+type Progress struct {
+	Copied int
+	Total  int
+}
+
+// Options configures CopyIndex. Zero values fall back to defaults.
+type Options struct {
+	// BatchSize is the number of vectors fetched and upserted per batch. Default: 500.
+	BatchSize int
+
+	// OnProgress, if set, is called after each batch is copied.
+	OnProgress func(Progress)
+
+	// Checkpointer, if set, persists progress after each batch. When a
+	// checkpoint from a previous run is found, the copy resumes from it and the
+	// destination index is loaded instead of created. The checkpoint is
+	// cleared once the copy completes if Checkpointer implements
+	// CheckpointClearer.
+	Checkpointer Checkpointer
+}
+
+// copyInclude lists the fields fetched from the source for each vector.
+var copyInclude = []string{"vector", "metadata", "contents"}
+
+// CopyIndex copies every vector in src into a new index described by dstParams.
+//
+// dstParams.IndexKey is the destination key; pass the source key to keep it,
+// or a fresh key to re-key the data. Set dstParams.KeyProvider instead, e.g.
+// to a cyborgdb.SecretKey, to keep the key out of plain byte slices. The
+// source's schema, if any, is copied once every vector is. If
+// dstParams.IndexConfig is nil, the source index configuration is reused.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - src: Source index handle
+//   - dstClient: Client for the destination service (may be the source client)
+//   - dstParams: Parameters used to create the destination index
+//   - opts: Batching, progress, and checkpoint options
+//
+// Returns:
+//   - *cyborgdb.EncryptedIndex: Handle for the destination index
+//   - error: Any error encountered; with a Checkpointer the copy can be resumed
+//
+// Example:
+//
+//	dst, err := migrate.CopyIndex(ctx, src, managedClient, &cyborgdb.CreateIndexParams{
+//		IndexName: src.GetIndexName(),
+//		IndexKey:  newKey,
+//	}, migrate.Options{Checkpointer: migrate.FileCheckpointer{Path: "copy.ckpt"}})
+func CopyIndex(
+	ctx context.Context,
+	src *cyborgdb.EncryptedIndex,
+	dstClient *cyborgdb.Client,
+	dstParams *cyborgdb.CreateIndexParams,
+	opts Options,
+) (*cyborgdb.EncryptedIndex, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	listed, err := src.ListIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to list source IDs: %w", err)
+	}
+	ids := append([]string(nil), listed.Ids...)
+	sort.Strings(ids)
+
+	cp := Checkpoint{SourceIndex: src.GetIndexName(), DestIndex: dstParams.IndexName, Total: len(ids)}
+	if opts.Checkpointer != nil {
+		saved, err := opts.Checkpointer.Load()
+		if err != nil {
+			return nil, err
+		}
+		if saved != nil {
+			if saved.SourceIndex != cp.SourceIndex || saved.DestIndex != cp.DestIndex {
+				return nil, ErrCheckpointMismatch
+			}
+			cp.Offset = saved.Offset
+		}
+	}
+
+	dst, err := openDestination(ctx, src, dstClient, dstParams, cp.Offset > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for cp.Offset < len(ids) {
+		end := cp.Offset + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		resp, err := src.Get(ctx, ids[cp.Offset:end], copyInclude)
+		if err != nil {
+			return dst, fmt.Errorf("migrate: failed to read batch at offset %d: %w", cp.Offset, err)
+		}
+
+		items := make([]cyborgdb.VectorItem, len(resp.Results))
+		for i, r := range resp.Results {
+			items[i] = cyborgdb.VectorItem{
+				Id:       r.Id,
+				Vector:   r.Vector,
+				Metadata: r.Metadata,
+				Contents: r.Contents,
+			}
+		}
+		if err := dst.Upsert(cyborgdb.WithPreservedTimestamps(ctx), items); err != nil {
+			return dst, fmt.Errorf("migrate: failed to write batch at offset %d: %w", cp.Offset, err)
+		}
+
+		cp.Offset = end
+		if opts.Checkpointer != nil {
+			if err := opts.Checkpointer.Save(cp); err != nil {
+				return dst, err
+			}
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(Progress{Copied: cp.Offset, Total: cp.Total})
+		}
+	}
+
+	schema, err := src.GetSchema(ctx)
+	switch {
+	case errors.Is(err, cyborgdb.ErrNoSchema):
+	case err != nil:
+		return dst, fmt.Errorf("migrate: failed to read source schema: %w", err)
+	default:
+		if err := dst.SetSchema(ctx, schema); err != nil {
+			return dst, fmt.Errorf("migrate: failed to copy schema: %w", err)
+		}
+	}
+
+	if clearer, ok := opts.Checkpointer.(CheckpointClearer); ok {
+		if err := clearer.Clear(); err != nil {
+			return dst, err
+		}
+	}
+	return dst, nil
+}
+
+// openDestination creates the destination index, or loads it when resuming.
+func openDestination(
+	ctx context.Context,
+	src *cyborgdb.EncryptedIndex,
+	dstClient *cyborgdb.Client,
+	dstParams *cyborgdb.CreateIndexParams,
+	resuming bool,
+) (*cyborgdb.EncryptedIndex, error) {
+	if resuming {
+		var dst *cyborgdb.EncryptedIndex
+		var err error
+		if len(dstParams.IndexKey) == 0 && dstParams.KeyProvider != nil {
+			dst, err = dstClient.LoadIndexWithKeyProvider(ctx, dstParams.IndexName, dstParams.KeyProvider)
+		} else {
+			dst, err = dstClient.LoadIndex(ctx, dstParams.IndexName, dstParams.IndexKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("migrate: failed to load destination index: %w", err)
+		}
+		return dst, nil
+	}
+
+	params := *dstParams
+	if params.IndexConfig == nil {
+		if config := src.GetIndexConfig(); !config.IsZero() {
+			params.IndexConfig = config
+		}
+	}
+	dst, err := dstClient.CreateIndex(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to create destination index: %w", err)
+	}
+	return dst, nil
+}
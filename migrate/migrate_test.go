@@ -0,0 +1,115 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// newTestClient returns a client for an in-memory service with just enough
+// of the API for CopyIndex, and a function returning an index's item count.
+func newTestClient(t *testing.T) (*cyborgdb.Client, func(index string) int) {
+	t.Helper()
+	var mu sync.Mutex
+	indexes := map[string]map[string]map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req struct {
+			IndexName string                   `json:"index_name"`
+			Items     []map[string]interface{} `json:"items"`
+			Ids       []string                 `json:"ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var resp interface{} = map[string]string{"status": "success", "message": "ok"}
+		items := indexes[req.IndexName]
+		switch r.URL.Path {
+		case "/v1/indexes/list":
+			names := []string{}
+			for name := range indexes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			resp = map[string]interface{}{"indexes": names}
+		case "/v1/indexes/create":
+			indexes[req.IndexName] = map[string]map[string]interface{}{}
+		case "/v1/indexes/describe":
+			resp = map[string]interface{}{"index_name": req.IndexName, "index_type": "ivfflat", "is_trained": false,
+				"index_config": map[string]interface{}{"type": "ivfflat", "dimension": 2}}
+		case "/v1/vectors/upsert":
+			for _, item := range req.Items {
+				items[item["id"].(string)] = item
+			}
+		case "/v1/vectors/list_ids":
+			ids := []string{}
+			for id := range items {
+				ids = append(ids, id)
+			}
+			resp = map[string]interface{}{"ids": ids, "count": len(ids)}
+		case "/v1/vectors/get":
+			results := []map[string]interface{}{}
+			for _, id := range req.Ids {
+				if item, ok := items[id]; ok {
+					results = append(results, item)
+				}
+			}
+			resp = map[string]interface{}{"results": results}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := cyborgdb.NewClient(srv.URL, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, func(index string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(indexes[index])
+	}
+}
+
+func TestCopyIndexClearsCheckpoint(t *testing.T) {
+	client, count := newTestClient(t)
+	ctx := context.Background()
+	key := make([]byte, cyborgdb.KeySize)
+	src, err := client.CreateIndex(ctx, &cyborgdb.CreateIndexParams{IndexName: "src", IndexKey: key, IndexConfig: cyborgdb.IndexIVFFlat(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}},
+		{Id: "b", Vector: []float32{0, 1}},
+		{Id: "c", Vector: []float32{1, 1}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cp := FileCheckpointer{Path: filepath.Join(t.TempDir(), "copy.ckpt")}
+	opts := Options{BatchSize: 2, Checkpointer: cp}
+	// Reusing the checkpointer for a second copy must start afresh rather
+	// than find the finished run's checkpoint.
+	for _, dst := range []string{"dst1", "dst2"} {
+		if _, err := CopyIndex(ctx, src, client, &cyborgdb.CreateIndexParams{IndexName: dst, IndexKey: key}, opts); err != nil {
+			t.Fatalf("copy to %s: %v", dst, err)
+		}
+		if n := count(dst); n != 3 {
+			t.Fatalf("%s holds %d items, want 3", dst, n)
+		}
+		if saved, err := cp.Load(); err != nil || saved != nil {
+			t.Fatalf("checkpoint after copying to %s = %+v, %v; want none", dst, saved, err)
+		}
+	}
+	if err := cp.Clear(); err != nil {
+		t.Errorf("Clear with no checkpoint: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// numbers.go controls how numbers in returned metadata are decoded.
+package cyborgdb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NumberDecoding selects the Go type of numbers in returned metadata.
+type NumberDecoding int
+
+const (
+	// NumberFloat64 decodes every number as float64, the encoding/json
+	// default. Integers beyond 2^53 lose precision.
+	NumberFloat64 NumberDecoding = iota
+	// NumberJSON decodes numbers as json.Number, preserving their exact text.
+	NumberJSON
+	// NumberInt64 decodes integer literals that fit in int64 as int64 and all
+	// other numbers as float64.
+	NumberInt64
+)
+
+// WithMetadataNumbers sets how numbers in metadata returned by Get and Query
+// are decoded. The default, NumberFloat64, turns an upserted int 7 into
+// float64(7); NumberInt64 returns int64(7) instead, so numeric metadata keeps
+// its integer type across a round trip.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithMetadataNumbers(cyborgdb.NumberInt64))
+func WithMetadataNumbers(mode NumberDecoding) ClientOption {
+	return func(o *clientOptions) { o.numberDecoding = mode }
+}
+
+// resultMetadata is the part of a Get or Query response item that is
+// decoded again with the configured number mode.
+type resultMetadata struct {
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// redecodeMetadata re-reads the metadata of each result from the raw body of
+// httpResp, which the generated client leaves readable, using mode. set is
+// called with the batch index, item index, and decoded metadata.
+func redecodeMetadata(mode NumberDecoding, httpResp *http.Response, set func(batch, i int, m map[string]interface{})) error {
+	if mode == NumberFloat64 || httpResp == nil || httpResp.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	httpResp.Body = io.NopCloser(bytes.NewReader(body))
+	return redecodeMetadataBody(mode, body, set)
+}
+
+// redecodeMetadataBody is redecodeMetadata for a response body.
+func redecodeMetadataBody(mode NumberDecoding, body []byte, set func(batch, i int, m map[string]interface{})) error {
+	if mode == NumberFloat64 {
+		return nil
+	}
+	var envelope struct {
+		Results json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+
+	trimmed := bytes.TrimLeft(envelope.Results, " \t\r\n")
+	if len(trimmed) < 2 || trimmed[0] != '[' {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(envelope.Results))
+	dec.UseNumber()
+	if bytes.HasPrefix(bytes.TrimLeft(trimmed[1:], " \t\r\n"), []byte("[")) {
+		var batches [][]resultMetadata
+		if err := dec.Decode(&batches); err != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+		}
+		for b, items := range batches {
+			for i, item := range items {
+				set(b, i, convertNumbers(mode, item.Metadata))
+			}
+		}
+		return nil
+	}
+	var items []resultMetadata
+	if err := dec.Decode(&items); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	for i, item := range items {
+		set(0, i, convertNumbers(mode, item.Metadata))
+	}
+	return nil
+}
+
+// redecodeQueryMetadata applies mode to the metadata of resp.
+func redecodeQueryMetadata(mode NumberDecoding, httpResp *http.Response, resp *QueryResponse) error {
+	if resp == nil {
+		return nil
+	}
+	return redecodeMetadata(mode, httpResp, queryMetadataSetter(resp))
+}
+
+// queryMetadataSetter returns a setter that replaces metadata in resp.
+func queryMetadataSetter(resp *QueryResponse) func(batch, i int, m map[string]interface{}) {
+	return func(batch, i int, m map[string]interface{}) {
+		if r := resp.Results.ArrayOfQueryResultItem; r != nil && batch == 0 && i < len(*r) {
+			(*r)[i].Metadata = m
+		}
+		if r := resp.Results.ArrayOfArrayOfQueryResultItem; r != nil && batch < len(*r) && i < len((*r)[batch]) {
+			(*r)[batch][i].Metadata = m
+		}
+	}
+}
+
+// convertNumbers converts the json.Number values in m according to mode.
+func convertNumbers(mode NumberDecoding, m map[string]interface{}) map[string]interface{} {
+	if mode == NumberJSON || m == nil {
+		return m
+	}
+	for k, v := range m {
+		m[k] = convertNumber(v)
+	}
+	return m
+}
+
+// convertNumber converts json.Number values in v to int64 or float64.
+func convertNumber(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v
+	case map[string]interface{}:
+		for k, x := range v {
+			v[k] = convertNumber(x)
+		}
+		return v
+	case []interface{}:
+		for i, x := range v {
+			v[i] = convertNumber(x)
+		}
+		return v
+	default:
+		return v
+	}
+}
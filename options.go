@@ -0,0 +1,165 @@
+// options.go defines functional options for configuring a Client.
+package cyborgdb
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultQueryTimeout is the default deadline for Query calls.
+	DefaultQueryTimeout = 30 * time.Second
+	// DefaultUpsertTimeout is the default deadline for each Upsert request.
+	DefaultUpsertTimeout = 2 * time.Minute
+	// DefaultTrainTimeout is the default deadline for Train calls.
+	DefaultTrainTimeout = time.Hour
+	// DefaultOperationTimeout is the default deadline for all other calls
+	// (health, list, describe, get, delete).
+	DefaultOperationTimeout = time.Minute
+)
+
+// ClientOption configures a Client created with NewClientWithOptions.
+type ClientOption func(*clientOptions)
+
+// clientOptions holds the settings applied by ClientOption values.
+type clientOptions struct {
+	verifySSL *bool
+
+	queryTimeout     time.Duration
+	upsertTimeout    time.Duration
+	trainTimeout     time.Duration
+	operationTimeout time.Duration
+
+	transport transportOptions
+
+	vectorEncoding VectorEncoding
+	compression    Compression
+
+	rateLimit float64
+	rateBurst int
+
+	circuitBreaker *CircuitBreakerConfig
+
+	credentials CredentialsProvider
+
+	// queryCache caches query responses, nil if disabled
+	queryCache *queryCache
+
+	// catalogKey protects the index catalog, nil if disabled
+	catalogKey []byte
+
+	// auditSink receives a record for every mutating call, nil if disabled
+	auditSink AuditSink
+
+	// metadataLimits validates upserted metadata, nil if disabled
+	metadataLimits *MetadataLimits
+
+	// numberDecoding selects the type of numbers in returned metadata
+	numberDecoding NumberDecoding
+
+	// strictInclude rejects unknown include fields
+	strictInclude bool
+
+	// slowOpThreshold reports slower requests to slowOpHandler, 0 if disabled
+	slowOpThreshold time.Duration
+	slowOpHandler   func(SlowOp)
+
+	// payloadLimits bounds request sizes, nil if disabled
+	payloadLimits *payloadLimits
+
+	// upsertRetries is the number of retries of a failed upsert request
+	upsertRetries int
+
+	// schemaValidation loads index schemas to validate upserts
+	schemaValidation bool
+
+	// timestamps stamps created_at/updated_at on upsert, nil if disabled
+	timestamps *TimestampOptions
+
+	// encryptedFields names the metadata fields encrypted client-side
+	encryptedFields map[string]bool
+
+	// hashedFields names the metadata fields stored as searchable hashes
+	hashedFields map[string]bool
+
+	// err records the first invalid option, reported by NewClientWithOptions
+	err error
+}
+
+// setErr records err unless an earlier option already failed.
+func (o *clientOptions) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// defaultClientOptions returns the option set used when none are given.
+func defaultClientOptions() *clientOptions {
+	return &clientOptions{
+		queryTimeout:     DefaultQueryTimeout,
+		upsertTimeout:    DefaultUpsertTimeout,
+		trainTimeout:     DefaultTrainTimeout,
+		operationTimeout: DefaultOperationTimeout,
+		transport:        defaultTransportOptions(),
+	}
+}
+
+// WithVerifySSL forces TLS certificate verification on or off, overriding
+// the auto-detection described on NewClient.
+func WithVerifySSL(verify bool) ClientOption {
+	return func(o *clientOptions) { o.verifySSL = &verify }
+}
+
+// WithQueryTimeout sets the deadline applied to Query calls whose context has
+// none. Zero or negative disables the default deadline.
+func WithQueryTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.queryTimeout = d }
+}
+
+// WithUpsertTimeout sets the deadline applied to each Upsert request whose
+// context has none. Zero or negative disables the default deadline.
+func WithUpsertTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.upsertTimeout = d }
+}
+
+// WithTrainTimeout sets the deadline applied to Train calls whose context has
+// none. Zero or negative disables the default deadline.
+func WithTrainTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.trainTimeout = d }
+}
+
+// WithOperationTimeout sets the deadline applied to all other calls whose
+// context has none. Zero or negative disables the default deadline.
+func WithOperationTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.operationTimeout = d }
+}
+
+// VectorEncoding selects how vectors are encoded in upsert requests.
+type VectorEncoding int
+
+const (
+	// VectorEncodingJSON sends vectors as JSON number arrays. This is the default.
+	VectorEncodingJSON VectorEncoding = iota
+	// VectorEncodingPacked sends each vector as base64 of its little-endian
+	// float32 bytes, which is several times smaller and faster to encode. If
+	// the server does not accept it, the client falls back to JSON and stops
+	// attempting packed requests.
+	VectorEncodingPacked
+)
+
+// WithVectorEncoding sets the wire encoding used for vectors in upserts.
+func WithVectorEncoding(enc VectorEncoding) ClientOption {
+	return func(o *clientOptions) { o.vectorEncoding = enc }
+}
+
+// withDefaultTimeout derives a context with timeout d, unless ctx already has
+// a deadline or d is not positive. The caller must always call cancel.
+func withDefaultTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,177 @@
+// paginate.go implements paging through query results with an offset or a
+// continuation token.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPageToken is returned when a page token is malformed or was
+// issued for a different query.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// QueryResultPage is one page of results returned by QueryPage.
+type QueryResultPage struct {
+	// Results are the page's results, in query order.
+	Results []QueryResult `json:"results"`
+	// NextPageToken fetches the following page; empty on the last page.
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// pageToken is the decoded form of a continuation token.
+type pageToken struct {
+	Offset int32  `json:"o"`
+	Query  []byte `json:"q"`
+}
+
+// QueryPage returns one page of TopK (WithTopK) results for vector, starting
+// at pageToken, which is empty for the first page.
+//
+// Pages are cut client-side (see QueryParams.Offset), so page n costs a
+// query for n*TopK results. Pages keep the order of the full result list,
+// including re-ranking or MMR order, and neither overlap nor skip results
+// as long as the service orders ties consistently, the index is not
+// modified or retrained between calls, and the same options are passed; a
+// write in between can shift results across page boundaries.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vector: The query vector
+//   - pageToken: "" for the first page, then the previous NextPageToken
+//   - opts: Optional settings such as WithTopK (the page size) and WithFilters
+//
+// Returns:
+//   - *QueryResultPage: The page and the token of the next one
+//   - error: ErrInvalidPageToken, or any query error
+//
+// Example:
+//
+//	token := ""
+//	for {
+//		page, err := index.QueryPage(ctx, vec, token, cyborgdb.WithTopK(20))
+//		if err != nil {
+//			return err
+//		}
+//		render(page.Results)
+//		if token = page.NextPageToken; token == "" {
+//			break
+//		}
+//	}
+func (e *EncryptedIndex) QueryPage(ctx context.Context, vector []float32, pageToken string, opts ...QueryOption) (*QueryResultPage, error) {
+	if len(vector) == 0 {
+		return nil, ErrEmptyQueryVector
+	}
+	params := QueryParams{QueryVector: vector}
+	for _, opt := range opts {
+		opt(&params)
+	}
+	if params.TopK <= 0 {
+		params.TopK = defaultQueryTopK
+	}
+	fingerprint, err := queryFingerprint(params)
+	if err != nil {
+		return nil, err
+	}
+	if pageToken != "" {
+		if params.Offset, err = decodePageToken(pageToken, fingerprint); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := e.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	page := &QueryResultPage{Results: []QueryResult{}}
+	if results := flattenQueryResults(resp, e.GetMetric()); len(results) > 0 {
+		page.Results = results[0]
+	}
+	if int32(len(page.Results)) == params.TopK {
+		page.NextPageToken = encodePageToken(params.Offset+params.TopK, fingerprint)
+	}
+	return page, nil
+}
+
+// queryOffset runs params with Offset by fetching Offset+TopK results and
+// dropping the first Offset of each result list.
+func (e *EncryptedIndex) queryOffset(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	topK := params.TopK
+	if topK <= 0 {
+		topK = defaultQueryTopK
+	}
+	offset := params.Offset
+	fetch := params
+	fetch.Offset = 0
+	fetch.TopK = offset + topK
+
+	resp, err := e.query(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	// Slice in the order returned: re-ranking and MMR order results by more
+	// than distance.
+	page := func(items []QueryResultItem) []QueryResultItem {
+		if int32(len(items)) <= offset {
+			return []QueryResultItem{}
+		}
+		items = items[offset:]
+		if int32(len(items)) > topK {
+			items = items[:topK]
+		}
+		return items
+	}
+	if r := resp.Results.ArrayOfQueryResultItem; r != nil {
+		*r = page(*r)
+	}
+	if r := resp.Results.ArrayOfArrayOfQueryResultItem; r != nil {
+		for i := range *r {
+			(*r)[i] = page((*r)[i])
+		}
+	}
+	return resp, nil
+}
+
+// queryFingerprint identifies the query a page token belongs to. The page
+// size and offset are excluded, so the size may change between pages.
+func queryFingerprint(params QueryParams) ([]byte, error) {
+	params.TopK, params.Offset = 0, 0
+	data, err := json.Marshal(struct {
+		Params    QueryParams `json:"params"`
+		Rerank    bool        `json:"rerank"`
+		MMRLambda *float64    `json:"mmr"`
+		MinScore  *float32    `json:"min_score"`
+	}{params, params.RerankExact, params.MMRLambda, params.MinScore})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:8], nil
+}
+
+// encodePageToken returns the token for the page starting at offset.
+func encodePageToken(offset int32, fingerprint []byte) string {
+	data, _ := json.Marshal(pageToken{Offset: offset, Query: fingerprint})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken returns the offset in token after checking it belongs to
+// the query with fingerprint.
+func decodePageToken(token string, fingerprint []byte) (int32, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	var t pageToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	if t.Offset < 0 || string(t.Query) != string(fingerprint) {
+		return 0, fmt.Errorf("%w: issued for a different query", ErrInvalidPageToken)
+	}
+	return t.Offset, nil
+}
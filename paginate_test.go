@@ -0,0 +1,46 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestQueryPageKeepsMMROrder(t *testing.T) {
+	_, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	// a1-a3 are near-duplicates close to the query; b is farther but diverse.
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a1", Vector: []float32{1, 0}},
+		{Id: "a2", Vector: []float32{0.99, 0.01}},
+		{Id: "a3", Vector: []float32{0.98, 0.02}},
+		{Id: "b", Vector: []float32{0.5, 0.5}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	query := []float32{1, 0}
+	opts := []cyborgdb.QueryOption{cyborgdb.WithMMR(0.3), cyborgdb.WithInclude("vector")}
+
+	full, err := index.QueryOne(ctx, query, append(opts, cyborgdb.WithTopK(4))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full) != 4 || full[1].ID != "b" {
+		t.Fatalf("MMR order = %v, want b second", resultIDs(full))
+	}
+
+	first, err := index.QueryPage(ctx, query, "", append(opts, cyborgdb.WithTopK(2))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := index.QueryPage(ctx, query, first.NextPageToken, append(opts, cyborgdb.WithTopK(2))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resultIDs(second.Results), resultIDs(full[2:]); !reflect.DeepEqual(got, want) {
+		t.Errorf("second page = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,430 @@
+// parquet.go implements the built-in Parquet RecordWriter used by Export.
+//
+// The writer emits uncompressed, PLAIN-encoded files with this schema:
+//
+//	message record {
+//	  required binary id (UTF8);
+//	  repeated float vector;
+//	  optional binary metadata (JSON);
+//	  optional binary contents (UTF8);
+//	}
+//
+// Records are buffered into row groups of parquetRowGroupSize rows, so memory
+// use is bounded by the row group rather than the index. The file footer is
+// written by Flush, after which the writer accepts no more records.
+package cyborgdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// parquetRowGroupSize is the number of records per Parquet row group.
+const parquetRowGroupSize = 10000
+
+// parquetMagic starts and ends every Parquet file.
+const parquetMagic = "PAR1"
+
+// Parquet enum values from parquet.thrift.
+const (
+	parquetFloat     = 4
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+	parquetRepeated = 2
+
+	parquetUTF8 = 0
+	parquetJSON = 19
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetDataPage = 0
+)
+
+// errParquetClosed is returned by WriteRecord after Flush.
+var errParquetClosed = errors.New("parquet file already finished by Flush")
+
+// parquetColumn accumulates one column of the current row group.
+type parquetColumn struct {
+	name         string
+	physical     int32
+	repetition   int32
+	converted    int32 // -1 if none
+	values       bytes.Buffer
+	repLevels    []byte
+	defLevels    []byte
+	numValues    int // level entries, including nulls
+	hasRepLevels bool
+	hasDefLevels bool
+}
+
+type parquetWriter struct {
+	w         *countingWriter
+	columns   []*parquetColumn
+	rows      int
+	totalRows int64
+	rowGroups [][]byte
+	started   bool
+	finished  bool
+}
+
+func newParquetWriter(w io.Writer) RecordWriter {
+	return &parquetWriter{
+		w: &countingWriter{w: w},
+		columns: []*parquetColumn{
+			{name: "id", physical: parquetByteArray, repetition: parquetRequired, converted: parquetUTF8},
+			{name: "vector", physical: parquetFloat, repetition: parquetRepeated, converted: -1, hasRepLevels: true, hasDefLevels: true},
+			{name: "metadata", physical: parquetByteArray, repetition: parquetOptional, converted: parquetJSON, hasDefLevels: true},
+			{name: "contents", physical: parquetByteArray, repetition: parquetOptional, converted: parquetUTF8, hasDefLevels: true},
+		},
+	}
+}
+
+func (p *parquetWriter) WriteRecord(rec Record) error {
+	if p.finished {
+		return errParquetClosed
+	}
+	id, vector, metadata, contents := p.columns[0], p.columns[1], p.columns[2], p.columns[3]
+
+	id.appendBytes([]byte(rec.ID))
+	id.numValues++
+
+	if len(rec.Vector) == 0 {
+		vector.repLevels = append(vector.repLevels, 0)
+		vector.defLevels = append(vector.defLevels, 0)
+		vector.numValues++
+	}
+	for i, v := range rec.Vector {
+		rep := byte(1)
+		if i == 0 {
+			rep = 0
+		}
+		vector.repLevels = append(vector.repLevels, rep)
+		vector.defLevels = append(vector.defLevels, 1)
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+		vector.values.Write(b[:])
+		vector.numValues++
+	}
+
+	if rec.Metadata != nil {
+		data, err := json.Marshal(rec.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata of %q: %w", rec.ID, err)
+		}
+		metadata.appendOptional(data)
+	} else {
+		metadata.appendOptional(nil)
+	}
+	if rec.Contents != nil {
+		contents.appendOptional([]byte(*rec.Contents))
+	} else {
+		contents.appendOptional(nil)
+	}
+
+	p.rows++
+	if p.rows >= parquetRowGroupSize {
+		return p.writeRowGroup()
+	}
+	return nil
+}
+
+// Flush writes the buffered row group and the file footer.
+func (p *parquetWriter) Flush() error {
+	if p.finished {
+		return nil
+	}
+	if err := p.writeRowGroup(); err != nil {
+		return err
+	}
+	if err := p.start(); err != nil {
+		return err
+	}
+	p.finished = true
+
+	footer := p.fileMetadata()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	for _, b := range [][]byte{footer, length[:], []byte(parquetMagic)} {
+		if _, err := p.w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// start writes the leading magic bytes once.
+func (p *parquetWriter) start() error {
+	if p.started {
+		return nil
+	}
+	p.started = true
+	_, err := p.w.Write([]byte(parquetMagic))
+	return err
+}
+
+// writeRowGroup writes one data page per column for the buffered rows.
+func (p *parquetWriter) writeRowGroup() error {
+	if p.rows == 0 {
+		return nil
+	}
+	if err := p.start(); err != nil {
+		return err
+	}
+	var chunks [][]byte
+	var groupSize int64
+	for _, col := range p.columns {
+		offset := p.w.n
+		page := col.page()
+		header := parquetPageHeader(len(page), col.numValues)
+		if _, err := p.w.Write(header); err != nil {
+			return err
+		}
+		if _, err := p.w.Write(page); err != nil {
+			return err
+		}
+		size := int64(len(header) + len(page))
+		groupSize += size
+		chunks = append(chunks, col.columnChunk(offset, size))
+		col.reset()
+	}
+
+	var rg thriftWriter
+	rg.listField(1, thriftStruct, len(chunks))
+	for _, c := range chunks {
+		rg.buf.Write(c)
+	}
+	rg.i64Field(2, groupSize)
+	rg.i64Field(3, int64(p.rows))
+	rg.stop()
+	p.rowGroups = append(p.rowGroups, rg.buf.Bytes())
+	p.totalRows += int64(p.rows)
+	p.rows = 0
+	return nil
+}
+
+// fileMetadata encodes the FileMetaData footer.
+func (p *parquetWriter) fileMetadata() []byte {
+	var t thriftWriter
+	t.i32Field(1, 1)
+	t.listField(2, thriftStruct, len(p.columns)+1)
+	var root thriftWriter
+	root.stringField(4, "schema")
+	root.i32Field(5, int32(len(p.columns)))
+	root.stop()
+	t.buf.Write(root.buf.Bytes())
+	for _, col := range p.columns {
+		var s thriftWriter
+		s.i32Field(1, col.physical)
+		s.i32Field(3, col.repetition)
+		s.stringField(4, col.name)
+		if col.converted >= 0 {
+			s.i32Field(6, col.converted)
+		}
+		s.stop()
+		t.buf.Write(s.buf.Bytes())
+	}
+	t.i64Field(3, p.totalRows)
+	t.listField(4, thriftStruct, len(p.rowGroups))
+	for _, rg := range p.rowGroups {
+		t.buf.Write(rg)
+	}
+	t.stringField(6, "cyborgdb-go")
+	t.stop()
+	return t.buf.Bytes()
+}
+
+func (c *parquetColumn) appendBytes(data []byte) {
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(data)))
+	c.values.Write(n[:])
+	c.values.Write(data)
+}
+
+// appendOptional appends data, or a null if data is nil.
+func (c *parquetColumn) appendOptional(data []byte) {
+	if data == nil {
+		c.defLevels = append(c.defLevels, 0)
+	} else {
+		c.defLevels = append(c.defLevels, 1)
+		c.appendBytes(data)
+	}
+	c.numValues++
+}
+
+// page returns the data page body: levels followed by the values.
+func (c *parquetColumn) page() []byte {
+	var page bytes.Buffer
+	if c.hasRepLevels {
+		writeLevels(&page, c.repLevels)
+	}
+	if c.hasDefLevels {
+		writeLevels(&page, c.defLevels)
+	}
+	page.Write(c.values.Bytes())
+	return page.Bytes()
+}
+
+// columnChunk encodes the ColumnChunk for a page written at offset.
+func (c *parquetColumn) columnChunk(offset, size int64) []byte {
+	var meta thriftWriter
+	meta.i32Field(1, c.physical)
+	if c.hasRepLevels || c.hasDefLevels {
+		meta.listField(2, thriftI32, 2)
+		meta.varint(zigzag(parquetPlain))
+		meta.varint(zigzag(parquetRLE))
+	} else {
+		meta.listField(2, thriftI32, 1)
+		meta.varint(zigzag(parquetPlain))
+	}
+	meta.listField(3, thriftBinary, 1)
+	meta.binary([]byte(c.name))
+	meta.i32Field(4, 0) // UNCOMPRESSED
+	meta.i64Field(5, int64(c.numValues))
+	meta.i64Field(6, size)
+	meta.i64Field(7, size)
+	meta.i64Field(9, offset)
+	meta.stop()
+
+	var chunk thriftWriter
+	chunk.i64Field(2, offset)
+	chunk.structField(3, meta.buf.Bytes())
+	chunk.stop()
+	return chunk.buf.Bytes()
+}
+
+func (c *parquetColumn) reset() {
+	c.values.Reset()
+	c.repLevels = c.repLevels[:0]
+	c.defLevels = c.defLevels[:0]
+	c.numValues = 0
+}
+
+// writeLevels writes 0/1 levels as a length-prefixed, bit-packed run of the
+// RLE/bit-packing hybrid encoding with a bit width of 1.
+func writeLevels(w *bytes.Buffer, levels []byte) {
+	groups := (len(levels) + 7) / 8
+	var run bytes.Buffer
+	var header [binary.MaxVarintLen64]byte
+	run.Write(header[:binary.PutUvarint(header[:], uint64(groups)<<1|1)])
+	for g := 0; g < groups; g++ {
+		var b byte
+		for i := 0; i < 8 && g*8+i < len(levels); i++ {
+			b |= levels[g*8+i] << i
+		}
+		run.WriteByte(b)
+	}
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(run.Len()))
+	w.Write(n[:])
+	w.Write(run.Bytes())
+}
+
+// parquetPageHeader encodes the PageHeader of an uncompressed PLAIN data page.
+func parquetPageHeader(size, numValues int) []byte {
+	var dph thriftWriter
+	dph.i32Field(1, int32(numValues))
+	dph.i32Field(2, parquetPlain)
+	dph.i32Field(3, parquetRLE)
+	dph.i32Field(4, parquetRLE)
+	dph.stop()
+
+	var t thriftWriter
+	t.i32Field(1, parquetDataPage)
+	t.i32Field(2, int32(size))
+	t.i32Field(3, int32(size))
+	t.structField(5, dph.buf.Bytes())
+	t.stop()
+	return t.buf.Bytes()
+}
+
+// Thrift compact protocol type IDs.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes a single struct in the Thrift compact protocol.
+// Nested structs are encoded with their own thriftWriter.
+type thriftWriter struct {
+	buf     bytes.Buffer
+	lastID  int16
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.lastID; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(zigzag(int64(id)))
+	}
+	t.lastID = id
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	t.buf.Write(t.scratch[:binary.PutUvarint(t.scratch[:], v)])
+}
+
+func (t *thriftWriter) binary(b []byte) {
+	t.varint(uint64(len(b)))
+	t.buf.Write(b)
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) stringField(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary([]byte(s))
+}
+
+// structField writes an already encoded struct, including its stop byte.
+func (t *thriftWriter) structField(id int16, encoded []byte) {
+	t.fieldHeader(id, thriftStruct)
+	t.buf.Write(encoded)
+}
+
+// listField writes a list header; the caller writes the n elements.
+func (t *thriftWriter) listField(id int16, elemType byte, n int) {
+	t.fieldHeader(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xF0 | elemType)
+		t.varint(uint64(n))
+	}
+}
+
+func (t *thriftWriter) stop() { t.buf.WriteByte(0) }
+
+func zigzag(v int64) uint64 { return uint64(v<<1) ^ uint64(v>>63) }
+
+// countingWriter tracks the offset of the next byte written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,63 @@
+package cyborgdb_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestExportParquet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/indexes/describe":
+			w.Write([]byte(`{"index_name":"docs","index_type":"ivfflat","is_trained":false,"index_config":{"type":"ivfflat","dimension":2}}`))
+		case "/v1/vectors/list_ids":
+			w.Write([]byte(`{"ids":["a","b"],"count":2}`))
+		case "/v1/vectors/get":
+			w.Write([]byte(`{"results":[` +
+				`{"id":"a","vector":[1,0],"metadata":{"lang":"en"}},` +
+				`{"id":"b","vector":[0,1],"contents":"hello"}]}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := cyborgdb.NewClient(srv.URL, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	index, err := client.LoadIndex(ctx, "docs", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	n, err := index.Export(ctx, &out, cyborgdb.FormatParquet, []string{"vector", "metadata", "contents"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("exported %d records, want 2", n)
+	}
+	data := out.Bytes()
+	if len(data) < 12 || string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatalf("output is not framed as a Parquet file: % x", data)
+	}
+	footer := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if footer <= 0 || footer > len(data)-12 {
+		t.Errorf("footer length %d does not fit a %d-byte file", footer, len(data))
+	}
+	for _, want := range []string{"id", "vector", "metadata", "contents", `{"lang":"en"}`, "hello"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("file does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// passphrase.go implements passphrase-based index key derivation with Argon2id.
+package cyborgdb
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// SaltSize is the recommended salt length in bytes for DeriveKeyFromPassphrase.
+	SaltSize = 16
+	// minSaltSize is the shortest salt accepted by DeriveKeyFromPassphrase.
+	minSaltSize = 8
+)
+
+var (
+	// ErrEmptyPassphrase is returned when the passphrase is empty.
+	ErrEmptyPassphrase = fmt.Errorf("passphrase must not be empty")
+	// ErrSaltTooShort is returned when the salt is shorter than 8 bytes.
+	ErrSaltTooShort = fmt.Errorf("salt must be at least %d bytes", minSaltSize)
+	// ErrInvalidKDFParams is returned when Argon2id parameters are out of range.
+	ErrInvalidKDFParams = fmt.Errorf("invalid key derivation parameters")
+)
+
+// KDFParams configures Argon2id key derivation.
+//
+// The zero value is not valid; start from DefaultKDFParams() and adjust.
+// The same params and salt must be used every time a key is re-derived.
+type KDFParams struct {
+	// Time is the number of passes over memory.
+	Time uint32 `json:"time"`
+
+	// Memory is the amount of memory used in KiB.
+	Memory uint32 `json:"memory"`
+
+	// Threads is the degree of parallelism.
+	Threads uint8 `json:"threads"`
+}
+
+// DefaultKDFParams returns the RFC 9106 second recommended Argon2id settings:
+// 3 passes, 64 MiB of memory, and 4 lanes.
+func DefaultKDFParams() KDFParams {
+	return KDFParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+// GenerateSalt returns a random salt of SaltSize bytes for DeriveKeyFromPassphrase.
+//
+// The salt is not secret but must be stored alongside the index name so the
+// key can be re-derived later.
+func GenerateSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyGeneration, err)
+	}
+	return salt, nil
+}
+
+// DeriveKeyFromPassphrase derives a stable 32-byte index key from a
+// passphrase using Argon2id.
+//
+// The same passphrase, salt, and params always produce the same key, so small
+// deployments and CLI users can re-derive the key instead of managing raw key
+// files.
+//
+// Parameters:
+//   - passphrase: The secret phrase (must not be empty)
+//   - salt: A random per-index salt of at least 8 bytes (see GenerateSalt)
+//   - params: Argon2id cost settings (see DefaultKDFParams)
+//
+// Returns:
+//   - []byte: A 32-byte encryption key
+//   - error: Any validation error
+//
+// Example:
+//
+//	salt, _ := cyborgdb.GenerateSalt()
+//	key, err := cyborgdb.DeriveKeyFromPassphrase("correct horse battery staple", salt, cyborgdb.DefaultKDFParams())
+func DeriveKeyFromPassphrase(passphrase string, salt []byte, params KDFParams) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	if len(salt) < minSaltSize {
+		return nil, fmt.Errorf("%w, got %d", ErrSaltTooShort, len(salt))
+	}
+	if params.Time == 0 || params.Threads == 0 || params.Memory < 8*uint32(params.Threads) {
+		return nil, fmt.Errorf("%w: %+v", ErrInvalidKDFParams, params)
+	}
+	return argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, KeySize), nil
+}
@@ -0,0 +1,174 @@
+// payload.go enforces request size limits client-side and reports the
+// payload size when the server rejects a request as too large.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+// ErrPayloadTooLarge is wrapped by every PayloadTooLargeError.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// PayloadTooLargeError reports a request over a size limit, either caught
+// client-side or rejected by the server with 413 Request Entity Too Large.
+type PayloadTooLargeError struct {
+	// Operation is the endpoint, e.g. "vectors/upsert".
+	Operation string
+	// Items and MaxItems are the item count and limit, 0 if the count was
+	// not the problem.
+	Items, MaxItems int
+	// Bytes is the request body size, -1 if unknown; MaxBytes is the limit,
+	// 0 if unknown.
+	Bytes, MaxBytes int64
+	// Server is true if the server rejected the request.
+	Server bool
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v: %s", ErrPayloadTooLarge, e.Operation)
+	if e.MaxItems > 0 {
+		fmt.Fprintf(&b, ": %d items exceeds limit of %d", e.Items, e.MaxItems)
+	} else {
+		fmt.Fprintf(&b, ": %d bytes", e.Bytes)
+		if e.MaxBytes > 0 && e.Bytes > e.MaxBytes {
+			fmt.Fprintf(&b, " exceeds limit of %d", e.MaxBytes)
+		}
+	}
+	if e.Server {
+		b.WriteString(" (rejected by server)")
+	}
+	return b.String()
+}
+
+func (e *PayloadTooLargeError) Unwrap() error {
+	return ErrPayloadTooLarge
+}
+
+// PayloadLimits bounds the requests a client sends. Zero fields are not
+// enforced.
+type PayloadLimits struct {
+	// MaxBatchItems limits the items per upsert and the query vectors per
+	// batch query.
+	MaxBatchItems int
+	// MaxBodyBytes limits the size of a request body as sent, after any
+	// compression.
+	MaxBodyBytes int64
+	// FromServer fills zero fields from the limits the server reports (see
+	// Capabilities), fetched once before the first upsert or query.
+	FromServer bool
+}
+
+// WithPayloadLimits rejects upserts and queries over limits with a
+// *PayloadTooLargeError before they are sent, instead of after the server
+// reads the whole body.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithPayloadLimits(cyborgdb.PayloadLimits{FromServer: true}))
+func WithPayloadLimits(limits PayloadLimits) ClientOption {
+	return func(o *clientOptions) { o.payloadLimits = &payloadLimits{limits: limits} }
+}
+
+// payloadLimits holds the configured limits, completed from the server on
+// first use when FromServer is set.
+type payloadLimits struct {
+	mu       sync.Mutex
+	limits   PayloadLimits
+	resolved bool
+}
+
+// get returns the effective limits, fetching the server's limits first if
+// needed. A failed fetch leaves the configured limits in place and is
+// retried on the next call.
+func (p *payloadLimits) get(ctx context.Context, client *internal.Client) PayloadLimits {
+	if p == nil {
+		return PayloadLimits{}
+	}
+	p.mu.Lock()
+	limits, fetch := p.limits, p.limits.FromServer && !p.resolved
+	p.mu.Unlock()
+	if !fetch {
+		return limits
+	}
+
+	// The health request passes through payloadTransport, so the lock must
+	// not be held while it runs.
+	raw, err := client.GetHealth(ctx)
+	if err != nil {
+		return limits
+	}
+	caps := newCapabilities(newHealthResponse(raw))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.resolved {
+		if p.limits.MaxBatchItems == 0 {
+			p.limits.MaxBatchItems = caps.MaxBatchSize
+		}
+		if p.limits.MaxBodyBytes == 0 {
+			p.limits.MaxBodyBytes = caps.MaxBodyBytes
+		}
+		p.resolved = true
+	}
+	return p.limits
+}
+
+// maxBodyBytes returns the body limit known so far, without fetching.
+func (p *payloadLimits) maxBodyBytes() int64 {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.limits.MaxBodyBytes
+}
+
+// checkItems returns a *PayloadTooLargeError if n items exceed the limit for
+// operation.
+func (o *clientOptions) checkItems(ctx context.Context, client *internal.Client, operation string, n int) error {
+	if o == nil || o.payloadLimits == nil {
+		return nil
+	}
+	limits := o.payloadLimits.get(ctx, client)
+	if limits.MaxBatchItems > 0 && n > limits.MaxBatchItems {
+		return &PayloadTooLargeError{Operation: operation, Items: n, MaxItems: limits.MaxBatchItems, Bytes: -1}
+	}
+	return nil
+}
+
+// payloadTransport rejects request bodies over the configured limit and
+// turns 413 responses into a *PayloadTooLargeError carrying the body size.
+type payloadTransport struct {
+	next   http.RoundTripper
+	limits *payloadLimits
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *payloadTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := strings.TrimPrefix(req.URL.Path, "/v1/")
+	maxBytes := t.limits.maxBodyBytes()
+	if maxBytes > 0 && req.ContentLength > maxBytes {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &PayloadTooLargeError{Operation: operation, Bytes: req.ContentLength, MaxBytes: maxBytes}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusRequestEntityTooLarge {
+		return resp, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil, &PayloadTooLargeError{Operation: operation, Bytes: req.ContentLength, MaxBytes: maxBytes, Server: true}
+}
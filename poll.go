@@ -0,0 +1,124 @@
+// poll.go implements Poll, the context-aware wait loop with exponential
+// backoff shared by WaitUntilHealthy, WaitForVisibility, and user code.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultPollInitialInterval is the first delay between attempts.
+	DefaultPollInitialInterval = 100 * time.Millisecond
+	// DefaultPollMaxInterval caps the delay between attempts.
+	DefaultPollMaxInterval = 5 * time.Second
+	// DefaultPollMultiplier grows the delay after each attempt.
+	DefaultPollMultiplier = 2.0
+)
+
+// PollOptions controls the delays of Poll. Zero fields use the defaults.
+type PollOptions struct {
+	// InitialInterval is the delay after the first attempt.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay. Set it equal to InitialInterval for a
+	// fixed interval.
+	MaxInterval time.Duration
+
+	// Multiplier scales the delay after each attempt; values below 1 are
+	// treated as the default.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction of it, e.g. 0.2
+	// for ±20%, so many pollers do not wake in lockstep. Default: none.
+	Jitter float64
+
+	// Timeout bounds the whole wait when ctx has no deadline. Default: ctx
+	// alone bounds it.
+	Timeout time.Duration
+}
+
+// PollFunc is one attempt of a Poll loop. It returns done=true to stop
+// polling, with err as Poll's result. With done=false, a non-nil err is
+// treated as transient: it is remembered and reported if the wait ends.
+type PollFunc func(ctx context.Context) (done bool, err error)
+
+// Poll calls fn until it reports done or the wait ends, sleeping with
+// exponential backoff between attempts.
+//
+// Parameters:
+//   - ctx: Context bounding the total wait
+//   - fn: The attempt; see PollFunc
+//   - opts: Delays and timeout
+//
+// Returns:
+//   - error: fn's error once done, otherwise the context error wrapped with
+//     the last transient error
+//
+// Example:
+//
+//	err := cyborgdb.Poll(ctx, func(ctx context.Context) (bool, error) {
+//		training, err := index.CheckTrainingStatus(ctx)
+//		return err == nil && !training, err
+//	}, cyborgdb.PollOptions{InitialInterval: time.Second, MaxInterval: 30 * time.Second})
+func Poll(ctx context.Context, fn PollFunc, opts PollOptions) error {
+	opts = opts.withDefaults()
+	ctx, cancel := withDefaultTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	interval := opts.InitialInterval
+	var lastErr error
+	for {
+		done, err := fn(ctx)
+		if done {
+			return err
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		timer := time.NewTimer(opts.jitter(interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if lastErr != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// withDefaults fills in zero fields.
+func (o PollOptions) withDefaults() PollOptions {
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = DefaultPollInitialInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = DefaultPollMaxInterval
+	}
+	if o.MaxInterval < o.InitialInterval {
+		o.MaxInterval = o.InitialInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = DefaultPollMultiplier
+	}
+	return o
+}
+
+// jitter randomizes d by up to ±Jitter of it.
+func (o PollOptions) jitter(d time.Duration) time.Duration {
+	if o.Jitter <= 0 {
+		return d
+	}
+	delta := float64(d) * o.Jitter * (2*rand.Float64() - 1)
+	return d + time.Duration(delta)
+}
@@ -0,0 +1,18 @@
+// ptr.go provides helpers for setting the optional (pointer) fields of
+// request parameters inline.
+package cyborgdb
+
+// Int32 returns a pointer to v, for optional fields such as
+// TrainParams.BatchSize or QueryParams.NProbes.
+func Int32(v int32) *int32 { return &v }
+
+// Bool returns a pointer to v, for optional fields such as QueryParams.Greedy.
+func Bool(v bool) *bool { return &v }
+
+// String returns a pointer to v, for optional fields such as
+// CreateIndexParams.Metric.
+func String(v string) *string { return &v }
+
+// Float64 returns a pointer to v, for optional fields such as
+// TrainParams.Tolerance.
+func Float64(v float64) *float64 { return &v }
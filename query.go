@@ -0,0 +1,277 @@
+// query.go provides typed single-call query helpers on EncryptedIndex.
+// QueryOne and QueryBatch wrap Query and flatten the nested response union
+// into plain Go slices of QueryResult, and QueryMany fans large query sets
+// out over concurrent batch requests.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+// ErrEmptyQueryVector is returned when QueryOne or QueryBatch receives no vector data.
+var ErrEmptyQueryVector = fmt.Errorf("query vector must not be empty")
+
+// ErrUnexpectedResultShape is returned by QueryResponse.Single and
+// QueryResponse.At when the response does not hold the requested results.
+var ErrUnexpectedResultShape = internal.ErrUnexpectedResultShape
+
+// QueryResult is a single flattened similarity search result.
+type QueryResult struct {
+	// ID is the identifier of the matched vector.
+	ID string `json:"id"`
+
+	// Distance is the distance between the query and the matched vector.
+	// Lower values indicate closer matches for distance metrics.
+	Distance float32 `json:"distance"`
+
+	// Score is the distance normalized to a similarity in [0, 1] for the
+	// index metric, where higher is closer. See Similarity.
+	Score float32 `json:"score"`
+
+	// Metadata holds the vector's metadata when "metadata" was included.
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Vector holds the stored vector when "vector" was included.
+	Vector []float32 `json:"vector,omitempty"`
+}
+
+// QueryOption customizes a QueryOne or QueryBatch call.
+type QueryOption func(*QueryParams)
+
+// WithTopK sets the number of nearest neighbors to return.
+func WithTopK(topK int32) QueryOption {
+	return func(p *QueryParams) { p.TopK = topK }
+}
+
+// WithNProbes sets the number of IVF lists probed during search.
+func WithNProbes(nProbes int32) QueryOption {
+	return func(p *QueryParams) { p.NProbes = &nProbes }
+}
+
+// WithGreedy enables or disables greedy search.
+func WithGreedy(greedy bool) QueryOption {
+	return func(p *QueryParams) { p.Greedy = &greedy }
+}
+
+// WithFilters sets the metadata filters applied to the search.
+func WithFilters(filters map[string]interface{}) QueryOption {
+	return func(p *QueryParams) { p.Filters = filters }
+}
+
+// WithInclude sets which fields are returned with each result.
+func WithInclude(include ...string) QueryOption {
+	return func(p *QueryParams) { p.Include = include }
+}
+
+// QueryOne performs a similarity search for a single query vector.
+//
+// Unlike Query, the result is returned as a flat slice rather than the
+// nested response union.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vector: The query vector
+//   - opts: Optional settings such as WithTopK, WithFilters, WithInclude
+//
+// Returns:
+//   - []QueryResult: Results ordered by similarity (closest first)
+//   - error: Any error encountered during the search
+//
+// Example:
+//
+//	results, err := index.QueryOne(ctx, vec, cyborgdb.WithTopK(5), cyborgdb.WithInclude("metadata"))
+func (e *EncryptedIndex) QueryOne(ctx context.Context, vector []float32, opts ...QueryOption) ([]QueryResult, error) {
+	if len(vector) == 0 {
+		return nil, ErrEmptyQueryVector
+	}
+
+	params := QueryParams{QueryVector: vector}
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	resp, err := e.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	results := flattenQueryResults(resp, e.GetMetric())
+	if len(results) == 0 {
+		return []QueryResult{}, nil
+	}
+	return results[0], nil
+}
+
+// QueryBatch performs a similarity search for several query vectors in one request.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vectors: The query vectors
+//   - opts: Optional settings such as WithTopK, WithFilters, WithInclude
+//
+// Returns:
+//   - [][]QueryResult: One result slice per query vector, in input order
+//   - error: Any error encountered during the search
+func (e *EncryptedIndex) QueryBatch(ctx context.Context, vectors [][]float32, opts ...QueryOption) ([][]QueryResult, error) {
+	if len(vectors) == 0 {
+		return nil, ErrEmptyQueryVector
+	}
+
+	params := QueryParams{BatchQueryVectors: vectors}
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	resp, err := e.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenQueryResults(resp, e.GetMetric()), nil
+}
+
+// flattenQueryResults converts the single/batch response union into [][]QueryResult.
+// A single-query response is returned as a batch of one. Scores are computed
+// from distances using metric.
+func flattenQueryResults(resp *QueryResponse, metric string) [][]QueryResult {
+	if resp == nil {
+		return nil
+	}
+
+	var batches [][]QueryResultItem
+	switch {
+	case resp.Results.ArrayOfArrayOfQueryResultItem != nil:
+		batches = *resp.Results.ArrayOfArrayOfQueryResultItem
+	case resp.Results.ArrayOfQueryResultItem != nil:
+		batches = [][]QueryResultItem{*resp.Results.ArrayOfQueryResultItem}
+	default:
+		return nil
+	}
+
+	out := make([][]QueryResult, len(batches))
+	for i, items := range batches {
+		out[i] = make([]QueryResult, len(items))
+		for j, item := range items {
+			out[i][j] = QueryResult{
+				ID:       item.Id,
+				Distance: item.GetDistance(),
+				Score:    Similarity(metric, item.GetDistance()),
+				Metadata: item.Metadata,
+				Vector:   item.Vector,
+			}
+		}
+	}
+	return out
+}
+
+const (
+	// DefaultQueryManyBatchSize is the number of query vectors sent per request by QueryMany.
+	DefaultQueryManyBatchSize = 100
+	// DefaultQueryManyConcurrency is the number of concurrent requests used by
+	// QueryMany when concurrency is not positive.
+	DefaultQueryManyConcurrency = 4
+)
+
+// QueryMany runs a large number of query vectors by splitting them into
+// sub-batches of DefaultQueryManyBatchSize, sending up to concurrency batch
+// requests at once, and stitching the results back in input order.
+//
+// Requests go through the client's transport, so client-wide limits such as
+// WithMaxConnsPerHost also pace QueryMany. The first failing batch cancels the
+// remaining work and its error is returned.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vectors: The query vectors
+//   - concurrency: Maximum in-flight requests (DefaultQueryManyConcurrency if <= 0)
+//   - opts: Optional settings applied to every batch, such as WithTopK
+//
+// Returns:
+//   - [][]QueryResult: One result slice per query vector, in input order
+//   - error: Any error encountered during the search
+func (e *EncryptedIndex) QueryMany(ctx context.Context, vectors [][]float32, concurrency int, opts ...QueryOption) ([][]QueryResult, error) {
+	if len(vectors) == 0 {
+		return nil, ErrEmptyQueryVector
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultQueryManyConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]QueryResult, len(vectors))
+	starts := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + DefaultQueryManyBatchSize
+				if end > len(vectors) {
+					end = len(vectors)
+				}
+
+				batch, err := e.queryManyBatch(ctx, vectors[start:end], opts)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("query batch at offset %d: %w", start, err)
+						cancel()
+					})
+					continue
+				}
+				copy(results[start:end], batch)
+			}
+		}()
+	}
+
+feed:
+	for start := 0; start < len(vectors); start += DefaultQueryManyBatchSize {
+		select {
+		case starts <- start:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(starts)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// queryManyBatch runs one QueryMany sub-batch. A single vector is sent as a
+// single query, since the server answers it with a flat result list.
+func (e *EncryptedIndex) queryManyBatch(ctx context.Context, vectors [][]float32, opts []QueryOption) ([][]QueryResult, error) {
+	if len(vectors) == 1 {
+		hits, err := e.QueryOne(ctx, vectors[0], opts...)
+		if err != nil {
+			return nil, err
+		}
+		return [][]QueryResult{hits}, nil
+	}
+
+	batch, err := e.QueryBatch(ctx, vectors, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(batch) != len(vectors) {
+		return nil, fmt.Errorf("expected %d result sets, got %d", len(vectors), len(batch))
+	}
+	return batch, nil
+}
@@ -0,0 +1,148 @@
+// ratelimit.go implements client-side request pacing and 429 handling.
+package cyborgdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitRetries is the number of times a request rejected with
+// 429 Too Many Requests is retried when WithRateLimit is enabled.
+const DefaultRateLimitRetries = 3
+
+// WithRateLimit paces requests to at most rps per second, allowing bursts of
+// up to burst requests. A burst below 1 is treated as 1.
+//
+// Requests rejected with 429 Too Many Requests are retried up to
+// DefaultRateLimitRetries times. A Retry-After header pauses all requests on
+// the client until it elapses; without one, the retry waits for the next
+// token. The limit is shared by every index handle created from the client.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimit = rps
+		o.rateBurst = burst
+	}
+}
+
+// tokenBucket is a mutex-guarded token bucket limiter.
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64 // tokens per second
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// newTokenBucket returns a full bucket.
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if pause := b.pausedUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait
+}
+
+// pause holds back all requests until d has elapsed.
+func (b *tokenBucket) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	d := b.reserve()
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rateLimitTransport paces requests through a tokenBucket and retries 429s.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	bucket  *tokenBucket
+	retries int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := t.bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= t.retries {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The body was consumed and cannot be replayed.
+			return resp, nil
+		}
+
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			t.bucket.pause(d)
+		}
+		resp.Body.Close()
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
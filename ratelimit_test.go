@@ -0,0 +1,85 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestRateLimitPacesRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"indexes":[]}`))
+	}))
+	defer srv.Close()
+
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithRateLimit(20, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.ListIndexes(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A burst of 1 at 20 rps spaces the last three requests 50ms apart.
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 150ms", elapsed)
+	}
+}
+
+func TestRateLimitRetriesTooManyRequests(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if n <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"detail":"slow down"}`))
+			return
+		}
+		w.Write([]byte(`{"indexes":["docs"]}`))
+	}))
+	defer srv.Close()
+
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithRateLimit(1000, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err := client.ListIndexes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("got %v after %d calls, want [docs] after 3", names, atomic.LoadInt32(&calls))
+	}
+}
+
+func TestRateLimitGivesUpAfterRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"detail":"slow down"}`))
+	}))
+	defer srv.Close()
+
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithRateLimit(1000, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListIndexes(context.Background()); err == nil {
+		t.Fatal("ListIndexes succeeded against a service that always returns 429")
+	}
+	if want := int32(cyborgdb.DefaultRateLimitRetries + 1); atomic.LoadInt32(&calls) != want {
+		t.Errorf("service called %d times, want %d", atomic.LoadInt32(&calls), want)
+	}
+}
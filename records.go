@@ -0,0 +1,230 @@
+// records.go defines the portable Record type and the pluggable record
+// formats used by bulk export and import.
+//
+// JSONL and CSV are built in, as is a Parquet writer for export (see
+// parquet.go). Reading Parquet needs a full decoder, which is kept out of the
+// core SDK to preserve its zero-dependency footprint; applications can plug
+// one in with RegisterRecordReader, and replace any built-in codec with
+// RegisterRecordWriter.
+package cyborgdb
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// RecordFormat identifies a bulk export/import serialization format.
+type RecordFormat string
+
+const (
+	// FormatJSONL is newline-delimited JSON, one Record per line.
+	FormatJSONL RecordFormat = "jsonl"
+	// FormatCSV is comma-separated values with a header row. Recognized columns
+	// are "id", "vector" (a JSON array), "metadata" (a JSON object), and "contents".
+	// Import only.
+	FormatCSV RecordFormat = "csv"
+	// FormatParquet is Apache Parquet. Export writes it natively, with the
+	// vector as a repeated float column and metadata as a JSON column; Import
+	// needs a reader registered with RegisterRecordReader.
+	FormatParquet RecordFormat = "parquet"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when no codec is registered for a RecordFormat.
+	ErrUnsupportedFormat = fmt.Errorf("unsupported record format")
+
+	// ErrMalformedRecord is wrapped by RecordReader implementations for row-level
+	// problems; Import skips such rows instead of aborting.
+	ErrMalformedRecord = errors.New("malformed record")
+)
+
+// Record is the portable representation of a stored vector item.
+type Record struct {
+	ID       string                 `json:"id"`
+	Vector   []float32              `json:"vector,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Contents *string                `json:"contents,omitempty"`
+}
+
+// RecordWriter serializes a stream of Records.
+type RecordWriter interface {
+	// WriteRecord writes a single record.
+	WriteRecord(rec Record) error
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+}
+
+// RecordReader deserializes a stream of Records.
+type RecordReader interface {
+	// ReadRecord returns the next record, or io.EOF when the stream is exhausted.
+	// Row-level problems should wrap ErrMalformedRecord so the caller can skip
+	// the row; any other error is treated as fatal.
+	ReadRecord() (Record, error)
+}
+
+var (
+	recordFormatsMu sync.RWMutex
+	recordWriters   = map[RecordFormat]func(io.Writer) RecordWriter{
+		FormatJSONL:   newJSONLWriter,
+		FormatParquet: newParquetWriter,
+	}
+	recordReaders = map[RecordFormat]func(io.Reader) (RecordReader, error){
+		FormatJSONL: newJSONLReader,
+		FormatCSV:   newCSVReader,
+	}
+)
+
+// RegisterRecordWriter makes a RecordWriter constructor available for format.
+// Registering a format twice replaces the previous constructor.
+func RegisterRecordWriter(format RecordFormat, newWriter func(io.Writer) RecordWriter) {
+	recordFormatsMu.Lock()
+	defer recordFormatsMu.Unlock()
+	recordWriters[format] = newWriter
+}
+
+// RegisterRecordReader makes a RecordReader constructor available for format.
+// Registering a format twice replaces the previous constructor.
+func RegisterRecordReader(format RecordFormat, newReader func(io.Reader) (RecordReader, error)) {
+	recordFormatsMu.Lock()
+	defer recordFormatsMu.Unlock()
+	recordReaders[format] = newReader
+}
+
+// newRecordReader returns a RecordReader for format, or ErrUnsupportedFormat.
+func newRecordReader(format RecordFormat, r io.Reader) (RecordReader, error) {
+	recordFormatsMu.RLock()
+	newReader, ok := recordReaders[format]
+	recordFormatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	return newReader(r)
+}
+
+// newRecordWriter returns a RecordWriter for format, or ErrUnsupportedFormat.
+func newRecordWriter(format RecordFormat, w io.Writer) (RecordWriter, error) {
+	recordFormatsMu.RLock()
+	newWriter, ok := recordWriters[format]
+	recordFormatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	return newWriter(w), nil
+}
+
+// jsonlWriter writes one JSON-encoded Record per line.
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) RecordWriter {
+	buf := bufio.NewWriter(w)
+	return &jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (j *jsonlWriter) WriteRecord(rec Record) error { return j.enc.Encode(rec) }
+
+func (j *jsonlWriter) Flush() error { return j.buf.Flush() }
+
+// jsonlReader reads one JSON-encoded Record per line. Blank lines are ignored.
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// maxJSONLLineSize bounds a single JSONL line (large vectors plus contents).
+const maxJSONLLineSize = 64 << 20
+
+func newJSONLReader(r io.Reader) (RecordReader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxJSONLLineSize)
+	return &jsonlReader{scanner: scanner}, nil
+}
+
+func (j *jsonlReader) ReadRecord() (Record, error) {
+	for j.scanner.Scan() {
+		j.line++
+		line := strings.TrimSpace(j.scanner.Text())
+		if line == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return Record{}, fmt.Errorf("%w: line %d: %v", ErrMalformedRecord, j.line, err)
+		}
+		return rec, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// csvReader reads Records from CSV with a header row.
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+func newCSVReader(r io.Reader) (RecordReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["id"]; !ok {
+		return nil, fmt.Errorf("%w: CSV header has no \"id\" column", ErrMalformedRecord)
+	}
+	return &csvReader{r: cr, columns: columns, line: 1}, nil
+}
+
+func (c *csvReader) field(row []string, name string) string {
+	if i, ok := c.columns[name]; ok && i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+func (c *csvReader) ReadRecord() (Record, error) {
+	row, err := c.r.Read()
+	c.line++
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{}, fmt.Errorf("%w: line %d: %v", ErrMalformedRecord, c.line, err)
+		}
+		return Record{}, err
+	}
+
+	rec := Record{ID: c.field(row, "id")}
+	if v := c.field(row, "vector"); v != "" {
+		if err := json.Unmarshal([]byte(v), &rec.Vector); err != nil {
+			return Record{}, fmt.Errorf("%w: line %d: invalid vector: %v", ErrMalformedRecord, c.line, err)
+		}
+	}
+	if m := c.field(row, "metadata"); m != "" {
+		if err := json.Unmarshal([]byte(m), &rec.Metadata); err != nil {
+			return Record{}, fmt.Errorf("%w: line %d: invalid metadata: %v", ErrMalformedRecord, c.line, err)
+		}
+	}
+	if _, ok := c.columns["contents"]; ok {
+		contents := c.field(row, "contents")
+		if contents != "" {
+			rec.Contents = &contents
+		}
+	}
+	return rec, nil
+}
@@ -0,0 +1,277 @@
+// rerank.go implements client-side post-processing of query results: exact
+// re-ranking and Maximal Marginal Relevance (MMR) diversification.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultRerankFactor multiplies TopK to get the number of candidates
+	// fetched for re-ranking when QueryParams.RerankCandidates is below TopK.
+	DefaultRerankFactor = 4
+	// defaultQueryTopK is the server's TopK when none is given.
+	defaultQueryTopK = 100
+)
+
+// DefaultMMRLambda balances relevance and diversity equally in MMR.
+const DefaultMMRLambda = 0.5
+
+// ErrRerankNeedsVector is returned when RerankExact is set on a query that
+// has no query vector to compute distances against.
+var ErrRerankNeedsVector = fmt.Errorf("exact re-ranking requires a query vector")
+
+// WithRerankExact over-fetches candidates approximate results (DefaultRerankFactor
+// times TopK if candidates < TopK), recomputes their exact distances client-side,
+// and returns the closest TopK. See QueryParams.RerankExact.
+func WithRerankExact(candidates int32) QueryOption {
+	return func(p *QueryParams) {
+		p.RerankExact = true
+		p.RerankCandidates = candidates
+	}
+}
+
+// WithMMR diversifies results with Maximal Marginal Relevance. lambda trades
+// relevance (1) for diversity (0). See QueryParams.MMRLambda.
+func WithMMR(lambda float64) QueryOption {
+	return func(p *QueryParams) { p.MMRLambda = &lambda }
+}
+
+// MMR selects up to k results from candidates by Maximal Marginal Relevance:
+// each pick maximizes lambda*relevance - (1-lambda)*(max similarity to the
+// results already picked). lambda = 1 keeps the original relevance order;
+// lower values favor diversity. Candidates need their vectors (query with
+// include "vector"); relevance is the cosine similarity to query, or derived
+// from Distance when query is nil.
+//
+// Parameters:
+//   - query: The query vector, or nil
+//   - candidates: Results to choose from, typically over-fetched
+//   - k: Number of results to return
+//   - lambda: Relevance/diversity trade-off in [0, 1], e.g. DefaultMMRLambda
+//
+// Returns:
+//   - []QueryResult: The selected results in pick order
+//
+// Example:
+//
+//	hits, _ := index.QueryOne(ctx, vec, cyborgdb.WithTopK(40), cyborgdb.WithInclude("vector", "metadata"))
+//	diverse := cyborgdb.MMR(vec, hits, 10, 0.7)
+func MMR(query []float32, candidates []QueryResult, k int, lambda float64) []QueryResult {
+	relevance := make([]float64, len(candidates))
+	vectors := make([][]float32, len(candidates))
+	for i, c := range candidates {
+		relevance[i] = mmrRelevance(query, c.Vector, c.Distance)
+		vectors[i] = c.Vector
+	}
+	picked := mmrSelect(relevance, vectors, k, lambda)
+	out := make([]QueryResult, len(picked))
+	for i, p := range picked {
+		out[i] = candidates[p]
+	}
+	return out
+}
+
+// mmrRelevance scores a candidate's relevance: cosine similarity to the query
+// when both vectors are known, otherwise a similarity derived from distance.
+func mmrRelevance(query, vector []float32, distance float32) float64 {
+	if len(query) > 0 && len(query) == len(vector) {
+		return 1 - float64(cosineDistance(query, vector))
+	}
+	return 1 / (1 + float64(distance))
+}
+
+// mmrSelect greedily picks up to k candidate positions by MMR.
+func mmrSelect(relevance []float64, vectors [][]float32, k int, lambda float64) []int {
+	if k > len(relevance) {
+		k = len(relevance)
+	}
+	picked := make([]int, 0, k)
+	used := make([]bool, len(relevance))
+	// maxSim[i] is candidate i's highest similarity to any picked candidate.
+	maxSim := make([]float64, len(relevance))
+
+	for len(picked) < k {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range relevance {
+			if used[i] {
+				continue
+			}
+			score := lambda * relevance[i]
+			if len(picked) > 0 {
+				score -= (1 - lambda) * maxSim[i]
+			}
+			// NaN scores rank last but are still picked when nothing else is left.
+			if math.IsNaN(score) {
+				score = math.Inf(-1)
+			}
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		used[best] = true
+		picked = append(picked, best)
+
+		for i := range relevance {
+			if used[i] || len(vectors[i]) != len(vectors[best]) || len(vectors[i]) == 0 {
+				continue
+			}
+			if sim := 1 - float64(cosineDistance(vectors[i], vectors[best])); sim > maxSim[i] {
+				maxSim[i] = sim
+			}
+		}
+	}
+	return picked
+}
+
+// queryPostProcess runs params as an over-fetching query that includes
+// vectors, then applies RerankExact and MMR client-side to each result list
+// and truncates it to TopK.
+func (e *EncryptedIndex) queryPostProcess(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	queries := params.BatchQueryVectors
+	if len(params.QueryVector) > 0 {
+		queries = [][]float32{params.QueryVector}
+	}
+	if len(queries) == 0 && params.RerankExact {
+		return nil, ErrRerankNeedsVector
+	}
+
+	topK := params.TopK
+	if topK <= 0 {
+		topK = defaultQueryTopK
+	}
+	candidates := params.RerankCandidates
+	if candidates < topK {
+		candidates = topK * DefaultRerankFactor
+	}
+
+	keepVector := containsString(params.Include, "vector")
+	fetch := params
+	fetch.RerankExact = false
+	fetch.MMRLambda = nil
+	fetch.TopK = candidates
+	if params.Include == nil {
+		fetch.Include = []string{"distance", "metadata", "vector"}
+	} else if !keepVector {
+		fetch.Include = append(append([]string(nil), params.Include...), "vector")
+	}
+
+	resp, err := e.query(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	distance := distanceFunc(e.GetMetric())
+	process := func(query []float32, items []QueryResultItem) []QueryResultItem {
+		if params.RerankExact {
+			for i := range items {
+				if len(items[i].Vector) == len(query) {
+					d := distance(query, items[i].Vector)
+					items[i].Distance.Set(&d)
+				}
+			}
+			sort.SliceStable(items, func(a, b int) bool {
+				return items[a].GetDistance() < items[b].GetDistance()
+			})
+		}
+
+		if params.MMRLambda != nil {
+			relevance := make([]float64, len(items))
+			vectors := make([][]float32, len(items))
+			for i, item := range items {
+				relevance[i] = mmrRelevance(query, item.Vector, item.GetDistance())
+				vectors[i] = item.Vector
+			}
+			picked := mmrSelect(relevance, vectors, int(topK), *params.MMRLambda)
+			selected := make([]QueryResultItem, len(picked))
+			for i, p := range picked {
+				selected[i] = items[p]
+			}
+			items = selected
+		} else if int32(len(items)) > topK {
+			items = items[:topK]
+		}
+
+		if !keepVector {
+			for i := range items {
+				items[i].Vector = nil
+			}
+		}
+		return items
+	}
+
+	switch {
+	case resp.Results.ArrayOfArrayOfQueryResultItem != nil:
+		batches := *resp.Results.ArrayOfArrayOfQueryResultItem
+		if len(queries) > 0 && len(batches) != len(queries) {
+			return nil, fmt.Errorf("expected %d result sets, got %d", len(queries), len(batches))
+		}
+		for i := range batches {
+			batches[i] = process(queryAt(queries, i), batches[i])
+		}
+	case resp.Results.ArrayOfQueryResultItem != nil:
+		items := process(queryAt(queries, 0), *resp.Results.ArrayOfQueryResultItem)
+		resp.Results.ArrayOfQueryResultItem = &items
+	}
+	return resp, nil
+}
+
+// queryAt returns the i-th query vector, or nil for content queries.
+func queryAt(queries [][]float32, i int) []float32 {
+	if i < len(queries) {
+		return queries[i]
+	}
+	return nil
+}
+
+// distanceFunc returns the exact distance for metric, matching the service's
+// definitions. Unknown metrics fall back to the service default, euclidean.
+func distanceFunc(metric string) func(a, b []float32) float32 {
+	switch metric {
+	case "cosine":
+		return cosineDistance
+	case "squared_euclidean":
+		return squaredEuclidean
+	default:
+		return func(a, b []float32) float32 {
+			return float32(math.Sqrt(float64(squaredEuclidean(a, b))))
+		}
+	}
+}
+
+// squaredEuclidean returns the squared L2 distance between a and b.
+func squaredEuclidean(a, b []float32) float32 {
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return float32(sum)
+}
+
+// cosineDistance returns 1 minus the cosine similarity of a and b.
+func cosineDistance(a, b []float32) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	return float32(1 - dot/(math.Sqrt(na)*math.Sqrt(nb)))
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package cyborgdb_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestMMRWithNaNScores(t *testing.T) {
+	nan := float32(math.NaN())
+	all := []cyborgdb.QueryResult{{ID: "a", Distance: nan}, {ID: "b", Distance: nan}}
+	if got := resultIDs(cyborgdb.MMR(nil, all, 2, 0.5)); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("all-NaN MMR = %v, want [a b]", got)
+	}
+
+	mixed := []cyborgdb.QueryResult{{ID: "nan", Distance: nan}, {ID: "far", Distance: 2}, {ID: "near", Distance: 0.1}}
+	if got := resultIDs(cyborgdb.MMR(nil, mixed, 3, 1)); !reflect.DeepEqual(got, []string{"near", "far", "nan"}) {
+		t.Errorf("MMR with a NaN score = %v, want [near far nan]", got)
+	}
+}
+
+func resultIDs(results []cyborgdb.QueryResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
@@ -0,0 +1,202 @@
+// rotate.go implements index key rotation.
+//
+// The service has no re-key endpoint, so rotation re-encrypts the data by
+// copying it through a temporary index created under the new key, then
+// recreating the original index name under the new key.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// rotateBatchSize is the number of vectors moved per batch during rotation.
+const rotateBatchSize = 500
+
+// copyInclude lists the fields fetched from a source index when copying vectors.
+var copyInclude = []string{"vector", "metadata", "contents"}
+
+// RotationProgress reports the state of a RotateIndexKey call.
+type RotationProgress struct {
+	// Phase is "copy-to-temp" or "copy-back".
+	Phase string
+	// Copied is the number of vectors copied so far in this phase.
+	Copied int
+	// Total is the number of vectors to copy in this phase.
+	Total int
+}
+
+// RotateIndexKey re-encrypts an index under a new key, keeping its name.
+//
+// The vectors are copied into a temporary index created with newKey, the
+// original index is deleted and recreated with newKey, and the vectors are
+// copied back. The index configuration, metric, schema, and catalog labels
+// are preserved.
+// The index is unavailable between deleting the original and the final copy
+// completing.
+//
+// If an error occurs after the original index was deleted, the returned
+// error names the temporary index that still holds the data under newKey.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - indexName: Name of the index to rotate
+//   - oldKey: Current 32-byte key
+//   - newKey: New 32-byte key
+//   - onProgress: Optional callback invoked after each batch (may be nil)
+//
+// Returns:
+//   - *EncryptedIndex: Handle for the index under the new key
+//   - error: ErrHashedFieldRekey under WithSearchableHashes, or any other
+//     error encountered
+func (c *Client) RotateIndexKey(
+	ctx context.Context,
+	indexName string,
+	oldKey, newKey []byte,
+	onProgress func(RotationProgress),
+) (*EncryptedIndex, error) {
+	if len(newKey) != KeySize {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(newKey))
+	}
+	if err := c.opts.checkRekey(oldKey, newKey); err != nil {
+		return nil, err
+	}
+
+	src, err := c.LoadIndex(ctx, indexName, oldKey)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := src.GetSchema(ctx)
+	if err != nil && !errors.Is(err, ErrNoSchema) {
+		return nil, err
+	}
+	labels, err := c.catalogLabels(ctx, indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyGeneration, err)
+	}
+	params := &CreateIndexParams{
+		IndexName:   fmt.Sprintf("%s_rotate_%x", indexName, suffix),
+		IndexKey:    newKey,
+		IndexConfig: indexModelFromConfig(src.cachedConfig()),
+	}
+	if metric := src.GetMetric(); metric != "" {
+		params.Metric = &metric
+	}
+
+	tmp, err := c.CreateIndex(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary index: %w", err)
+	}
+
+	if err := copyVectors(ctx, src, tmp, "copy-to-temp", onProgress); err != nil {
+		_ = tmp.DeleteIndex(ctx)
+		return nil, fmt.Errorf("failed to copy into temporary index: %w", err)
+	}
+
+	if err := src.DeleteIndex(ctx); err != nil {
+		_ = tmp.DeleteIndex(ctx)
+		return nil, fmt.Errorf("failed to delete original index: %w", err)
+	}
+
+	params.IndexName = indexName
+	params.Labels = labels
+	dst, err := c.CreateIndex(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recreate index (data preserved in %q under the new key): %w", tmp.GetIndexName(), err)
+	}
+
+	if err := copyVectors(ctx, tmp, dst, "copy-back", onProgress); err != nil {
+		return nil, fmt.Errorf("failed to copy back into index (data preserved in %q under the new key): %w", tmp.GetIndexName(), err)
+	}
+
+	if schema != nil {
+		if err := dst.SetSchema(ctx, schema); err != nil {
+			return nil, fmt.Errorf("failed to restore schema (data preserved in %q under the new key): %w", tmp.GetIndexName(), err)
+		}
+	}
+
+	if err := tmp.DeleteIndex(ctx); err != nil {
+		return dst, fmt.Errorf("rotation succeeded but temporary index %q was not deleted: %w", tmp.GetIndexName(), err)
+	}
+
+	if src.IsTrained() {
+		if err := dst.Train(ctx, TrainParams{}); err != nil {
+			return dst, fmt.Errorf("rotation succeeded but retraining failed: %w", err)
+		}
+	}
+	return dst, nil
+}
+
+// RotateIndexKeyWithKeyProvider is RotateIndexKey with both keys resolved
+// through providers, e.g. SecretKeys. The resolved copies are wiped when the
+// rotation returns.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - indexName: Name of the index to rotate
+//   - oldKey: Source of the current key
+//   - newKey: Source of the new key
+//   - onProgress: Optional callback invoked after each batch (may be nil)
+//
+// Returns:
+//   - *EncryptedIndex: Handle for the index under the new key
+//   - error: Any error resolving the keys or rotating the index
+func (c *Client) RotateIndexKeyWithKeyProvider(
+	ctx context.Context,
+	indexName string,
+	oldKey, newKey KeyProvider,
+	onProgress func(RotationProgress),
+) (*EncryptedIndex, error) {
+	old, err := resolveKey(ctx, oldKey, indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(old)
+	key, err := resolveKey(ctx, newKey, indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(key)
+	return c.RotateIndexKey(ctx, indexName, old, key, onProgress)
+}
+
+// copyVectors copies every vector from src to dst in batches.
+func copyVectors(ctx context.Context, src, dst *EncryptedIndex, phase string, onProgress func(RotationProgress)) error {
+	listed, err := src.ListIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := listed.Ids
+	for start := 0; start < len(ids); start += rotateBatchSize {
+		end := start + rotateBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		resp, err := src.Get(ctx, ids[start:end], copyInclude)
+		if err != nil {
+			return err
+		}
+
+		items := make([]VectorItem, len(resp.Results))
+		for i, r := range resp.Results {
+			items[i] = VectorItem{Id: r.Id, Vector: r.Vector, Metadata: r.Metadata, Contents: r.Contents}
+		}
+		if err := dst.Upsert(WithPreservedTimestamps(ctx), items); err != nil {
+			return err
+		}
+
+		if onProgress != nil {
+			onProgress(RotationProgress{Phase: phase, Copied: end, Total: len(ids)})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// scan.go implements iteration over every item in an index.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// ScanOptions configures Scan. Zero values fall back to defaults.
+type ScanOptions struct {
+	// Include lists the fields returned for each item ("vector", "metadata",
+	// "contents"). Default: metadata only.
+	Include []string
+
+	// ChunkSize is the number of items fetched per request. Default: DefaultGetChunkSize.
+	ChunkSize int
+
+	// RequestsPerSecond, if positive, paces the chunk requests so a long scan
+	// does not crowd out production traffic.
+	RequestsPerSecond float64
+}
+
+// Scan returns an iterator over every item stored in the index, for
+// re-indexing, exports, and offline audits.
+//
+// The service does not paginate ListIDs, so Scan lists all IDs once up front
+// and then fetches the items lazily, one chunk per request. Items upserted
+// after the scan starts are not returned; items deleted after it starts are
+// skipped.
+//
+// Parameters:
+//   - ctx: Context for cancellation; it applies to every request
+//   - opts: Fields, chunk size, and pacing options
+//
+// Returns:
+//   - *ItemIterator: Iterator over all items
+//
+// Example:
+//
+//	it := index.Scan(ctx, cyborgdb.ScanOptions{Include: []string{"metadata"}, RequestsPerSecond: 5})
+//	for it.Next() {
+//		audit(it.Item().Id, it.Item().Metadata)
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+func (e *EncryptedIndex) Scan(ctx context.Context, opts ScanOptions) *ItemIterator {
+	include := opts.Include
+	if include == nil {
+		include = []string{"metadata"}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultGetChunkSize
+	}
+	var limiter *tokenBucket
+	if opts.RequestsPerSecond > 0 {
+		limiter = newTokenBucket(opts.RequestsPerSecond, 1)
+	}
+
+	var (
+		chunks [][]string
+		next   int
+		listed bool
+	)
+	return &ItemIterator{
+		ctx: ctx,
+		fetch: func(ctx context.Context) ([]GetResultItem, bool, error) {
+			if !listed {
+				resp, err := e.ListIDs(ctx)
+				if err != nil {
+					return nil, false, fmt.Errorf("failed to list IDs: %w", err)
+				}
+				chunks, listed = chunkIDs(resp.Ids, chunkSize), true
+				if len(chunks) == 0 {
+					return nil, false, nil
+				}
+			}
+
+			if limiter != nil {
+				if err := limiter.wait(ctx); err != nil {
+					return nil, false, err
+				}
+			}
+			resp, err := e.getChunk(ctx, chunks[next], include)
+			if err != nil {
+				return nil, false, err
+			}
+			next++
+			return resp.Results, next < len(chunks), nil
+		},
+	}
+}
@@ -0,0 +1,523 @@
+// schema.go implements an optional per-index schema for metadata fields,
+// stored in a small side index under the index's key so every client sees it.
+//
+// The service accepts any metadata, so a typo or a number sent as a string
+// silently produces items that filters never match. A Schema declares each
+// field's type and whether it may be filtered on; upserts are checked against
+// it, and FilterBuilder uses it to type-check filters before they are sent.
+package cyborgdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+// schemaIndexPrefix starts the name of every schema side index.
+const schemaIndexPrefix = "_cyborgdb_schema_"
+
+// Schema side index item ID and metadata key holding the encoded schema.
+const (
+	schemaItemID      = "schema"
+	schemaMetadataKey = "schema"
+)
+
+// SchemaIndexName returns the name of the side index that stores the schema
+// of indexName. The schema lives outside the index so it never appears in
+// its results, listings, exports, or training data. The side index shares
+// the index's key and is deleted with it.
+func SchemaIndexName(indexName string) string {
+	return schemaIndexPrefix + indexName
+}
+
+// FieldType is the type of a metadata field in a Schema.
+type FieldType string
+
+// Field types.
+const (
+	FieldString     FieldType = "string"
+	FieldNumber     FieldType = "number"
+	FieldInteger    FieldType = "integer"
+	FieldBoolean    FieldType = "boolean"
+	FieldStringList FieldType = "string_list"
+	FieldObject     FieldType = "object"
+	FieldAny        FieldType = "any"
+)
+
+var (
+	// ErrNoSchema is returned by GetSchema for an index without a schema.
+	ErrNoSchema = errors.New("index has no schema")
+
+	// ErrInvalidSchema is returned for a Schema that cannot be stored.
+	ErrInvalidSchema = errors.New("invalid schema")
+
+	// ErrInvalidFilter is returned for a filter that does not type-check
+	// against a Schema.
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// FieldSchema describes one metadata field.
+type FieldSchema struct {
+	// Type is the field's type.
+	Type FieldType `json:"type"`
+
+	// Required rejects items without the field.
+	Required bool `json:"required,omitempty"`
+
+	// Filterable allows the field in filters built by FilterBuilder. Fields
+	// are not filterable unless declared so, like unindexed database
+	// columns.
+	Filterable bool `json:"filterable,omitempty"`
+}
+
+// Schema declares the metadata fields of an index. Nested fields are named
+// with dotted paths such as "author.name".
+type Schema struct {
+	// Fields maps field names to their declarations.
+	Fields map[string]FieldSchema `json:"fields"`
+
+	// Strict rejects metadata and filters using undeclared fields, other
+	// than the system timestamps MetadataCreatedAt and MetadataUpdatedAt.
+	Strict bool `json:"strict,omitempty"`
+}
+
+// Validate checks that every field has a known type.
+func (s *Schema) Validate() error {
+	for name, field := range s.Fields {
+		if name == "" {
+			return fmt.Errorf("%w: empty field name", ErrInvalidSchema)
+		}
+		switch field.Type {
+		case FieldString, FieldNumber, FieldInteger, FieldBoolean, FieldStringList, FieldObject, FieldAny:
+		default:
+			return fmt.Errorf("%w: field %q has unknown type %q", ErrInvalidSchema, name, field.Type)
+		}
+	}
+	return nil
+}
+
+// ValidateMetadata checks m against the schema: declared fields must have
+// their type, required fields must be present, and with Strict no undeclared
+// top-level field may appear. Null values count as absent.
+//
+// Returns:
+//   - error: nil, or a *MetadataError wrapping ErrInvalidMetadata
+func (s *Schema) ValidateMetadata(m map[string]interface{}) error {
+	for _, name := range s.fieldNames() {
+		field := s.Fields[name]
+		value, present := lookupField(m, name)
+		if !present || value == nil {
+			if field.Required {
+				return &MetadataError{Path: name, Reason: "required field is missing"}
+			}
+			continue
+		}
+		if !field.Type.matches(value) {
+			return &MetadataError{Path: name, Reason: fmt.Sprintf("expected %s, got %T", field.Type, value)}
+		}
+	}
+	if s.Strict {
+		for key := range m {
+			if key == MetadataCreatedAt || key == MetadataUpdatedAt {
+				continue
+			}
+			if !s.declares(key) {
+				return &MetadataError{Path: key, Reason: "field is not declared in the schema"}
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateFilter checks that filter only uses filterable fields, with
+// operators and operands that suit their types. Undeclared fields are
+// accepted unless the schema is Strict.
+//
+// Returns:
+//   - error: nil, or an error wrapping ErrInvalidFilter
+func (s *Schema) ValidateFilter(filter map[string]interface{}) error {
+	for key, cond := range filter {
+		if key == "$and" || key == "$or" {
+			clauses, ok := cond.([]interface{})
+			if !ok {
+				if maps, isMaps := cond.([]map[string]interface{}); isMaps {
+					for _, clause := range maps {
+						if err := s.ValidateFilter(clause); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				return fmt.Errorf("%w: %s expects a list of filters", ErrInvalidFilter, key)
+			}
+			for _, clause := range clauses {
+				m, ok := clause.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%w: %s expects a list of filters", ErrInvalidFilter, key)
+				}
+				if err := s.ValidateFilter(m); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := s.validateCondition(key, cond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCondition checks the condition on one field.
+func (s *Schema) validateCondition(name string, cond interface{}) error {
+	field, declared := s.Fields[name]
+	if !declared {
+		if s.Strict && name != MetadataCreatedAt && name != MetadataUpdatedAt {
+			return fmt.Errorf("%w: field %q is not declared in the schema", ErrInvalidFilter, name)
+		}
+		return nil
+	}
+	if !field.Filterable {
+		return fmt.Errorf("%w: field %q is not filterable", ErrInvalidFilter, name)
+	}
+
+	ops, isOps := cond.(map[string]interface{})
+	if !isOps || !hasOperators(ops) {
+		return field.checkOperand(name, "$eq", cond)
+	}
+	for op, operand := range ops {
+		switch op {
+		case "$eq", "$ne":
+			if err := field.checkOperand(name, op, operand); err != nil {
+				return err
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			switch field.Type {
+			case FieldNumber, FieldInteger, FieldString, FieldAny:
+			default:
+				return fmt.Errorf("%w: %s cannot order %s field %q", ErrInvalidFilter, op, field.Type, name)
+			}
+			if err := field.checkOperand(name, op, operand); err != nil {
+				return err
+			}
+		case "$in", "$nin":
+			list, ok := filterList(operand)
+			if !ok {
+				return fmt.Errorf("%w: %s on %q expects a list", ErrInvalidFilter, op, name)
+			}
+			for _, v := range list {
+				if err := field.checkOperand(name, op, v); err != nil {
+					return err
+				}
+			}
+		case "$exists":
+			if _, ok := operand.(bool); !ok {
+				return fmt.Errorf("%w: $exists on %q expects a boolean", ErrInvalidFilter, name)
+			}
+		default:
+			return fmt.Errorf("%w: unknown operator %q on %q", ErrInvalidFilter, op, name)
+		}
+	}
+	return nil
+}
+
+// checkOperand checks a value compared with the field. Lists are compared
+// element-wise, so a string_list field takes string operands.
+func (f FieldSchema) checkOperand(name, op string, operand interface{}) error {
+	want := f.Type
+	if want == FieldStringList {
+		want = FieldString
+	}
+	if operand == nil || want.matches(operand) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s on %s field %q given %T", ErrInvalidFilter, op, f.Type, name, operand)
+}
+
+// matches reports whether value has type t.
+func (t FieldType) matches(value interface{}) bool {
+	switch t {
+	case FieldString:
+		_, ok := value.(string)
+		return ok
+	case FieldNumber:
+		_, ok := filterNumber(value)
+		return ok
+	case FieldInteger:
+		n, ok := filterNumber(value)
+		return ok && n == math.Trunc(n)
+	case FieldBoolean:
+		_, ok := value.(bool)
+		return ok
+	case FieldStringList:
+		if _, ok := value.([]string); ok {
+			return true
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, v := range list {
+			if _, ok := v.(string); !ok {
+				return false
+			}
+		}
+		return true
+	case FieldObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case FieldAny:
+		return true
+	}
+	return false
+}
+
+// fieldNames returns the declared field names, sorted so errors are stable.
+func (s *Schema) fieldNames() []string {
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// declares reports whether key is a declared field or the parent of one.
+func (s *Schema) declares(key string) bool {
+	if _, ok := s.Fields[key]; ok {
+		return true
+	}
+	for name := range s.Fields {
+		if strings.HasPrefix(name, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSchemaValidation makes every index handle load its index's schema
+// before its first upsert and reject items that do not match it, even when
+// the handle never called SetSchema or GetSchema.
+func WithSchemaValidation() ClientOption {
+	return func(o *clientOptions) { o.schemaValidation = true }
+}
+
+// SetSchema stores schema in the index's schema side index (see
+// SchemaIndexName), replacing any previous schema, and validates this
+// handle's later upserts against it. Existing items are not checked.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - schema: The schema to attach
+//
+// Returns:
+//   - error: ErrInvalidSchema, or any error storing the schema
+//
+// Example:
+//
+//	err := index.SetSchema(ctx, &cyborgdb.Schema{Fields: map[string]cyborgdb.FieldSchema{
+//		"category": {Type: cyborgdb.FieldString, Filterable: true},
+//		"year":     {Type: cyborgdb.FieldInteger, Required: true, Filterable: true},
+//		"tags":     {Type: cyborgdb.FieldStringList},
+//	}})
+func (e *EncryptedIndex) SetSchema(ctx context.Context, schema *Schema) error {
+	if schema == nil {
+		return fmt.Errorf("%w: nil schema", ErrInvalidSchema)
+	}
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	exists, err := e.schemaIndexExists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to store schema: %w", err)
+	}
+	if !exists {
+		if err := e.createSchemaIndex(ctx); err != nil {
+			return fmt.Errorf("failed to store schema: %w", err)
+		}
+	}
+	record := VectorItem{
+		Id:       schemaItemID,
+		Vector:   aliasVector,
+		Metadata: map[string]interface{}{schemaMetadataKey: string(data)},
+	}
+	if _, err := e.schemaIndex().upsertItems(ctx, []VectorItem{record}); err != nil {
+		e.setHasSchemaIndex(false)
+		return fmt.Errorf("failed to store schema: %w", err)
+	}
+	e.setSchema(schema)
+	return nil
+}
+
+// GetSchema fetches the index's schema and validates this handle's later
+// upserts against it.
+//
+// Returns:
+//   - *Schema: The index's schema
+//   - error: ErrNoSchema if none is stored, or any request error
+func (e *EncryptedIndex) GetSchema(ctx context.Context) (*Schema, error) {
+	exists, err := e.schemaIndexExists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema: %w", err)
+	}
+	if exists {
+		resp, err := e.schemaIndex().Get(ctx, []string{schemaItemID}, []string{"metadata"})
+		if err != nil {
+			// The side index may have been deleted through another
+			// handle; check again next time.
+			e.setHasSchemaIndex(false)
+			return nil, fmt.Errorf("failed to fetch schema: %w", err)
+		}
+		for _, item := range resp.Results {
+			if item.Id != schemaItemID {
+				continue
+			}
+			data, ok := item.Metadata[schemaMetadataKey].(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: malformed schema record", ErrInvalidSchema)
+			}
+			var schema Schema
+			if err := json.Unmarshal([]byte(data), &schema); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+			}
+			e.setSchema(&schema)
+			return &schema, nil
+		}
+	}
+	e.setSchema(nil)
+	return nil, ErrNoSchema
+}
+
+// DeleteSchema removes the index's schema and its side index; upserts are no
+// longer validated.
+func (e *EncryptedIndex) DeleteSchema(ctx context.Context) error {
+	if err := e.deleteSchemaIndex(ctx); err != nil {
+		return fmt.Errorf("failed to delete schema: %w", err)
+	}
+	e.setSchema(nil)
+	return nil
+}
+
+// schemaIndex returns a handle for e's schema side index.
+func (e *EncryptedIndex) schemaIndex() *EncryptedIndex {
+	return &EncryptedIndex{
+		indexName: SchemaIndexName(e.indexName),
+		indexKey:  e.indexKey,
+		client:    e.client,
+		opts:      e.opts,
+	}
+}
+
+// schemaIndexExists reports whether e has a schema side index. Only a side
+// index found or created through e is remembered: it is removed only by
+// DeleteSchema or DeleteIndex, while one missing now may be created by
+// another handle at any time.
+func (e *EncryptedIndex) schemaIndexExists(ctx context.Context) (bool, error) {
+	e.mu.RLock()
+	known := e.hasSchemaIndex
+	e.mu.RUnlock()
+	if known {
+		return true, nil
+	}
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+	names, err := e.client.ListIndexes(ctx)
+	if err != nil {
+		return false, err
+	}
+	want := SchemaIndexName(e.indexName)
+	for _, name := range names {
+		if name == want {
+			e.setHasSchemaIndex(true)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// setHasSchemaIndex records whether e's schema side index is known to exist.
+func (e *EncryptedIndex) setHasSchemaIndex(known bool) {
+	e.mu.Lock()
+	e.hasSchemaIndex = known
+	e.mu.Unlock()
+}
+
+// createSchemaIndex creates e's schema side index. It bypasses
+// Client.CreateIndex so the side index is never recorded in the catalog.
+func (e *EncryptedIndex) createSchemaIndex(ctx context.Context) error {
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.operationTimeout)
+	defer cancel()
+	name := SchemaIndexName(e.indexName)
+	req := internal.CreateIndexRequest{
+		IndexName:   name,
+		IndexKey:    e.indexKey,
+		IndexConfig: *internal.NewNullableIndexConfig(IndexIVFFlat(int32(len(aliasVector))).ToIndexConfig()),
+	}
+	start := time.Now()
+	_, _, err := e.client.APIClient.DefaultAPI.CreateIndexV1IndexesCreatePost(ctx).
+		CreateIndexRequest(req).
+		Execute()
+	e.opts.audit(ctx, AuditCreateIndex, name, 0, start, err)
+	if err == nil {
+		e.setHasSchemaIndex(true)
+	}
+	return err
+}
+
+// deleteSchemaIndex deletes e's schema side index, if it has one.
+func (e *EncryptedIndex) deleteSchemaIndex(ctx context.Context) error {
+	exists, err := e.schemaIndexExists(ctx)
+	if err != nil || !exists {
+		return err
+	}
+	if err := e.schemaIndex().DeleteIndex(ctx); err != nil {
+		return err
+	}
+	e.setHasSchemaIndex(false)
+	return nil
+}
+
+// setSchema caches schema, which may be nil for none, as loaded.
+func (e *EncryptedIndex) setSchema(schema *Schema) {
+	e.mu.Lock()
+	e.schema, e.schemaLoaded = schema, true
+	e.mu.Unlock()
+}
+
+// validateSchema checks items against the cached schema, loading it first
+// under WithSchemaValidation. Internal indexes are never validated.
+func (e *EncryptedIndex) validateSchema(ctx context.Context, items []VectorItem) error {
+	if isInternalIndex(e.indexName) {
+		return nil
+	}
+	e.mu.RLock()
+	schema, loaded := e.schema, e.schemaLoaded
+	e.mu.RUnlock()
+	if !loaded && e.opts.schemaValidation {
+		var err error
+		if schema, err = e.GetSchema(ctx); err != nil && !errors.Is(err, ErrNoSchema) {
+			return err
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+	for _, item := range items {
+		if err := schema.ValidateMetadata(item.Metadata); err != nil {
+			return fmt.Errorf("item %q: %w", item.Id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func createFakeIndex(t *testing.T, client *cyborgdb.Client, name string, key []byte) *cyborgdb.EncryptedIndex {
+	t.Helper()
+	index, err := client.CreateIndex(context.Background(), &cyborgdb.CreateIndexParams{
+		IndexName:   name,
+		IndexKey:    key,
+		IndexConfig: cyborgdb.IndexIVFFlat(2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func TestSchemaIsStoredOutsideTheIndex(t *testing.T) {
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"year": 2020}},
+		{Id: "b", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"year": 2021}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := &cyborgdb.Schema{Fields: map[string]cyborgdb.FieldSchema{
+		"year": {Type: cyborgdb.FieldInteger, Required: true, Filterable: true},
+	}}
+	if err := index.SetSchema(ctx, schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := fake.indexNames(), []string{cyborgdb.SchemaIndexName("docs"), "docs"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("indexes = %v, want %v", got, want)
+	}
+	ids, err := index.ListIDs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids.Ids, []string{"a", "b"}) {
+		t.Errorf("ListIDs = %v, want [a b]", ids.Ids)
+	}
+	results, err := index.QueryOne(ctx, []float32{1, 0}, cyborgdb.WithTopK(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Errorf("query returned %d results, want 2", len(results))
+	}
+
+	// A fresh handle sees the schema and enforces it.
+	reloaded, err := client.LoadIndex(ctx, "docs", testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reloaded.GetSchema(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, schema) {
+		t.Errorf("GetSchema = %+v, want %+v", got, schema)
+	}
+	err = reloaded.Upsert(ctx, []cyborgdb.VectorItem{{Id: "c", Vector: []float32{1, 1}}})
+	if !errors.Is(err, cyborgdb.ErrInvalidMetadata) {
+		t.Errorf("upsert without a required field: err = %v, want ErrInvalidMetadata", err)
+	}
+
+	if err := index.DeleteIndex(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if names := fake.indexNames(); len(names) != 0 {
+		t.Errorf("indexes left after DeleteIndex: %v", names)
+	}
+}
+
+func TestDeleteSchema(t *testing.T) {
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+
+	if _, err := index.GetSchema(ctx); !errors.Is(err, cyborgdb.ErrNoSchema) {
+		t.Fatalf("GetSchema without a schema: err = %v, want ErrNoSchema", err)
+	}
+	if err := index.SetSchema(ctx, &cyborgdb.Schema{Fields: map[string]cyborgdb.FieldSchema{"a": {Type: cyborgdb.FieldString}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.DeleteSchema(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if fake.index(cyborgdb.SchemaIndexName("docs")) != nil {
+		t.Error("schema index left after DeleteSchema")
+	}
+	if _, err := index.GetSchema(ctx); !errors.Is(err, cyborgdb.ErrNoSchema) {
+		t.Errorf("GetSchema after DeleteSchema: err = %v, want ErrNoSchema", err)
+	}
+}
+
+func TestCloneIndexCopiesSchema(t *testing.T) {
+	_, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	schema := &cyborgdb.Schema{Fields: map[string]cyborgdb.FieldSchema{"a": {Type: cyborgdb.FieldString}}}
+	if err := index.SetSchema(ctx, schema); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := client.CloneIndex(ctx, "docs", "docs-copy", testKey(1), testKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := clone.GetSchema(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, schema) {
+		t.Errorf("clone schema = %+v, want %+v", got, schema)
+	}
+}
+
+func TestSchemaIndexLookupIsCached(t *testing.T) {
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	schema := &cyborgdb.Schema{Fields: map[string]cyborgdb.FieldSchema{"a": {Type: cyborgdb.FieldString}}}
+	listCalls := func() int {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.requests["/v1/indexes/list"]
+	}
+
+	if err := index.SetSchema(ctx, schema); err != nil {
+		t.Fatal(err)
+	}
+	before := listCalls()
+	for i := 0; i < 3; i++ {
+		if err := index.SetSchema(ctx, schema); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := index.GetSchema(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := listCalls() - before; n != 0 {
+		t.Errorf("schema operations listed indexes %d times after the side index was created", n)
+	}
+
+	// Another handle deletes the schema; the stale handle notices.
+	other, err := client.LoadIndex(ctx, "docs", testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.DeleteSchema(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := index.GetSchema(ctx); err == nil {
+		t.Fatal("GetSchema after another handle deleted the schema succeeded")
+	}
+	if _, err := index.GetSchema(ctx); !errors.Is(err, cyborgdb.ErrNoSchema) {
+		t.Errorf("GetSchema after retry: err = %v, want ErrNoSchema", err)
+	}
+}
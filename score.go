@@ -0,0 +1,64 @@
+// score.go converts raw query distances into normalized similarity scores.
+package cyborgdb
+
+import "context"
+
+// Similarity converts a distance reported for metric into a similarity score
+// in [0, 1], where 1 is an exact match:
+//   - cosine: 1 - distance/2, since cosine distance ranges over [0, 2]
+//   - euclidean and squared_euclidean: 1 / (1 + distance)
+//
+// Unknown metrics are treated as euclidean, the service default.
+func Similarity(metric string, distance float32) float32 {
+	if metric == "cosine" {
+		s := 1 - distance/2
+		switch {
+		case s < 0:
+			return 0
+		case s > 1:
+			return 1
+		}
+		return s
+	}
+	if distance < 0 {
+		return 1
+	}
+	return 1 / (1 + distance)
+}
+
+// WithMinScore drops results whose normalized similarity is below minScore.
+// See QueryParams.MinScore.
+func WithMinScore(minScore float32) QueryOption {
+	return func(p *QueryParams) { p.MinScore = &minScore }
+}
+
+// queryMinScore runs params and drops results scoring below MinScore.
+func (e *EncryptedIndex) queryMinScore(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	minScore := *params.MinScore
+	params.MinScore = nil
+	resp, err := e.query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := func(items []QueryResultItem) []QueryResultItem {
+		kept := items[:0]
+		for _, item := range items {
+			if Similarity(e.GetMetric(), item.GetDistance()) >= minScore {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	}
+	switch {
+	case resp.Results.ArrayOfArrayOfQueryResultItem != nil:
+		batches := *resp.Results.ArrayOfArrayOfQueryResultItem
+		for i := range batches {
+			batches[i] = keep(batches[i])
+		}
+	case resp.Results.ArrayOfQueryResultItem != nil:
+		items := keep(*resp.Results.ArrayOfQueryResultItem)
+		resp.Results.ArrayOfQueryResultItem = &items
+	}
+	return resp, nil
+}
@@ -0,0 +1,308 @@
+// searchhash.go implements deterministic keyed hashes of sensitive metadata
+// values, so fields such as email addresses support exact-match filters
+// without their plaintext being stored.
+//
+// A hashed field's value is replaced on upsert by HMAC-SHA256 under a key
+// derived from the index key, and equality filters on the field are rewritten
+// to the same hash at query time. Equal values give equal hashes, which is
+// what makes them searchable: anyone who can see stored metadata learns which
+// items share a value, but not the value itself, nor can they test guesses
+// without the index key.
+package cyborgdb
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hashedValuePrefix marks a searchable hash.
+const hashedValuePrefix = "cyborgdb:hmac:v1:"
+
+// searchHashKeyInfo is the HKDF info string of the searchable hash key.
+const searchHashKeyInfo = "cyborgdb searchable hash v1"
+
+var (
+	// ErrUnhashableValue is returned for a hashed field value that is not a
+	// string, number, boolean, or list of them.
+	ErrUnhashableValue = errors.New("value cannot be hashed")
+
+	// ErrHashedFieldFilter is returned for a filter on a hashed field using
+	// an operator other than equality, membership, or $exists; hashes do
+	// not preserve order.
+	ErrHashedFieldFilter = errors.New("unsupported filter on a hashed metadata field")
+
+	// ErrHashedFieldRekey is returned by RotateIndexKey and CloneIndex when
+	// copying to a new key under WithSearchableHashes. The hash key derives
+	// from the index key and the plaintext is gone, so the copied hashes
+	// could never match a filter again.
+	ErrHashedFieldRekey = errors.New("cannot change the key of an index with searchable hashes")
+
+	// ErrMalformedHash is returned for a hashed field value or filter
+	// operand that starts with the searchable hash prefix but is not a
+	// well-formed hash.
+	ErrMalformedHash = errors.New("malformed searchable hash")
+)
+
+// WithSearchableHashes replaces the values of the named top-level metadata
+// fields with searchable hashes on upsert, and rewrites $eq, $ne, $in, and
+// $nin filters on them (including literal {"field": value} filters) to match
+// the hashes, both in Query and in client-side filters such as Count. Range
+// operators on hashed fields fail with ErrHashedFieldFilter.
+//
+// The plaintext is not stored, so Get and Query return the hashes; keep a
+// copy in a field listed in WithEncryptedMetadata if it must be read back. A
+// field cannot be both hashed and encrypted. The hashes are keyed by the
+// index key and cannot be recomputed without the plaintext, so
+// RotateIndexKey, and CloneIndex with a new key, fail with
+// ErrHashedFieldRekey. Strings starting with the hash prefix are taken to be
+// hashes read back, and must be well-formed or fail with ErrMalformedHash.
+// Hashing is exact, so normalize values (e.g. lowercase email addresses)
+// before upserting and querying. Every client writing the index must use the
+// same field list.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithSearchableHashes("email"),
+//		cyborgdb.WithEncryptedMetadata("email_plain"))
+//	...
+//	results, err := index.QueryOne(ctx, vec,
+//		cyborgdb.WithFilters(map[string]interface{}{"email": "ada@example.com"}))
+func WithSearchableHashes(fields ...string) ClientOption {
+	return func(o *clientOptions) {
+		if o.hashedFields == nil {
+			o.hashedFields = make(map[string]bool, len(fields))
+		}
+		for _, f := range fields {
+			if o.encryptedFields[f] {
+				o.setErr(fmt.Errorf("metadata field %q cannot be both hashed and encrypted", f))
+				return
+			}
+			o.hashedFields[f] = true
+		}
+	}
+}
+
+// SearchableHash returns the hash stored for value in field, for comparing
+// with metadata read back from a hashed field. Lists hash element-wise.
+//
+// Returns:
+//   - interface{}: The hash string, or a list of hashes for a list value
+//   - error: ErrUnhashableValue for unsupported value types
+func (e *EncryptedIndex) SearchableHash(field string, value interface{}) (interface{}, error) {
+	key, err := e.deriveIndexSubkey(searchHashKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return hashValue(key, field, value)
+}
+
+// checkRekey rejects copying an index to a different key when searchable
+// hashes are configured.
+func (o *clientOptions) checkRekey(oldKey, newKey []byte) error {
+	if len(o.hashedFields) == 0 || bytes.Equal(oldKey, newKey) {
+		return nil
+	}
+	return ErrHashedFieldRekey
+}
+
+// hashValue hashes a scalar value, or each element of a list.
+func hashValue(key []byte, field string, value interface{}) (interface{}, error) {
+	if list, ok := value.([]interface{}); ok {
+		out := make([]interface{}, len(list))
+		for i, v := range list {
+			h, err := hashScalar(key, field, v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = h
+		}
+		return out, nil
+	}
+	if list, ok := value.([]string); ok {
+		out := make([]interface{}, len(list))
+		for i, v := range list {
+			h, err := hashScalar(key, field, v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = h
+		}
+		return out, nil
+	}
+	return hashScalar(key, field, value)
+}
+
+// hashScalar hashes the canonical form of a string, number, or boolean, so
+// that 7 and 7.0 hash alike but 7 and "7" do not. The field name is hashed
+// too, so equal values in different fields cannot be correlated. Well-formed
+// hashes pass through, so items read back can be re-upserted under the same
+// key; other strings with the hash prefix fail with ErrMalformedHash.
+func hashScalar(key []byte, field string, value interface{}) (string, error) {
+	var canonical string
+	if n, ok := filterNumber(value); ok {
+		canonical = "n:" + strconv.FormatFloat(n, 'g', -1, 64)
+	} else {
+		switch v := value.(type) {
+		case string:
+			if strings.HasPrefix(v, hashedValuePrefix) {
+				sum, err := base64.RawURLEncoding.DecodeString(v[len(hashedValuePrefix):])
+				if err != nil || len(sum) != sha256.Size {
+					return "", fmt.Errorf("%w: field %q holds %q", ErrMalformedHash, field, v)
+				}
+				return v, nil
+			}
+			canonical = "s:" + v
+		case bool:
+			canonical = "b:" + strconv.FormatBool(v)
+		default:
+			return "", fmt.Errorf("%w: field %q holds %T", ErrUnhashableValue, field, value)
+		}
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(canonical))
+	return hashedValuePrefix + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// hashItemsMetadata returns items with the configured fields replaced by
+// their hashes, copying the metadata of every item it changes.
+func (e *EncryptedIndex) hashItemsMetadata(items []VectorItem) ([]VectorItem, error) {
+	fields := e.opts.hashedFields
+	if len(fields) == 0 || isInternalIndex(e.indexName) {
+		return items, nil
+	}
+	key, err := e.deriveIndexSubkey(searchHashKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []VectorItem
+	for i, item := range items {
+		var meta map[string]interface{}
+		for field := range fields {
+			value, ok := item.Metadata[field]
+			if !ok || value == nil {
+				continue
+			}
+			hashed, err := hashValue(key, field, value)
+			if err != nil {
+				return nil, fmt.Errorf("item %q: %w", item.Id, err)
+			}
+			if meta == nil {
+				meta = make(map[string]interface{}, len(item.Metadata))
+				for k, v := range item.Metadata {
+					meta[k] = v
+				}
+			}
+			meta[field] = hashed
+		}
+		if meta == nil {
+			continue
+		}
+		if out == nil {
+			out = make([]VectorItem, len(items))
+			copy(out, items)
+		}
+		out[i].Metadata = meta
+	}
+	if out == nil {
+		return items, nil
+	}
+	return out, nil
+}
+
+// rewriteHashedFilter returns filter with the operands of conditions on
+// hashed fields replaced by their hashes. filter itself is not modified.
+func (e *EncryptedIndex) rewriteHashedFilter(filter map[string]interface{}) (map[string]interface{}, error) {
+	if len(e.opts.hashedFields) == 0 || len(filter) == 0 {
+		return filter, nil
+	}
+	key, err := e.deriveIndexSubkey(searchHashKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	return rewriteHashedConditions(key, e.opts.hashedFields, filter)
+}
+
+func rewriteHashedConditions(key []byte, fields map[string]bool, filter map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(filter))
+	for name, cond := range filter {
+		switch {
+		case name == "$and" || name == "$or":
+			clauses, ok := filterList(cond)
+			if !ok {
+				out[name] = cond
+				continue
+			}
+			rewritten := make([]interface{}, len(clauses))
+			for i, clause := range clauses {
+				m, ok := clause.(map[string]interface{})
+				if !ok {
+					rewritten[i] = clause
+					continue
+				}
+				r, err := rewriteHashedConditions(key, fields, m)
+				if err != nil {
+					return nil, err
+				}
+				rewritten[i] = r
+			}
+			out[name] = rewritten
+		case fields[name]:
+			r, err := rewriteHashedCondition(key, name, cond)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = r
+		default:
+			out[name] = cond
+		}
+	}
+	return out, nil
+}
+
+// rewriteHashedCondition hashes the operands of one field's condition.
+func rewriteHashedCondition(key []byte, field string, cond interface{}) (interface{}, error) {
+	ops, isOps := cond.(map[string]interface{})
+	if !isOps || !hasOperators(ops) {
+		return hashScalar(key, field, cond)
+	}
+	out := make(map[string]interface{}, len(ops))
+	for op, operand := range ops {
+		switch op {
+		case "$eq", "$ne":
+			h, err := hashScalar(key, field, operand)
+			if err != nil {
+				return nil, err
+			}
+			out[op] = h
+		case "$in", "$nin":
+			list, ok := filterList(operand)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s on %q expects a list", ErrHashedFieldFilter, op, field)
+			}
+			hashed := make([]interface{}, len(list))
+			for i, v := range list {
+				h, err := hashScalar(key, field, v)
+				if err != nil {
+					return nil, err
+				}
+				hashed[i] = h
+			}
+			out[op] = hashed
+		case "$exists":
+			out[op] = operand
+		default:
+			return nil, fmt.Errorf("%w: %s on %q", ErrHashedFieldFilter, op, field)
+		}
+	}
+	return out, nil
+}
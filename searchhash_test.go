@@ -0,0 +1,190 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// countingServer describes every index as an empty 4-dimensional IVFFlat
+// index, answers every other request with 500, and counts requests.
+func countingServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/indexes/describe" {
+			w.Write([]byte(`{"index_name":"docs","index_type":"ivfflat","is_trained":false,` +
+				`"index_config":{"type":"ivfflat","dimension":4}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"detail":"unavailable"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func testKey(b byte) []byte {
+	key := make([]byte, cyborgdb.KeySize)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func TestSearchableHashesBlockRekey(t *testing.T) {
+	srv, calls := countingServer(t)
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithSearchableHashes("email"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	oldKey, newKey := testKey(1), testKey(2)
+
+	if _, err := client.RotateIndexKey(ctx, "docs", oldKey, newKey, nil); !errors.Is(err, cyborgdb.ErrHashedFieldRekey) {
+		t.Errorf("RotateIndexKey error = %v, want ErrHashedFieldRekey", err)
+	}
+	if _, err := client.CloneIndex(ctx, "docs", "docs-copy", oldKey, newKey); !errors.Is(err, cyborgdb.ErrHashedFieldRekey) {
+		t.Errorf("CloneIndex with a new key error = %v, want ErrHashedFieldRekey", err)
+	}
+	if n := atomic.LoadInt32(calls); n != 0 {
+		t.Fatalf("service called %d times before the rekey was refused", n)
+	}
+
+	// Cloning under the same key keeps the hashes valid and is allowed.
+	if _, err := client.CloneIndex(ctx, "docs", "docs-copy", oldKey, nil); errors.Is(err, cyborgdb.ErrHashedFieldRekey) {
+		t.Errorf("CloneIndex with the same key refused: %v", err)
+	}
+	if atomic.LoadInt32(calls) == 0 {
+		t.Error("CloneIndex with the same key did not reach the service")
+	}
+}
+
+func TestSearchableHashDependsOnIndexKey(t *testing.T) {
+	srv, _ := countingServer(t)
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithSearchableHashes("email"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	a, err := client.LoadIndex(ctx, "a", testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := client.LoadIndex(ctx, "b", testKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ha, err := a.SearchableHash("email", "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hb, err := b.SearchableHash("email", "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ha == hb {
+		t.Error("hashes under different index keys are equal")
+	}
+	again, err := a.SearchableHash("email", ha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != ha {
+		t.Error("an existing hash was hashed again")
+	}
+}
+
+func TestQueryIntoAppliesMetadataProtection(t *testing.T) {
+	fake, client := newFakeService(t,
+		cyborgdb.WithSearchableHashes("email"),
+		cyborgdb.WithEncryptedMetadata("name"))
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "people", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "ada", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"email": "ada@example.com", "name": "Ada"}},
+		{Id: "bob", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"email": "bob@example.com", "name": "Bob"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if stored := fake.item("people", "ada")["metadata"].(map[string]interface{}); stored["name"] == "Ada" || stored["email"] == "ada@example.com" {
+		t.Fatalf("plaintext stored: %v", stored)
+	}
+
+	buf := cyborgdb.AcquireQueryBuffer()
+	defer cyborgdb.ReleaseQueryBuffer(buf)
+	err := index.QueryInto(ctx, []float32{0, 1}, buf,
+		cyborgdb.WithFilters(map[string]interface{}{"email": "ada@example.com"}),
+		cyborgdb.WithInclude("metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buf.Results) != 1 || buf.Results[0].ID != "ada" {
+		t.Fatalf("results = %+v, want only ada", buf.Results)
+	}
+	if name := buf.Results[0].Metadata["name"]; name != "Ada" {
+		t.Errorf("name = %v, want decrypted \"Ada\"", name)
+	}
+
+	err = index.QueryInto(ctx, []float32{0, 1}, buf, cyborgdb.WithFilters(map[string]interface{}{"name": "Ada"}))
+	if !errors.Is(err, cyborgdb.ErrEncryptedFieldFilter) {
+		t.Errorf("filter on an encrypted field: err = %v, want ErrEncryptedFieldFilter", err)
+	}
+}
+
+func TestSearchableHashesRejectMalformedHashes(t *testing.T) {
+	fake, client := newFakeService(t, cyborgdb.WithSearchableHashes("email"))
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "people", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "ada", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"email": "ada@example.com"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, planted := range []interface{}{"cyborgdb:hmac:v1:", "cyborgdb:hmac:v1:anything", []interface{}{"x", "cyborgdb:hmac:v1:!!"}} {
+		err := index.Upsert(ctx, []cyborgdb.VectorItem{
+			{Id: "eve", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"email": planted}},
+		})
+		if !errors.Is(err, cyborgdb.ErrMalformedHash) {
+			t.Errorf("upsert of %v: err = %v, want ErrMalformedHash", planted, err)
+		}
+		operands, ok := planted.([]interface{})
+		if !ok {
+			operands = []interface{}{planted}
+		}
+		_, err = index.QueryOne(ctx, []float32{1, 0}, cyborgdb.WithFilters(map[string]interface{}{"email": map[string]interface{}{"$in": operands}}))
+		if !errors.Is(err, cyborgdb.ErrMalformedHash) {
+			t.Errorf("filter on %v: err = %v, want ErrMalformedHash", planted, err)
+		}
+	}
+	if fake.item("people", "eve") != nil {
+		t.Error("an item with a malformed hash was stored")
+	}
+
+	// A hash read back is well-formed, so the item can be upserted again and
+	// the hash used as a filter operand.
+	got, err := index.Get(ctx, []string{"ada"}, []string{"vector", "metadata"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := got.Results[0]
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: item.Id, Vector: item.Vector, Metadata: item.Metadata}}); err != nil {
+		t.Fatalf("re-upserting an item read back: %v", err)
+	}
+	results, err := index.QueryOne(ctx, []float32{1, 0}, cyborgdb.WithFilters(map[string]interface{}{"email": item.Metadata["email"]}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "ada" {
+		t.Errorf("filter on a stored hash = %v, want [ada]", resultIDs(results))
+	}
+}
@@ -0,0 +1,140 @@
+// secret_key.go implements SecretKey, an index key held in a zeroizable,
+// memory-locked buffer that never prints its contents.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ErrSecretKeyClosed is returned when a SecretKey is used after Close.
+var ErrSecretKeyClosed = fmt.Errorf("secret key has been closed")
+
+// redacted is printed in place of key material.
+const redacted = "[REDACTED]"
+
+// SecretKey holds a 32-byte index key in a dedicated buffer.
+//
+// On Linux and macOS the buffer sits in its own memory page outside the Go
+// heap, locked so it is not swapped to disk. On every platform it is
+// overwritten with zeros on Close, or when the garbage collector finds the
+// SecretKey unreachable if Close was never called. A SecretKey formats as "[REDACTED]" with
+// every fmt verb and in JSON, so it cannot leak through logs or error
+// messages.
+//
+// SecretKey implements KeyProvider, so it can be passed as
+// CreateIndexParams.KeyProvider, migrate.CopyIndex's destination
+// KeyProvider, or to Client.LoadIndexWithKeyProvider,
+// Client.RotateIndexKeyWithKeyProvider, and Client.CloneIndexWithKeyProvider.
+// The SDK wipes the copies it resolves once they are no longer needed.
+//
+// An EncryptedIndex handle, however, keeps an encoded copy of its key for as
+// long as the handle is reachable, because every request carries the key.
+// That copy is not locked or zeroed; keep handles short-lived where this
+// matters.
+//
+// A SecretKey is safe for concurrent use.
+type SecretKey struct {
+	mu     sync.RWMutex
+	buf    []byte
+	locked bool
+}
+
+// Compile-time check that SecretKey satisfies KeyProvider.
+var _ KeyProvider = (*SecretKey)(nil)
+
+// NewSecretKey copies key into a new SecretKey.
+//
+// The caller remains responsible for the original slice; use Zeroize to wipe
+// it once the SecretKey has been created. Call Close when done with the key:
+// a SecretKey that is dropped without Close keeps its key in memory until the
+// garbage collector runs its finalizer, which may be much later or, at exit,
+// never.
+//
+// Returns:
+//   - *SecretKey: The protected key
+//   - error: ErrInvalidKeyLength if key is not 32 bytes
+func NewSecretKey(key []byte) (*SecretKey, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(key))
+	}
+	k := newSecretKey()
+	copy(k.buf, key)
+	return k, nil
+}
+
+// GenerateSecretKey returns a new random SecretKey without the key ever
+// existing in an unprotected buffer. As with NewSecretKey, call Close when
+// done with the key.
+func GenerateSecretKey() (*SecretKey, error) {
+	k := newSecretKey()
+	if _, err := rand.Read(k.buf); err != nil {
+		_ = k.Close()
+		return nil, fmt.Errorf("%w: %v", ErrKeyGeneration, err)
+	}
+	return k, nil
+}
+
+// newSecretKey allocates an empty SecretKey whose buffer is wiped and
+// released by a finalizer if it is never closed.
+func newSecretKey() *SecretKey {
+	k := &SecretKey{}
+	k.buf, k.locked = allocSecret()
+	runtime.SetFinalizer(k, (*SecretKey).Close)
+	return k
+}
+
+// IndexKey implements KeyProvider. It returns a copy of the key, which the
+// SDK wipes once it is no longer needed.
+func (k *SecretKey) IndexKey(context.Context, string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if k.buf == nil {
+		return nil, ErrSecretKeyClosed
+	}
+	out := make([]byte, len(k.buf))
+	copy(out, k.buf)
+	return out, nil
+}
+
+// Close wipes the key from memory and releases its page.
+// It is safe to call Close more than once.
+func (k *SecretKey) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.buf == nil {
+		return nil
+	}
+	Zeroize(k.buf)
+	freeSecret(k.buf, k.locked)
+	k.buf = nil
+	runtime.SetFinalizer(k, nil)
+	return nil
+}
+
+// String implements fmt.Stringer without revealing the key.
+func (k *SecretKey) String() string { return "SecretKey(" + redacted + ")" }
+
+// GoString implements fmt.GoStringer without revealing the key.
+func (k *SecretKey) GoString() string { return k.String() }
+
+// Format implements fmt.Formatter so that every verb, including %x and %v,
+// prints the redacted form.
+func (k *SecretKey) Format(f fmt.State, _ rune) { _, _ = f.Write([]byte(k.String())) }
+
+// MarshalJSON implements json.Marshaler without revealing the key.
+func (k *SecretKey) MarshalJSON() ([]byte, error) { return []byte(`"` + redacted + `"`), nil }
+
+// MarshalText implements encoding.TextMarshaler without revealing the key.
+func (k *SecretKey) MarshalText() ([]byte, error) { return []byte(redacted), nil }
+
+// Zeroize overwrites b with zeros. Use it to wipe raw key material once it is
+// no longer needed.
+func Zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
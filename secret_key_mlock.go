@@ -0,0 +1,36 @@
+//go:build linux || darwin
+
+package cyborgdb
+
+import (
+	"os"
+	"syscall"
+)
+
+// allocSecret returns a KeySize buffer at the start of its own anonymous
+// mapping, locked into memory if possible, and whether the lock succeeded.
+// Keys never share a page with each other or with the Go heap, so unlocking
+// one cannot unlock another. Lock failure (e.g. RLIMIT_MEMLOCK exhausted)
+// is not fatal.
+func allocSecret() (buf []byte, locked bool) {
+	page, err := syscall.Mmap(-1, 0, os.Getpagesize(),
+		syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return make([]byte, KeySize), false
+	}
+	return page[:KeySize], syscall.Mlock(page) == nil
+}
+
+// freeSecret releases a buffer returned by allocSecret. The caller zeroes it
+// first.
+func freeSecret(buf []byte, locked bool) {
+	if cap(buf) == KeySize {
+		// Heap fallback from a failed mmap.
+		return
+	}
+	page := buf[:cap(buf)]
+	if locked {
+		_ = syscall.Munlock(page)
+	}
+	_ = syscall.Munmap(page)
+}
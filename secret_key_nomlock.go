@@ -0,0 +1,9 @@
+//go:build !(linux || darwin)
+
+package cyborgdb
+
+// allocSecret returns a heap buffer on platforms without mmap and mlock.
+func allocSecret() (buf []byte, locked bool) { return make([]byte, KeySize), false }
+
+// freeSecret is a no-op on platforms without mmap and mlock.
+func freeSecret([]byte, bool) {}
@@ -0,0 +1,86 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestSecretKey(t *testing.T) {
+	ctx := context.Background()
+	a, err := cyborgdb.NewSecretKey(testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cyborgdb.NewSecretKey(testKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	for _, s := range []string{fmt.Sprint(a), fmt.Sprintf("%x %#v %s", a, a, a)} {
+		if strings.Contains(s, "0101") {
+			t.Errorf("formatted key leaks material: %s", s)
+		}
+	}
+	if js, _ := json.Marshal(a); string(js) != `"[REDACTED]"` {
+		t.Errorf("JSON = %s", js)
+	}
+
+	// Closing one key must leave others intact.
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.IndexKey(ctx, "docs"); !errors.Is(err, cyborgdb.ErrSecretKeyClosed) {
+		t.Errorf("IndexKey after Close: err = %v, want ErrSecretKeyClosed", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	key, err := b.IndexKey(ctx, "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != string(testKey(2)) {
+		t.Error("closing one key changed another")
+	}
+
+	if _, err := cyborgdb.NewSecretKey(make([]byte, 16)); !errors.Is(err, cyborgdb.ErrInvalidKeyLength) {
+		t.Errorf("short key: err = %v, want ErrInvalidKeyLength", err)
+	}
+}
+
+func TestCloneAndRotateWithSecretKeys(t *testing.T) {
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: "a", Vector: []float32{1, 0}}}); err != nil {
+		t.Fatal(err)
+	}
+	oldKey, _ := cyborgdb.NewSecretKey(testKey(1))
+	defer oldKey.Close()
+	newKey, _ := cyborgdb.NewSecretKey(testKey(2))
+	defer newKey.Close()
+
+	if _, err := client.CloneIndexWithKeyProvider(ctx, "docs", "docs-copy", oldKey, newKey); err != nil {
+		t.Fatal(err)
+	}
+	if fake.item("docs-copy", "a") == nil {
+		t.Error("clone is missing item a")
+	}
+	if _, err := client.LoadIndex(ctx, "docs-copy", testKey(2)); err != nil {
+		t.Errorf("clone is not under the new key: %v", err)
+	}
+
+	if _, err := client.RotateIndexKeyWithKeyProvider(ctx, "docs", oldKey, newKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	if idx := fake.index("docs"); idx == nil || idx.key != fmt.Sprintf("%x", testKey(2)) {
+		t.Error("index was not rotated to the new key")
+	}
+}
@@ -0,0 +1,246 @@
+// Package semanticcache caches LLM responses by the meaning of their
+// prompts on top of an EncryptedIndex, to avoid paying for a model call when
+// a semantically equivalent prompt was answered recently.
+//
+// Put stores a prompt's embedding with the response in metadata; Lookup
+// embeds a new prompt, finds the nearest cached prompt, and returns its
+// response if the similarity clears a threshold and the entry has not
+// expired. Prompts are embedded with the configured Embedder, the index's
+// Embedder, or the index's server-side embedding model, in that order.
+package semanticcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+const (
+	// DefaultThreshold is the minimum normalized similarity (see
+	// cyborgdb.Similarity) for a cached prompt to match.
+	DefaultThreshold = 0.95
+	// DefaultTTL is how long entries stay valid when Options.TTL is not set.
+	DefaultTTL = 24 * time.Hour
+	// lookupCandidates is the number of nearest prompts considered per lookup.
+	lookupCandidates = 5
+)
+
+// Metadata keys written on every entry.
+const (
+	MetaPrompt    = "prompt"
+	MetaResponse  = "response"
+	MetaCreatedAt = "created_at"
+	MetaExpiresAt = "expires_at"
+)
+
+// ErrEmptyPrompt is returned when Put or Lookup receives an empty prompt.
+var ErrEmptyPrompt = errors.New("semanticcache: prompt must not be empty")
+
+// Options configures a Cache. Zero values fall back to defaults.
+type Options struct {
+	// Embedder embeds prompts. Default: the index's Embedder, or server-side
+	// embedding if the index has none.
+	Embedder cyborgdb.Embedder
+
+	// TTL is how long an entry can be returned after Put. Default: DefaultTTL.
+	TTL time.Duration
+
+	// Threshold is the default minimum similarity used by Lookup when its
+	// threshold argument is not positive. Default: DefaultThreshold.
+	Threshold float32
+}
+
+// Hit is a cached response returned by Lookup.
+type Hit struct {
+	// Prompt is the cached prompt that matched.
+	Prompt string
+	// Response is the cached response.
+	Response string
+	// Score is the normalized similarity between the prompts, in [0, 1].
+	Score float32
+	// CreatedAt is when the entry was stored.
+	CreatedAt time.Time
+}
+
+// Cache is a semantic cache backed by an EncryptedIndex. It is safe for
+// concurrent use if the index is.
+type Cache struct {
+	index *cyborgdb.EncryptedIndex
+	opts  Options
+	now   func() time.Time
+}
+
+// New returns a Cache storing entries in index.
+func New(index *cyborgdb.EncryptedIndex, opts Options) *Cache {
+	if opts.Embedder == nil {
+		opts.Embedder = index.GetEmbedder()
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+	return &Cache{index: index, opts: opts, now: time.Now}
+}
+
+// Put caches response for prompt. Storing the same prompt again replaces
+// the entry and restarts its TTL.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - prompt: The prompt that produced response
+//   - response: The response to cache
+//
+// Returns:
+//   - error: Any error embedding or storing the entry
+func (c *Cache) Put(ctx context.Context, prompt, response string) error {
+	if prompt == "" {
+		return ErrEmptyPrompt
+	}
+	now := c.now()
+	item := cyborgdb.VectorItem{
+		Id:       cyborgdb.DeterministicID([]byte(prompt)),
+		Contents: cyborgdb.TextContents(prompt),
+		Metadata: map[string]interface{}{
+			MetaPrompt:    prompt,
+			MetaResponse:  response,
+			MetaCreatedAt: now.Unix(),
+			MetaExpiresAt: now.Add(c.opts.TTL).Unix(),
+		},
+	}
+	if c.opts.Embedder != nil {
+		vectors, err := c.opts.Embedder.Embed(ctx, []string{prompt})
+		if err != nil {
+			return fmt.Errorf("semanticcache: failed to embed prompt: %w", err)
+		}
+		if len(vectors) != 1 {
+			return fmt.Errorf("semanticcache: embedder returned %d vectors for 1 prompt", len(vectors))
+		}
+		item.Vector = vectors[0]
+	}
+
+	if err := c.index.Upsert(ctx, []cyborgdb.VectorItem{item}); err != nil {
+		return fmt.Errorf("semanticcache: failed to store entry: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the cached response for the prompt most similar to prompt,
+// if its similarity is at least threshold (Options.Threshold if <= 0) and it
+// has not expired.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - prompt: The new prompt
+//   - threshold: Minimum normalized similarity in [0, 1]
+//
+// Returns:
+//   - *Hit: The cached entry, or nil on a miss
+//   - error: Any error embedding or querying
+//
+// Example:
+//
+//	if hit, err := cache.Lookup(ctx, prompt, 0); err == nil && hit != nil {
+//		return hit.Response, nil
+//	}
+//	answer := callModel(prompt)
+//	_ = cache.Put(ctx, prompt, answer)
+func (c *Cache) Lookup(ctx context.Context, prompt string, threshold float32) (*Hit, error) {
+	if prompt == "" {
+		return nil, ErrEmptyPrompt
+	}
+	if threshold <= 0 {
+		threshold = c.opts.Threshold
+	}
+	now := c.now()
+
+	params := cyborgdb.QueryParams{
+		TopK:     lookupCandidates,
+		Include:  []string{"distance", "metadata"},
+		Filters:  map[string]interface{}{MetaExpiresAt: map[string]interface{}{"$gt": now.Unix()}},
+		MinScore: &threshold,
+	}
+	if c.opts.Embedder != nil {
+		vectors, err := c.opts.Embedder.Embed(ctx, []string{prompt})
+		if err != nil {
+			return nil, fmt.Errorf("semanticcache: failed to embed prompt: %w", err)
+		}
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("semanticcache: embedder returned %d vectors for 1 prompt", len(vectors))
+		}
+		params.QueryVector = vectors[0]
+	} else {
+		params.QueryContents = &prompt
+	}
+
+	resp, err := c.index.Query(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("semanticcache: lookup failed: %w", err)
+	}
+	if resp.Results.ArrayOfQueryResultItem == nil {
+		return nil, nil
+	}
+
+	metric := c.index.GetMetric()
+	for _, item := range *resp.Results.ArrayOfQueryResultItem {
+		// Re-check expiry in case the server ignored the filter.
+		expires, _ := unixField(item.Metadata[MetaExpiresAt])
+		if expires <= now.Unix() {
+			continue
+		}
+		response, ok := item.Metadata[MetaResponse].(string)
+		if !ok {
+			continue
+		}
+		cachedPrompt, _ := item.Metadata[MetaPrompt].(string)
+		created, _ := unixField(item.Metadata[MetaCreatedAt])
+		return &Hit{
+			Prompt:    cachedPrompt,
+			Response:  response,
+			Score:     cyborgdb.Similarity(metric, item.GetDistance()),
+			CreatedAt: time.Unix(created, 0),
+		}, nil
+	}
+	return nil, nil
+}
+
+// PurgeExpired deletes expired entries and returns how many were removed.
+// Expired entries are never returned by Lookup, so purging only reclaims space.
+func (c *Cache) PurgeExpired(ctx context.Context) (int, error) {
+	now := c.now().Unix()
+	var expired []string
+	it := c.index.Scan(ctx, cyborgdb.ScanOptions{Include: []string{"metadata"}})
+	for it.Next() {
+		item := it.Item()
+		if expires, ok := unixField(item.Metadata[MetaExpiresAt]); ok && expires <= now {
+			expired = append(expired, item.Id)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, fmt.Errorf("semanticcache: failed to scan entries: %w", err)
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	if err := c.index.Delete(ctx, expired); err != nil {
+		return 0, fmt.Errorf("semanticcache: failed to delete expired entries: %w", err)
+	}
+	return len(expired), nil
+}
+
+// unixField reads a Unix timestamp decoded from JSON metadata.
+func unixField(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), true
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,289 @@
+// sharded.go implements client-side sharding: a ShardedIndex spreads items
+// across several indexes by hashing their IDs, so one logical collection can
+// grow beyond the limits of a single index.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// ErrNoShards is returned when a ShardedIndex is built without shards.
+var ErrNoShards = errors.New("sharded index requires at least one shard")
+
+// ShardedIndex hashes item IDs across a fixed set of indexes and exposes the
+// Upsert, Query, Get, and Delete API of a single index. Writes and lookups go
+// to the shard owning each ID; queries fan out to every shard and the results
+// are merged by distance.
+//
+// The shard of an ID depends on the number of shards, so a ShardedIndex must
+// always be opened with the same shards in the same order. All shards should
+// share one configuration and metric, or merged distances are not comparable.
+//
+// A ShardedIndex is safe for concurrent use.
+//
+// Example:
+//
+//	sharded, err := client.CreateShardedIndex(ctx, &cyborgdb.CreateIndexParams{
+//		IndexName:   "docs",
+//		IndexKey:    key,
+//		IndexConfig: cyborgdb.IndexIVFFlat(768),
+//	}, 8)
+//	err = sharded.Upsert(ctx, items)
+//	results, err := sharded.Query(ctx, vec, cyborgdb.WithTopK(10))
+type ShardedIndex struct {
+	shards []*EncryptedIndex
+}
+
+// NewShardedIndex groups shards, in order, into a ShardedIndex.
+func NewShardedIndex(shards ...*EncryptedIndex) (*ShardedIndex, error) {
+	if len(shards) == 0 {
+		return nil, ErrNoShards
+	}
+	return &ShardedIndex{shards: append([]*EncryptedIndex(nil), shards...)}, nil
+}
+
+// ShardName returns the name of shard i of the sharded index name, as used by
+// CreateShardedIndex and LoadShardedIndex.
+func ShardName(name string, i int) string {
+	return fmt.Sprintf("%s_shard_%d", name, i)
+}
+
+// CreateShardedIndex creates n indexes named ShardName(params.IndexName, i),
+// all with params' configuration and key, and returns them as a ShardedIndex.
+// If a shard fails, the shards already created are left in place and the
+// error is returned.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - params: Name, key, and configuration shared by every shard
+//   - n: Number of shards (must be positive)
+//
+// Returns:
+//   - *ShardedIndex: Handle over the new shards
+//   - error: ErrNoShards or any creation error
+func (c *Client) CreateShardedIndex(ctx context.Context, params *CreateIndexParams, n int) (*ShardedIndex, error) {
+	if n <= 0 {
+		return nil, ErrNoShards
+	}
+	shards := make([]*EncryptedIndex, n)
+	for i := range shards {
+		shardParams := *params
+		shardParams.IndexName = ShardName(params.IndexName, i)
+		index, err := c.CreateIndex(ctx, &shardParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shard %q: %w", shardParams.IndexName, err)
+		}
+		shards[i] = index
+	}
+	return NewShardedIndex(shards...)
+}
+
+// LoadShardedIndex loads the n shards of a sharded index created by
+// CreateShardedIndex.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - name: The sharded index name
+//   - n: Number of shards it was created with
+//   - key: 32-byte encryption key shared by the shards
+//
+// Returns:
+//   - *ShardedIndex: Handle over the shards
+//   - error: ErrNoShards or any load error
+func (c *Client) LoadShardedIndex(ctx context.Context, name string, n int, key []byte) (*ShardedIndex, error) {
+	if n <= 0 {
+		return nil, ErrNoShards
+	}
+	shards := make([]*EncryptedIndex, n)
+	for i := range shards {
+		index, err := c.LoadIndex(ctx, ShardName(name, i), key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load shard %q: %w", ShardName(name, i), err)
+		}
+		shards[i] = index
+	}
+	return NewShardedIndex(shards...)
+}
+
+// Shards returns the underlying indexes, in shard order.
+func (s *ShardedIndex) Shards() []*EncryptedIndex {
+	return append([]*EncryptedIndex(nil), s.shards...)
+}
+
+// ShardFor returns the position of the shard that owns id.
+func (s *ShardedIndex) ShardFor(id string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+// Upsert routes each item to its shard and upserts the shards concurrently.
+// The first shard error cancels the remaining requests; shards that already
+// finished keep their items.
+func (s *ShardedIndex) Upsert(ctx context.Context, items []VectorItem) error {
+	byShard := make(map[int][]VectorItem)
+	for _, item := range items {
+		i := s.ShardFor(item.Id)
+		byShard[i] = append(byShard[i], item)
+	}
+	return s.fanOut(ctx, keysOf(byShard), func(ctx context.Context, i int) error {
+		return s.shards[i].Upsert(ctx, byShard[i])
+	})
+}
+
+// Query searches every shard for vector and merges the results, closest
+// first, keeping the overall TopK.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - vector: The query vector
+//   - opts: Optional settings such as WithTopK, WithFilters, WithInclude
+//
+// Returns:
+//   - []QueryResult: Merged results ordered by distance (closest first)
+//   - error: The first shard error
+func (s *ShardedIndex) Query(ctx context.Context, vector []float32, opts ...QueryOption) ([]QueryResult, error) {
+	if len(vector) == 0 {
+		return nil, ErrEmptyQueryVector
+	}
+	var params QueryParams
+	for _, opt := range opts {
+		opt(&params)
+	}
+	topK := int(params.TopK)
+	if topK <= 0 {
+		topK = defaultQueryTopK
+	}
+
+	perShard := make([][]QueryResult, len(s.shards))
+	err := s.fanOut(ctx, s.all(), func(ctx context.Context, i int) error {
+		results, err := s.shards[i].QueryOne(ctx, vector, opts...)
+		perShard[i] = results
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var merged []QueryResult
+	for _, results := range perShard {
+		merged = append(merged, results...)
+	}
+	sort.SliceStable(merged, func(a, b int) bool { return merged[a].Distance < merged[b].Distance })
+	if len(merged) > topK {
+		merged = merged[:topK]
+	}
+	return merged, nil
+}
+
+// Get retrieves items by ID from their shards. Results follow the order of
+// ids; IDs that were not found are omitted.
+func (s *ShardedIndex) Get(ctx context.Context, ids []string, include []string) (*GetResponse, error) {
+	byShard := s.groupIDs(ids)
+	perShard := make(map[int][]GetResultItem, len(byShard))
+	var mu sync.Mutex
+	err := s.fanOut(ctx, keysOf(byShard), func(ctx context.Context, i int) error {
+		resp, err := s.shards[i].Get(ctx, byShard[i], include)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		perShard[i] = resp.Results
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]GetResultItem, len(ids))
+	for _, results := range perShard {
+		for _, item := range results {
+			byID[item.Id] = item
+		}
+	}
+	out := &GetResponse{Results: make([]GetResultItem, 0, len(byID))}
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			out.Results = append(out.Results, item)
+			delete(byID, id)
+		}
+	}
+	return out, nil
+}
+
+// Delete removes items by ID from their shards.
+func (s *ShardedIndex) Delete(ctx context.Context, ids []string) error {
+	byShard := s.groupIDs(ids)
+	return s.fanOut(ctx, keysOf(byShard), func(ctx context.Context, i int) error {
+		return s.shards[i].Delete(ctx, byShard[i])
+	})
+}
+
+// Train trains every shard concurrently with params.
+func (s *ShardedIndex) Train(ctx context.Context, params TrainParams) error {
+	return s.fanOut(ctx, s.all(), func(ctx context.Context, i int) error {
+		return s.shards[i].Train(ctx, params)
+	})
+}
+
+// groupIDs splits ids by owning shard.
+func (s *ShardedIndex) groupIDs(ids []string) map[int][]string {
+	byShard := make(map[int][]string)
+	for _, id := range ids {
+		i := s.ShardFor(id)
+		byShard[i] = append(byShard[i], id)
+	}
+	return byShard
+}
+
+// all returns every shard position.
+func (s *ShardedIndex) all() []int {
+	out := make([]int, len(s.shards))
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+// fanOut runs fn for each shard position concurrently. The first error
+// cancels the others and is returned, naming its shard.
+func (s *ShardedIndex) fanOut(ctx context.Context, shards []int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for _, i := range shards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := fn(ctx, i); err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("shard %q: %w", s.shards[i].GetIndexName(), err)
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// keysOf returns the shard positions in m, in ascending order.
+func keysOf[V any](m map[int]V) []int {
+	out := make([]int, 0, len(m))
+	for i := range m {
+		out = append(out, i)
+	}
+	sort.Ints(out)
+	return out
+}
@@ -0,0 +1,119 @@
+// slowop.go implements logging of requests that exceed a latency threshold.
+package cyborgdb
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SlowOp describes a request that took longer than the slow-operation
+// threshold.
+type SlowOp struct {
+	// Time is when the request started.
+	Time time.Time
+	// Operation is the endpoint, e.g. "vectors/query" or "indexes/train".
+	Operation string
+	// Index is the index name from the request body, if any.
+	Index string
+	// PayloadBytes is the size of the request body, -1 if unknown.
+	PayloadBytes int64
+	// Duration is how long the request took, including retries and waits
+	// for the rate limiter.
+	Duration time.Duration
+	// Status is the HTTP status code, 0 if no response was received.
+	Status int
+	// Err is the transport error, nil if a response was received.
+	Err error
+}
+
+// WithSlowOpThreshold reports every request to the service that takes at
+// least d. By default each one is written to the standard logger as a
+// structured warning:
+//
+//	cyborgdb: slow operation op=vectors/query index="docs" bytes=6210 duration=2.4s status=200
+//
+// Use WithSlowOpHandler to send them elsewhere. Zero or negative disables it.
+func WithSlowOpThreshold(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.slowOpThreshold = d }
+}
+
+// WithSlowOpHandler sets the function called for each slow request instead
+// of logging it. It is called synchronously, possibly from many goroutines at
+// once. It has no effect without WithSlowOpThreshold.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithSlowOpThreshold(time.Second),
+//		cyborgdb.WithSlowOpHandler(func(op cyborgdb.SlowOp) {
+//			metrics.SlowOps.WithLabelValues(op.Operation).Inc()
+//		}))
+func WithSlowOpHandler(handler func(SlowOp)) ClientOption {
+	return func(o *clientOptions) { o.slowOpHandler = handler }
+}
+
+// logSlowOp writes op to the standard logger.
+func logSlowOp(op SlowOp) {
+	if op.Err != nil {
+		log.Printf("cyborgdb: slow operation op=%s index=%q bytes=%d duration=%s error=%q",
+			op.Operation, op.Index, op.PayloadBytes, op.Duration, op.Err)
+		return
+	}
+	log.Printf("cyborgdb: slow operation op=%s index=%q bytes=%d duration=%s status=%d",
+		op.Operation, op.Index, op.PayloadBytes, op.Duration, op.Status)
+}
+
+// slowOpTransport reports requests slower than threshold.
+type slowOpTransport struct {
+	next      http.RoundTripper
+	threshold time.Duration
+	handler   func(SlowOp)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *slowOpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+	if elapsed < t.threshold {
+		return resp, err
+	}
+
+	op := SlowOp{
+		Time:         start,
+		Operation:    strings.TrimPrefix(req.URL.Path, "/v1/"),
+		Index:        requestIndexName(req),
+		PayloadBytes: req.ContentLength,
+		Duration:     elapsed,
+		Err:          err,
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		op.PayloadBytes = 0
+	}
+	if resp != nil {
+		op.Status = resp.StatusCode
+	}
+	t.handler(op)
+	return resp, err
+}
+
+// requestIndexName reads index_name from a fresh copy of the JSON request
+// body, or returns "" if there is none.
+func requestIndexName(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	var payload struct {
+		IndexName string `json:"index_name"`
+	}
+	_ = json.NewDecoder(body).Decode(&payload)
+	return payload.IndexName
+}
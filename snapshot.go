@@ -0,0 +1,377 @@
+// snapshot.go implements encrypted snapshots of an entire index.
+//
+// A snapshot is a versioned archive of the index configuration and every
+// item (vector, metadata, contents). Because the items are decrypted by the
+// service when read, the archive is encrypted client-side with AES-256-GCM
+// under a key derived from the index key, so a snapshot is no less protected
+// at rest than the index itself.
+//
+// Layout: the magic "CYBORGDB-SNAPSHOT", a format version byte, then frames of
+// [4-byte big-endian length][12-byte nonce][ciphertext]. The first frame is
+// the manifest, the middle frames hold batches of records, and the last frame
+// is a trailer with the record count, so truncation is detected. Each frame's
+// sequence number is authenticated, so frames cannot be reordered or dropped.
+package cyborgdb
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+const (
+	// SnapshotVersion is the snapshot format version written by CreateSnapshot.
+	SnapshotVersion = 1
+	// snapshotMagic starts every snapshot.
+	snapshotMagic = "CYBORGDB-SNAPSHOT"
+	// snapshotBatchSize is the number of records per snapshot frame.
+	snapshotBatchSize = 500
+	// maxSnapshotFrame bounds a frame's size when reading, to reject corrupt input early.
+	maxSnapshotFrame = 256 << 20
+)
+
+var (
+	// ErrInvalidSnapshot is returned when a snapshot is malformed, truncated,
+	// or cannot be decrypted with the given key.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	// ErrUnsupportedSnapshotVersion is returned for snapshots written by a newer SDK.
+	ErrUnsupportedSnapshotVersion = errors.New("unsupported snapshot version")
+)
+
+// SnapshotManifest describes the index a snapshot was taken from.
+type SnapshotManifest struct {
+	Version     int                    `json:"version"`
+	IndexName   string                 `json:"index_name"`
+	IndexType   string                 `json:"index_type"`
+	IndexConfig map[string]interface{} `json:"index_config,omitempty"`
+	Metric      string                 `json:"metric,omitempty"`
+	Trained     bool                   `json:"trained"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// snapshotTrailer ends a snapshot.
+type snapshotTrailer struct {
+	Count int `json:"count"`
+}
+
+// snapshotFrame is the plaintext of a record or trailer frame.
+type snapshotFrame struct {
+	Records []Record         `json:"records,omitempty"`
+	Trailer *snapshotTrailer `json:"trailer,omitempty"`
+}
+
+// CreateSnapshot writes an encrypted archive of the index configuration and
+// all items to w, for disaster recovery and environment cloning.
+//
+// The archive is encrypted under the index key; restore it with
+// Client.RestoreSnapshot using the same key.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - w: Destination for the archive
+//
+// Returns:
+//   - int: Number of items written
+//   - error: Any error encountered; a partial archive fails to restore
+//
+// Example:
+//
+//	f, _ := os.Create("index.snap")
+//	defer f.Close()
+//	n, err := index.CreateSnapshot(ctx, f)
+func (e *EncryptedIndex) CreateSnapshot(ctx context.Context, w io.Writer) (int, error) {
+	key, err := hex.DecodeString(e.indexKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode index key: %w", err)
+	}
+	defer Zeroize(key)
+	sw, err := newSnapshotWriter(w, key)
+	if err != nil {
+		return 0, err
+	}
+
+	manifest := SnapshotManifest{
+		Version:   SnapshotVersion,
+		IndexName: e.indexName,
+		IndexType: e.GetIndexType(),
+		Metric:    e.GetMetric(),
+		Trained:   e.IsTrained(),
+		CreatedAt: time.Now().UTC(),
+	}
+	if config := e.cachedConfig(); config != nil {
+		if manifest.IndexConfig, err = configToMap(config); err != nil {
+			return 0, err
+		}
+	}
+	if err := sw.writeJSON(manifest); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	batch := make([]Record, 0, snapshotBatchSize)
+	it := e.Scan(ctx, ScanOptions{Include: copyInclude})
+	for it.Next() {
+		item := it.Item()
+		rec := Record{ID: item.Id, Vector: item.Vector, Metadata: item.Metadata}
+		if text, ok := contentsText(item.Contents); ok {
+			rec.Contents = &text
+		}
+		batch = append(batch, rec)
+		if len(batch) == snapshotBatchSize {
+			if err := sw.writeJSON(snapshotFrame{Records: batch}); err != nil {
+				return count, err
+			}
+			count += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := it.Err(); err != nil {
+		return count, fmt.Errorf("failed to read items for snapshot: %w", err)
+	}
+	if len(batch) > 0 {
+		if err := sw.writeJSON(snapshotFrame{Records: batch}); err != nil {
+			return count, err
+		}
+		count += len(batch)
+	}
+
+	if err := sw.writeJSON(snapshotFrame{Trailer: &snapshotTrailer{Count: count}}); err != nil {
+		return count, err
+	}
+	return count, sw.flush()
+}
+
+// RestoreSnapshot creates a new index from a snapshot written by CreateSnapshot.
+//
+// params names the new index and supplies the key, which must be the key of
+// the index the snapshot was taken from (use RotateIndexKey afterwards to
+// re-key). If params.IndexConfig or params.Metric are unset, the snapshot's
+// values are used. The index is trained after restoring if the source was.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - r: Source of the archive
+//   - params: Parameters for the new index
+//
+// Returns:
+//   - *EncryptedIndex: Handle for the restored index
+//   - error: Any error encountered; ErrInvalidSnapshot for a corrupt archive or wrong key
+func (c *Client) RestoreSnapshot(ctx context.Context, r io.Reader, params *CreateIndexParams) (*EncryptedIndex, error) {
+	p := *params
+	if len(p.IndexKey) == 0 && p.KeyProvider != nil {
+		key, err := resolveKey(ctx, p.KeyProvider, p.IndexName)
+		if err != nil {
+			return nil, err
+		}
+		defer Zeroize(key)
+		p.IndexKey = key
+	}
+	if len(p.IndexKey) != KeySize {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidKeyLength, len(p.IndexKey))
+	}
+
+	sr, err := newSnapshotReader(r, p.IndexKey)
+	if err != nil {
+		return nil, err
+	}
+	var manifest SnapshotManifest
+	if err := sr.readJSON(&manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version > SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSnapshotVersion, manifest.Version)
+	}
+
+	if p.IndexConfig == nil {
+		if config := indexConfigFromMap(manifest.IndexType, manifest.IndexConfig); config != nil {
+			p.IndexConfig = indexModelFromConfig(config)
+		}
+	}
+	if p.Metric == nil && manifest.Metric != "" {
+		metric := manifest.Metric
+		p.Metric = &metric
+	}
+
+	dst, err := c.CreateIndex(ctx, &p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index for restore: %w", err)
+	}
+
+	restored := 0
+	for {
+		var frame snapshotFrame
+		if err := sr.readJSON(&frame); err != nil {
+			return dst, err
+		}
+		if frame.Trailer != nil {
+			if frame.Trailer.Count != restored {
+				return dst, fmt.Errorf("%w: trailer count %d, restored %d", ErrInvalidSnapshot, frame.Trailer.Count, restored)
+			}
+			break
+		}
+
+		items := make([]VectorItem, len(frame.Records))
+		for i, rec := range frame.Records {
+			items[i] = VectorItem{Id: rec.ID, Vector: rec.Vector, Metadata: rec.Metadata}
+			if rec.Contents != nil {
+				items[i].Contents = TextContents(*rec.Contents)
+			}
+		}
+		if err := dst.Upsert(ctx, items); err != nil {
+			return dst, fmt.Errorf("failed to restore items: %w", err)
+		}
+		restored += len(items)
+	}
+
+	if manifest.Trained && restored > 0 {
+		if err := dst.Train(ctx, TrainParams{}); err != nil {
+			return dst, fmt.Errorf("restore succeeded but training failed: %w", err)
+		}
+	}
+	return dst, nil
+}
+
+// configToMap converts an index configuration to its JSON object form.
+func configToMap(config *internal.IndexConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode index config: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to encode index config: %w", err)
+	}
+	return m, nil
+}
+
+// snapshotAEAD derives the snapshot encryption key from the index key.
+func snapshotAEAD(indexKey []byte) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, indexKey)
+	mac.Write([]byte("cyborgdb-snapshot-v1"))
+	key := mac.Sum(nil)
+	defer Zeroize(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// snapshotAAD binds a frame to its position in the archive.
+func snapshotAAD(seq uint64) []byte {
+	aad := make([]byte, len(snapshotMagic)+1+8)
+	copy(aad, snapshotMagic)
+	aad[len(snapshotMagic)] = SnapshotVersion
+	binary.BigEndian.PutUint64(aad[len(snapshotMagic)+1:], seq)
+	return aad
+}
+
+// snapshotWriter writes encrypted frames.
+type snapshotWriter struct {
+	w    *bufio.Writer
+	aead cipher.AEAD
+	seq  uint64
+}
+
+func newSnapshotWriter(w io.Writer, indexKey []byte) (*snapshotWriter, error) {
+	aead, err := snapshotAEAD(indexKey)
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(snapshotMagic); err != nil {
+		return nil, err
+	}
+	if err := bw.WriteByte(SnapshotVersion); err != nil {
+		return nil, err
+	}
+	return &snapshotWriter{w: bw, aead: aead}, nil
+}
+
+// writeJSON encrypts v as the next frame.
+func (s *snapshotWriter) writeJSON(v interface{}) error {
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot frame: %w", err)
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := s.aead.Seal(nonce, nonce, plain, snapshotAAD(s.seq))
+	s.seq++
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := s.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err = s.w.Write(sealed)
+	return err
+}
+
+func (s *snapshotWriter) flush() error { return s.w.Flush() }
+
+// snapshotReader reads and decrypts frames.
+type snapshotReader struct {
+	r    *bufio.Reader
+	aead cipher.AEAD
+	seq  uint64
+}
+
+func newSnapshotReader(r io.Reader, indexKey []byte) (*snapshotReader, error) {
+	aead, err := snapshotAEAD(indexKey)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(r)
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidSnapshot)
+	}
+	if version := header[len(snapshotMagic)]; version > SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSnapshotVersion, version)
+	}
+	return &snapshotReader{r: br, aead: aead}, nil
+}
+
+// readJSON decrypts the next frame into v.
+func (s *snapshotReader) readJSON(v interface{}) error {
+	var length [4]byte
+	if _, err := io.ReadFull(s.r, length[:]); err != nil {
+		return fmt.Errorf("%w: truncated", ErrInvalidSnapshot)
+	}
+	n := binary.BigEndian.Uint32(length[:])
+	if n < uint32(s.aead.NonceSize()+s.aead.Overhead()) || n > maxSnapshotFrame {
+		return fmt.Errorf("%w: bad frame length %d", ErrInvalidSnapshot, n)
+	}
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(s.r, sealed); err != nil {
+		return fmt.Errorf("%w: truncated", ErrInvalidSnapshot)
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, snapshotAAD(s.seq))
+	if err != nil {
+		return fmt.Errorf("%w: decryption failed (wrong key or corrupt data)", ErrInvalidSnapshot)
+	}
+	s.seq++
+	if err := json.Unmarshal(plain, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	return nil
+}
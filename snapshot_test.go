@@ -0,0 +1,101 @@
+package cyborgdb_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// takeSnapshot fills a fake "docs" index and returns its snapshot.
+func takeSnapshot(t *testing.T) (*fakeService, *cyborgdb.Client, []byte) {
+	t.Helper()
+	fake, client := newFakeService(t)
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{
+		{Id: "a", Vector: []float32{1, 0}, Metadata: map[string]interface{}{"year": 2020, "tags": []interface{}{"x", "y"}}},
+		{Id: "b", Vector: []float32{0, 1}, Metadata: map[string]interface{}{"year": 2021}},
+		{Id: "c", Vector: []float32{0.5, 0.5}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := index.CreateSnapshot(ctx, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("CreateSnapshot wrote %d items, want 3", n)
+	}
+	return fake, client, buf.Bytes()
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	fake, client, snap := takeSnapshot(t)
+	ctx := context.Background()
+
+	restored, err := client.RestoreSnapshot(ctx, bytes.NewReader(snap), &cyborgdb.CreateIndexParams{
+		IndexName: "docs-restored",
+		IndexKey:  testKey(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.GetIndexName() != "docs-restored" {
+		t.Errorf("restored index name = %q", restored.GetIndexName())
+	}
+	if got, want := fake.index("docs-restored").items, fake.index("docs").items; !reflect.DeepEqual(got, want) {
+		t.Errorf("restored items = %v, want %v", got, want)
+	}
+}
+
+func TestRestoreSnapshotRejectsWrongKey(t *testing.T) {
+	fake, client, snap := takeSnapshot(t)
+	_, err := client.RestoreSnapshot(context.Background(), bytes.NewReader(snap), &cyborgdb.CreateIndexParams{
+		IndexName: "docs-restored",
+		IndexKey:  testKey(2),
+	})
+	if !errors.Is(err, cyborgdb.ErrInvalidSnapshot) {
+		t.Errorf("err = %v, want ErrInvalidSnapshot", err)
+	}
+	if fake.index("docs-restored") != nil {
+		t.Error("an index was created for a snapshot that could not be decrypted")
+	}
+}
+
+func TestRestoreSnapshotRejectsCorruptInput(t *testing.T) {
+	_, client, snap := takeSnapshot(t)
+	ctx := context.Background()
+
+	cases := map[string][]byte{
+		"empty":          nil,
+		"bad magic":      append([]byte("NOT-A-SNAPSHOT!!!"), snap[len("CYBORGDB-SNAPSHOT"):]...),
+		"header only":    snap[:len("CYBORGDB-SNAPSHOT")+1],
+		"cut mid-frame":  snap[:len(snap)/2],
+		"missing byte":   snap[:len(snap)-1],
+		"flipped header": flipByte(snap, len("CYBORGDB-SNAPSHOT")+6),
+		"flipped body":   flipByte(snap, len(snap)/2),
+		"flipped tail":   flipByte(snap, len(snap)-1),
+	}
+	for name, data := range cases {
+		_, err := client.RestoreSnapshot(ctx, bytes.NewReader(data), &cyborgdb.CreateIndexParams{
+			IndexName: "restored-" + strings.ReplaceAll(name, " ", "-"),
+			IndexKey:  testKey(1),
+		})
+		if !errors.Is(err, cyborgdb.ErrInvalidSnapshot) {
+			t.Errorf("%s: err = %v, want ErrInvalidSnapshot", name, err)
+		}
+	}
+}
+
+// flipByte returns a copy of data with the byte at i inverted.
+func flipByte(data []byte, i int) []byte {
+	out := append([]byte(nil), data...)
+	out[i] ^= 0xff
+	return out
+}
@@ -0,0 +1,200 @@
+// sparse.go adds sparse vectors (index/value pairs, e.g. SPLADE or BM25
+// weights) alongside dense vectors, for hybrid lexical-semantic retrieval on
+// servers that support them.
+package cyborgdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+var (
+	// ErrSparseUnsupported is returned when sparse vectors are sent to a
+	// server that does not advertise sparse support.
+	ErrSparseUnsupported = fmt.Errorf("server does not support sparse vectors")
+	// ErrInvalidSparseVector is returned for a malformed sparse vector.
+	ErrInvalidSparseVector = fmt.Errorf("invalid sparse vector")
+)
+
+// SparseVector is a sparse vector given by the positions and values of its
+// non-zero entries.
+type SparseVector struct {
+	// Indices are the positions of the non-zero entries, strictly increasing.
+	Indices []uint32 `json:"indices"`
+	// Values are the entries at Indices.
+	Values []float32 `json:"values"`
+}
+
+// Validate checks that Indices and Values have the same length and Indices
+// are strictly increasing.
+func (s *SparseVector) Validate() error {
+	if len(s.Indices) != len(s.Values) {
+		return fmt.Errorf("%w: %d indices but %d values", ErrInvalidSparseVector, len(s.Indices), len(s.Values))
+	}
+	for i := 1; i < len(s.Indices); i++ {
+		if s.Indices[i] <= s.Indices[i-1] {
+			return fmt.Errorf("%w: indices must be strictly increasing", ErrInvalidSparseVector)
+		}
+	}
+	return nil
+}
+
+// SparseVectorItem is a VectorItem that may also carry a sparse vector.
+type SparseVectorItem struct {
+	VectorItem
+	// Sparse is the item's sparse vector, or nil.
+	Sparse *SparseVector
+}
+
+// UpsertSparse upserts items that may carry sparse vectors. Items without
+// sparse vectors are upserted as with Upsert; if any item has one and the
+// server does not advertise sparse support, ErrSparseUnsupported is returned
+// and nothing is written.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - items: Items with dense and optional sparse vectors
+//
+// Returns:
+//   - error: Any error encountered
+//
+// Example:
+//
+//	err := index.UpsertSparse(ctx, []cyborgdb.SparseVectorItem{{
+//		VectorItem: cyborgdb.VectorItem{Id: "doc1", Vector: dense},
+//		Sparse:     &cyborgdb.SparseVector{Indices: []uint32{12, 4051}, Values: []float32{0.8, 0.3}},
+//	}})
+func (e *EncryptedIndex) UpsertSparse(ctx context.Context, items []SparseVectorItem) error {
+	dense := make([]VectorItem, len(items))
+	hasSparse := false
+	for i, item := range items {
+		dense[i] = item.VectorItem
+		if item.Sparse != nil {
+			if err := item.Sparse.Validate(); err != nil {
+				return fmt.Errorf("item %q: %w", item.Id, err)
+			}
+			hasSparse = true
+		}
+	}
+	if !hasSparse {
+		return e.Upsert(ctx, dense)
+	}
+	dense, err := e.stampTimestamps(ctx, dense)
+	if err != nil {
+		return err
+	}
+	if err := e.opts.validateItemsMetadata(dense); err != nil {
+		return err
+	}
+	if err := e.validateSchema(ctx, dense); err != nil {
+		return err
+	}
+	if dense, err = e.hashItemsMetadata(dense); err != nil {
+		return err
+	}
+	if dense, err = e.encryptItemsMetadata(dense); err != nil {
+		return err
+	}
+	if err := e.requireSparse(ctx); err != nil {
+		return err
+	}
+
+	defer e.InvalidateQueryCache()
+
+	ctx, cancel := withDefaultTimeout(ctx, e.opts.upsertTimeout)
+	defer cancel()
+
+	dense, err = e.embedItems(ctx, dense)
+	if err != nil {
+		return err
+	}
+	body := make([]map[string]interface{}, len(dense))
+	for i, item := range dense {
+		m, err := item.ToMap()
+		if err != nil {
+			return err
+		}
+		if items[i].Sparse != nil {
+			m["sparse_vector"] = items[i].Sparse
+		}
+		body[i] = m
+	}
+
+	var resp internal.CyborgdbServiceApiSchemasVectorsSuccessResponseModel
+	start := time.Now()
+	err = e.client.PostJSON(ctx, "DefaultAPIService.UpsertVectorsV1VectorsUpsertPost", "/v1/vectors/upsert",
+		map[string]interface{}{"index_name": e.indexName, "index_key": e.indexKey, "items": body}, &resp)
+	e.opts.audit(ctx, AuditUpsert, e.indexName, len(items), start, err)
+	if err != nil {
+		return err
+	}
+	triggered := resp.HasTrainingTriggered() && resp.GetTrainingTriggered()
+	if triggered {
+		e.setTrained(false)
+	}
+	e.observeUpsert(ctx, len(items), triggered)
+	return nil
+}
+
+// WithSparseVector adds a sparse query vector. See QueryParams.SparseVector.
+func WithSparseVector(sparse SparseVector) QueryOption {
+	return func(p *QueryParams) { p.SparseVector = &sparse }
+}
+
+// querySparse sends a single query carrying a sparse vector.
+func (e *EncryptedIndex) querySparse(ctx context.Context, params QueryParams) (*QueryResponse, error) {
+	if len(params.BatchQueryVectors) > 0 {
+		return nil, fmt.Errorf("%w: sparse vectors are not supported in batch queries", ErrInvalidSparseVector)
+	}
+	if err := params.SparseVector.Validate(); err != nil {
+		return nil, err
+	}
+	if err := e.requireSparse(ctx); err != nil {
+		return nil, err
+	}
+
+	body, err := e.newQueryRequest(params).ToMap()
+	if err != nil {
+		return nil, err
+	}
+	body["sparse_vector"] = params.SparseVector
+
+	var raw bytes.Buffer
+	start := time.Now()
+	_, err = e.client.PostJSONRaw(ctx, "DefaultAPIService.QueryVectorsV1VectorsQueryPost", "/v1/vectors/query", body, &raw)
+	if err != nil {
+		return nil, err
+	}
+	var resp QueryResponse
+	if err := json.Unmarshal(raw.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
+	}
+	queryTraceFrom(ctx).request(start, &resp)
+	if err := redecodeMetadataBody(e.opts.numberDecoding, raw.Bytes(), queryMetadataSetter(&resp)); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// requireSparse returns ErrSparseUnsupported unless the server advertises
+// sparse support. The answer is cached on the client after the first check.
+func (e *EncryptedIndex) requireSparse(ctx context.Context) error {
+	supported, known := e.client.SparseSupport()
+	if !known {
+		raw, err := e.client.GetHealth(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to check sparse vector support: %w", err)
+		}
+		supported = newCapabilities(newHealthResponse(raw)).SparseVectors
+		e.client.SetSparseSupport(supported)
+	}
+	if !supported {
+		return ErrSparseUnsupported
+	}
+	return nil
+}
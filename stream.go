@@ -0,0 +1,153 @@
+// stream.go implements continuous upserts from a channel of items.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultStreamBatchSize is the default maximum number of items per upsert in UpsertStream.
+	DefaultStreamBatchSize = 500
+	// DefaultStreamFlushInterval is the default longest time an item waits in a
+	// partial batch before UpsertStream sends it.
+	DefaultStreamFlushInterval = time.Second
+)
+
+// UpsertStreamOptions configures UpsertStream. Zero values fall back to defaults.
+type UpsertStreamOptions struct {
+	// BatchSize is the maximum number of items sent per upsert request. Default: 500.
+	BatchSize int
+
+	// FlushInterval bounds how long a partial batch is held before it is sent,
+	// so slow producers still see their items indexed promptly. Default: 1s.
+	FlushInterval time.Duration
+
+	// OnBatch, if set, is called after each batch is upserted with the batch
+	// size and the running total.
+	OnBatch func(batch, total int)
+}
+
+// UpsertStreamError reports a failed batch and how many items were upserted before it.
+type UpsertStreamError struct {
+	// Upserted is the number of items successfully upserted before the failure.
+	Upserted int
+	// Batch holds the items of the failed batch, so the caller can retry them.
+	Batch []VectorItem
+	// State is BatchFailed or BatchAborted if Batch was sent, or BatchSkipped
+	// if ctx was done while Batch was still buffered.
+	State BatchState
+	// Err is the underlying upsert error.
+	Err error
+}
+
+func (e *UpsertStreamError) Error() string {
+	return fmt.Sprintf("upsert stream failed after %d items: %v", e.Upserted, e.Err)
+}
+
+func (e *UpsertStreamError) Unwrap() error {
+	return e.Err
+}
+
+// UpsertStream consumes items from the channel and upserts them in
+// micro-batches until the channel is closed or ctx is done.
+//
+// A batch is sent when it reaches BatchSize items or when FlushInterval has
+// passed since its first item arrived. Items are not read from the channel
+// while a batch is being sent, so a slow server applies backpressure to the
+// producer. On success the remaining partial batch is flushed once the
+// channel closes.
+//
+// Batches are sent one at a time, so after a failure or cancellation the
+// first Upserted items read from the channel are committed and the rest are
+// not, except the error's Batch when its State is BatchAborted.
+//
+// Parameters:
+//   - ctx: Context for cancellation; items buffered when it is done are not
+//     sent, and are returned in an *UpsertStreamError
+//   - items: Source of items; the caller closes it to finish the stream
+//   - opts: Batch size, flush interval, and progress options
+//
+// Returns:
+//   - int: The number of items upserted
+//   - error: An *UpsertStreamError if a batch failed or was left unsent, or
+//     ctx.Err() if cancelled with nothing buffered
+//
+// Example:
+//
+//	items := make(chan cyborgdb.VectorItem, 1000)
+//	go consumeFromQueue(items) // closes items when done
+//	n, err := index.UpsertStream(ctx, items, cyborgdb.UpsertStreamOptions{})
+func (e *EncryptedIndex) UpsertStream(ctx context.Context, items <-chan VectorItem, opts UpsertStreamOptions) (int, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultStreamBatchSize
+	}
+	flushInterval := opts.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = DefaultStreamFlushInterval
+	}
+
+	var (
+		total  int
+		batch  = make([]VectorItem, 0, batchSize)
+		timer  *time.Timer
+		timerC <-chan time.Time
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	flush := func() error {
+		if timer != nil {
+			timer.Stop()
+			timer, timerC = nil, nil
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := e.Upsert(ctx, batch); err != nil {
+			return &UpsertStreamError{Upserted: total, Batch: batch, State: batchState(err), Err: err}
+		}
+		total += len(batch)
+		if opts.OnBatch != nil {
+			opts.OnBatch(len(batch), total)
+		}
+		batch = make([]VectorItem, 0, batchSize)
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if len(batch) > 0 {
+				return total, &UpsertStreamError{Upserted: total, Batch: batch, State: BatchSkipped, Err: ctx.Err()}
+			}
+			return total, ctx.Err()
+
+		case <-timerC:
+			timer, timerC = nil, nil
+			if err := flush(); err != nil {
+				return total, err
+			}
+
+		case item, ok := <-items:
+			if !ok {
+				return total, flush()
+			}
+			batch = append(batch, item)
+			if len(batch) == 1 {
+				timer = time.NewTimer(flushInterval)
+				timerC = timer.C
+			}
+			if len(batch) >= batchSize {
+				if err := flush(); err != nil {
+					return total, err
+				}
+			}
+		}
+	}
+}
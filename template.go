@@ -0,0 +1,121 @@
+// template.go implements provisioning many identically configured indexes,
+// such as one index per tenant.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultCreateIndexesConcurrency is the number of indexes
+// CreateIndexesFromTemplate creates at once.
+const DefaultCreateIndexesConcurrency = 4
+
+// CreateIndexesFromTemplate creates one index per name, all configured like
+// template, with up to DefaultCreateIndexesConcurrency creations in flight.
+// template.IndexName is ignored. Set template.KeyProvider to give each index
+// its own key; template.IndexKey is shared by all of them otherwise.
+//
+// Creation is all or nothing: if any index fails, no further creations
+// start, the ones in flight finish, and every index created is deleted again.
+// In-flight creations are not canceled, since the server may already have
+// applied them.
+//
+// Parameters:
+//   - ctx: Context for cancellation/timeouts
+//   - template: Configuration shared by every index
+//   - names: Names of the indexes to create (must be unique)
+//
+// Returns:
+//   - []*EncryptedIndex: Handles in the order of names
+//   - error: The first creation error, with any rollback failures
+//
+// Example:
+//
+//	indexes, err := client.CreateIndexesFromTemplate(ctx, &cyborgdb.CreateIndexParams{
+//		KeyProvider: provider,
+//		IndexConfig: cyborgdb.IndexIVFFlat(768),
+//	}, []string{"tenant-a", "tenant-b", "tenant-c"})
+func (c *Client) CreateIndexesFromTemplate(ctx context.Context, template *CreateIndexParams, names []string) ([]*EncryptedIndex, error) {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate index name %q", name)
+		}
+		seen[name] = true
+	}
+
+	indexes := make([]*EncryptedIndex, len(names))
+	next := make(chan int)
+	stop := make(chan struct{})
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < DefaultCreateIndexesConcurrency && w < len(names); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				params := *template
+				params.IndexName = names[i]
+				index, err := c.CreateIndex(ctx, &params)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("failed to create index %q: %w", names[i], err)
+						close(stop)
+					})
+				}
+				// CreateIndex can return a handle with an error when only
+				// the catalog update failed; the index exists either way.
+				indexes[i] = index
+			}
+		}()
+	}
+
+feed:
+	for i := range names {
+		select {
+		case next <- i:
+		case <-stop:
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr == nil {
+		return indexes, nil
+	}
+	return nil, c.rollbackIndexes(ctx, indexes, firstErr)
+}
+
+// rollbackIndexes deletes the created indexes after cause, and returns cause
+// annotated with any deletions that failed.
+func (c *Client) rollbackIndexes(ctx context.Context, indexes []*EncryptedIndex, cause error) error {
+	if ctx.Err() != nil {
+		// Still clean up when the caller's context is what failed.
+		ctx = context.Background()
+	}
+	var failed []string
+	for _, index := range indexes {
+		if index == nil {
+			continue
+		}
+		if err := index.DeleteIndex(ctx); err != nil {
+			failed = append(failed, index.GetIndexName())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w; rollback failed, indexes left behind: %q", cause, failed)
+	}
+	return cause
+}
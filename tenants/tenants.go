@@ -0,0 +1,258 @@
+// Package tenants maps tenant IDs to per-tenant encrypted indexes, for SaaS
+// applications that isolate each customer's vectors under its own key.
+//
+// A Manager derives each tenant's index name from its ID, resolves the
+// tenant's key through a cyborgdb.KeyProvider, and hands out a Tenant handle
+// scoped to that index. The index is created lazily on the tenant's first
+// write; reads from a tenant without an index return no results.
+package tenants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// DefaultIndexPrefix prefixes tenant index names when Options.IndexName is
+// not set.
+const DefaultIndexPrefix = "tenant_"
+
+// ErrInvalidTenantID is returned for an empty tenant ID or one containing
+// characters other than letters, digits, '-', and '_'.
+var ErrInvalidTenantID = errors.New("tenants: invalid tenant ID")
+
+// Options configures a Manager. Zero values fall back to defaults.
+type Options struct {
+	// Template configures the indexes created for tenants. Its IndexName,
+	// IndexKey, and KeyProvider are ignored. Default: server defaults.
+	Template cyborgdb.CreateIndexParams
+
+	// IndexName maps a tenant ID to its index name. It must be one-to-one
+	// and, for ListTenants, reversible by TenantID. Default: DefaultIndexPrefix
+	// followed by the ID.
+	IndexName func(tenantID string) string
+
+	// TenantID maps an index name back to a tenant ID, reporting false for
+	// indexes that do not belong to a tenant. Default: strips
+	// DefaultIndexPrefix.
+	TenantID func(indexName string) (string, bool)
+}
+
+// Manager hands out Tenant handles. It is safe for concurrent use.
+type Manager struct {
+	client *cyborgdb.Client
+	keys   cyborgdb.KeyProvider
+	opts   Options
+
+	mu      sync.Mutex
+	tenants map[string]*Tenant
+}
+
+// New returns a Manager that stores tenant indexes on client and resolves
+// tenant keys through keys, which is called with each tenant's index name.
+//
+// Example:
+//
+//	mgr := tenants.New(client, provider, tenants.Options{
+//		Template: cyborgdb.CreateIndexParams{IndexConfig: cyborgdb.IndexIVFFlat(768)},
+//	})
+//	acme, err := mgr.Tenant("acme")
+//	err = acme.Upsert(ctx, items) // creates tenant_acme on first write
+//	results, err := acme.Query(ctx, vec, cyborgdb.WithTopK(5))
+func New(client *cyborgdb.Client, keys cyborgdb.KeyProvider, opts Options) *Manager {
+	if opts.IndexName == nil {
+		opts.IndexName = func(id string) string { return DefaultIndexPrefix + id }
+	}
+	if opts.TenantID == nil {
+		opts.TenantID = func(name string) (string, bool) {
+			id := strings.TrimPrefix(name, DefaultIndexPrefix)
+			return id, id != name && id != ""
+		}
+	}
+	return &Manager{client: client, keys: keys, opts: opts, tenants: make(map[string]*Tenant)}
+}
+
+// Tenant returns the handle for tenantID. It does not contact the service;
+// the tenant's index is looked up on first use.
+func (m *Manager) Tenant(tenantID string) (*Tenant, error) {
+	if err := validateTenantID(tenantID); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tenants[tenantID]
+	if !ok {
+		t = &Tenant{manager: m, id: tenantID, indexName: m.opts.IndexName(tenantID)}
+		m.tenants[tenantID] = t
+	}
+	return t, nil
+}
+
+// ListTenants returns the IDs of tenants that have an index.
+func (m *Manager) ListTenants(ctx context.Context) ([]string, error) {
+	names, err := m.client.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, name := range names {
+		if id, ok := m.opts.TenantID(name); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// DeleteTenant permanently deletes the tenant's index and all its vectors.
+// Deleting a tenant without an index is not an error.
+func (m *Manager) DeleteTenant(ctx context.Context, tenantID string) error {
+	t, err := m.Tenant(tenantID)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	index, err := t.loadLocked(ctx)
+	if err != nil || index == nil {
+		return err
+	}
+	if err := index.DeleteIndex(ctx); err != nil {
+		return fmt.Errorf("tenants: failed to delete index for %q: %w", tenantID, err)
+	}
+	t.index = nil
+	return nil
+}
+
+// Tenant is a handle scoped to one tenant's index. It is safe for concurrent
+// use.
+type Tenant struct {
+	manager   *Manager
+	id        string
+	indexName string
+
+	mu sync.Mutex
+	// index is the loaded or created index, nil until it is known to exist.
+	index *cyborgdb.EncryptedIndex
+}
+
+// ID returns the tenant ID.
+func (t *Tenant) ID() string { return t.id }
+
+// IndexName returns the name of the tenant's index.
+func (t *Tenant) IndexName() string { return t.indexName }
+
+// Index returns the tenant's index, or nil if it has not been created yet.
+func (t *Tenant) Index(ctx context.Context) (*cyborgdb.EncryptedIndex, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.loadLocked(ctx)
+}
+
+// Upsert writes items to the tenant's index, creating it first if needed.
+func (t *Tenant) Upsert(ctx context.Context, items []cyborgdb.VectorItem) error {
+	index, err := t.ensureIndex(ctx)
+	if err != nil {
+		return err
+	}
+	return index.Upsert(ctx, items)
+}
+
+// Query searches the tenant's index. A tenant without an index has no
+// results.
+func (t *Tenant) Query(ctx context.Context, vector []float32, opts ...cyborgdb.QueryOption) ([]cyborgdb.QueryResult, error) {
+	index, err := t.Index(ctx)
+	if err != nil || index == nil {
+		return []cyborgdb.QueryResult{}, err
+	}
+	return index.QueryOne(ctx, vector, opts...)
+}
+
+// Get retrieves items by ID from the tenant's index.
+func (t *Tenant) Get(ctx context.Context, ids []string, include []string) (*cyborgdb.GetResponse, error) {
+	index, err := t.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if index == nil {
+		return &cyborgdb.GetResponse{Results: []cyborgdb.GetResultItem{}}, nil
+	}
+	return index.Get(ctx, ids, include)
+}
+
+// Delete removes items by ID from the tenant's index.
+func (t *Tenant) Delete(ctx context.Context, ids []string) error {
+	index, err := t.Index(ctx)
+	if err != nil || index == nil {
+		return err
+	}
+	return index.Delete(ctx, ids)
+}
+
+// ensureIndex returns the tenant's index, creating it if it does not exist.
+func (t *Tenant) ensureIndex(ctx context.Context) (*cyborgdb.EncryptedIndex, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	index, err := t.loadLocked(ctx)
+	if err != nil || index != nil {
+		return index, err
+	}
+
+	params := t.manager.opts.Template
+	params.IndexName = t.indexName
+	params.IndexKey = nil
+	params.KeyProvider = t.manager.keys
+	index, err = t.manager.client.CreateIndex(ctx, &params)
+	if err != nil {
+		// Another process may have created it first.
+		if loaded, loadErr := t.manager.client.LoadIndexWithKeyProvider(ctx, t.indexName, t.manager.keys); loadErr == nil {
+			index, err = loaded, nil
+		} else {
+			return nil, fmt.Errorf("tenants: failed to create index for %q: %w", t.id, err)
+		}
+	}
+	t.index = index
+	return index, nil
+}
+
+// loadLocked returns the tenant's index, loading it on first use, or nil if
+// it does not exist. A missing index is looked up again on every call, so
+// one created by another process is found. t.mu must be held.
+func (t *Tenant) loadLocked(ctx context.Context) (*cyborgdb.EncryptedIndex, error) {
+	if t.index != nil {
+		return t.index, nil
+	}
+	names, err := t.manager.client.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if name == t.indexName {
+			index, err := t.manager.client.LoadIndexWithKeyProvider(ctx, t.indexName, t.manager.keys)
+			if err != nil {
+				return nil, fmt.Errorf("tenants: failed to load index for %q: %w", t.id, err)
+			}
+			t.index = index
+			break
+		}
+	}
+	return t.index, nil
+}
+
+// validateTenantID checks that id can be embedded in an index name.
+func validateTenantID(id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidTenantID)
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidTenantID, id, r)
+		}
+	}
+	return nil
+}
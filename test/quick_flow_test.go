@@ -313,7 +313,7 @@ func TestUnitFlow(t *testing.T) {
 		if err != nil {
 			t.Errorf("Failed to get health: %v", err)
 		}
-		if health["status"] != "healthy" {
+		if !health.IsHealthy() {
 			t.Errorf("API is not healthy: %v", health)
 		}
 	})
@@ -996,8 +996,7 @@ func TestUnitFlow(t *testing.T) {
 		}
 
 		config := index.GetIndexConfig()
-		// Check if config is empty (all fields are nil)
-		if config.IndexIVFFlatModel == nil && config.IndexIVFModel == nil && config.IndexIVFPQModel == nil {
+		if config.IsZero() {
 			t.Errorf("Index config is empty")
 		}
 	})
@@ -1013,5 +1012,20 @@ func TestUnitFlow(t *testing.T) {
 		if loadedName != indexName {
 			t.Errorf("Loaded index name does not match: expected %s, got %s", indexName, loadedName)
 		}
+
+		loadedConfig := loadedIndex.GetIndexConfig()
+		if loadedConfig.Type != "ivfflat" {
+			t.Fatalf("Loaded index config is not IVFFlat: %+v", loadedConfig)
+		}
+		if loadedDim := loadedConfig.Dimension; loadedDim != int32(dimension) {
+			t.Errorf("Loaded index dimension does not match: expected %d, got %d", dimension, loadedDim)
+		}
+		if loadedIndex.IsTrained() != index.IsTrained() {
+			t.Errorf("Loaded index trained state does not match: expected %v, got %v", index.IsTrained(), loadedIndex.IsTrained())
+		}
+
+		if err := loadedIndex.RefreshInfo(ctx); err != nil {
+			t.Errorf("Failed to refresh index info: %v", err)
+		}
 	})
 }
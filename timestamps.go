@@ -0,0 +1,186 @@
+// timestamps.go implements optional created_at/updated_at system metadata
+// stamped on upsert, with filter and sort helpers for freshness-aware
+// retrieval.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// System metadata keys written by WithTimestamps. Values are Unix times in
+// seconds, so they support numeric range filters.
+const (
+	MetadataCreatedAt = "created_at"
+	MetadataUpdatedAt = "updated_at"
+)
+
+// TimestampOptions configures WithTimestamps. Zero values fall back to
+// defaults.
+type TimestampOptions struct {
+	// Clock returns the current time. Default: time.Now.
+	Clock func() time.Time
+
+	// SkipLookup stamps created_at on items that do not carry one without
+	// first fetching the stored created_at of existing items. It saves a
+	// Get per upsert when items are known to be new, or when callers pass
+	// created_at through themselves.
+	SkipLookup bool
+}
+
+// WithTimestamps stamps MetadataUpdatedAt on every upserted item and
+// MetadataCreatedAt on items upserted for the first time. An item's existing
+// created_at is kept: from its metadata if the caller supplied one, otherwise
+// fetched from the index, so created_at survives read-modify-write cycles and
+// full overwrites alike. The caller's items are not modified. Internal
+// indexes (alias registry, catalog, schema indexes) are never stamped.
+//
+// Example:
+//
+//	client, err := cyborgdb.NewClientWithOptions(url, apiKey,
+//		cyborgdb.WithTimestamps(cyborgdb.TimestampOptions{}))
+//	...
+//	results, err := index.QueryOne(ctx, vec,
+//		cyborgdb.WithFilters(cyborgdb.UpdatedAfter(time.Now().Add(-7*24*time.Hour))),
+//		cyborgdb.WithInclude("metadata"))
+func WithTimestamps(opts TimestampOptions) ClientOption {
+	return func(o *clientOptions) {
+		if opts.Clock == nil {
+			opts.Clock = time.Now
+		}
+		o.timestamps = &opts
+	}
+}
+
+// preserveTimestampsKey is the context key set by WithPreservedTimestamps.
+type preserveTimestampsKey struct{}
+
+// WithPreservedTimestamps returns a context whose upserts store items'
+// created_at and updated_at exactly as given, without stamping, so copies of
+// an index keep their items' history. RotateIndexKey, CloneIndex, and
+// migrate.CopyIndex use it.
+func WithPreservedTimestamps(ctx context.Context) context.Context {
+	return context.WithValue(ctx, preserveTimestampsKey{}, true)
+}
+
+// stampTimestamps returns items with system timestamps set, copying every
+// item's metadata. It returns items unchanged when timestamps are disabled,
+// preserved by ctx, or the index is internal, such as the alias registry.
+func (e *EncryptedIndex) stampTimestamps(ctx context.Context, items []VectorItem) ([]VectorItem, error) {
+	ts := e.opts.timestamps
+	if ts == nil || len(items) == 0 || isInternalIndex(e.indexName) {
+		return items, nil
+	}
+	if preserve, _ := ctx.Value(preserveTimestampsKey{}).(bool); preserve {
+		return items, nil
+	}
+	now := ts.Clock().Unix()
+
+	existing := make(map[string]interface{})
+	if !ts.SkipLookup {
+		var lookup []string
+		for _, item := range items {
+			if _, ok := item.Metadata[MetadataCreatedAt]; !ok {
+				lookup = append(lookup, item.Id)
+			}
+		}
+		if len(lookup) > 0 {
+			resp, err := e.Get(ctx, lookup, []string{"metadata"})
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up created_at: %w", err)
+			}
+			for _, item := range resp.Results {
+				if created, ok := item.Metadata[MetadataCreatedAt]; ok {
+					existing[item.Id] = created
+				}
+			}
+		}
+	}
+
+	out := make([]VectorItem, len(items))
+	copy(out, items)
+	for i := range out {
+		meta := make(map[string]interface{}, len(out[i].Metadata)+2)
+		for k, v := range out[i].Metadata {
+			meta[k] = v
+		}
+		if _, ok := meta[MetadataCreatedAt]; !ok {
+			if created, ok := existing[out[i].Id]; ok {
+				meta[MetadataCreatedAt] = created
+			} else {
+				meta[MetadataCreatedAt] = now
+			}
+		}
+		meta[MetadataUpdatedAt] = now
+		out[i].Metadata = meta
+	}
+	return out, nil
+}
+
+// MetadataTime reads a Unix-seconds timestamp such as MetadataCreatedAt
+// from metadata.
+//
+// Returns:
+//   - time.Time: The timestamp
+//   - bool: false if the key is missing or not a number
+func MetadataTime(metadata map[string]interface{}, key string) (time.Time, bool) {
+	n, ok := filterNumber(metadata[key])
+	if !ok {
+		return time.Time{}, false
+	}
+	sec, frac := math.Modf(n)
+	return time.Unix(int64(sec), int64(frac*1e9)), true
+}
+
+// CreatedAfter returns a filter matching items created at or after t.
+func CreatedAfter(t time.Time) map[string]interface{} {
+	return timeFilter(MetadataCreatedAt, "$gte", t)
+}
+
+// CreatedBefore returns a filter matching items created before t.
+func CreatedBefore(t time.Time) map[string]interface{} {
+	return timeFilter(MetadataCreatedAt, "$lt", t)
+}
+
+// UpdatedAfter returns a filter matching items updated at or after t.
+func UpdatedAfter(t time.Time) map[string]interface{} {
+	return timeFilter(MetadataUpdatedAt, "$gte", t)
+}
+
+// UpdatedBefore returns a filter matching items last updated before t.
+func UpdatedBefore(t time.Time) map[string]interface{} {
+	return timeFilter(MetadataUpdatedAt, "$lt", t)
+}
+
+func timeFilter(key, op string, t time.Time) map[string]interface{} {
+	return map[string]interface{}{key: map[string]interface{}{op: t.Unix()}}
+}
+
+// SortByCreatedAt orders results by MetadataCreatedAt, newest first if
+// newestFirst, keeping the existing order among equal times. Results
+// without the timestamp, e.g. because "metadata" was not included, go last.
+func SortByCreatedAt(results []QueryResult, newestFirst bool) {
+	sortByTime(results, MetadataCreatedAt, newestFirst)
+}
+
+// SortByUpdatedAt orders results by MetadataUpdatedAt like SortByCreatedAt.
+func SortByUpdatedAt(results []QueryResult, newestFirst bool) {
+	sortByTime(results, MetadataUpdatedAt, newestFirst)
+}
+
+func sortByTime(results []QueryResult, key string, newestFirst bool) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, aok := MetadataTime(results[i].Metadata, key)
+		b, bok := MetadataTime(results[j].Metadata, key)
+		if !aok || !bok {
+			return aok && !bok
+		}
+		if newestFirst {
+			return a.After(b)
+		}
+		return a.Before(b)
+	})
+}
@@ -0,0 +1,71 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+// steppedClock returns a clock reading *now, which tests advance.
+func steppedClock(now *time.Time) func() time.Time {
+	return func() time.Time { return *now }
+}
+
+func TestCopiesPreserveTimestamps(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	_, client := newFakeService(t, cyborgdb.WithTimestamps(cyborgdb.TimestampOptions{Clock: steppedClock(&now)}))
+	ctx := context.Background()
+	index := createFakeIndex(t, client, "docs", testKey(1))
+	if err := index.Upsert(ctx, []cyborgdb.VectorItem{{Id: "a", Vector: []float32{1, 0}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(24 * time.Hour)
+	clone, err := client.CloneIndex(ctx, "docs", "docs-copy", testKey(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := client.RotateIndexKey(ctx, "docs", testKey(1), testKey(2), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, idx := range map[string]*cyborgdb.EncryptedIndex{"clone": clone, "rotated": rotated} {
+		resp, err := idx.Get(ctx, []string{"a"}, []string{"metadata"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Results) != 1 {
+			t.Fatalf("%s: got %d items, want 1", name, len(resp.Results))
+		}
+		for _, key := range []string{cyborgdb.MetadataCreatedAt, cyborgdb.MetadataUpdatedAt} {
+			got, ok := cyborgdb.MetadataTime(resp.Results[0].Metadata, key)
+			if !ok || got.Unix() != 1700000000 {
+				t.Errorf("%s: %s = %v, want the original time", name, key, got)
+			}
+		}
+	}
+}
+
+func TestTimestampsSkipInternalIndexes(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	fake, client := newFakeService(t, cyborgdb.WithTimestamps(cyborgdb.TimestampOptions{Clock: steppedClock(&now)}))
+	ctx := context.Background()
+	registry, err := client.AliasRegistry(ctx, testKey(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.CreateAlias(ctx, "docs", "docs_v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, _ := fake.item(cyborgdb.AliasRegistryIndexName, "docs")["metadata"].(map[string]interface{})
+	if _, ok := meta["updated_at"].(string); !ok {
+		t.Errorf("alias updated_at = %#v, want the registry's RFC 3339 string", meta["updated_at"])
+	}
+	if _, ok := meta[cyborgdb.MetadataCreatedAt]; ok {
+		t.Errorf("alias item was stamped: %v", meta)
+	}
+}
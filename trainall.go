@@ -0,0 +1,118 @@
+// trainall.go implements training many indexes at once, such as one index
+// per tenant.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTrainAllConcurrency is the number of indexes TrainAll trains at once
+// when concurrency is not positive.
+const DefaultTrainAllConcurrency = 4
+
+// IndexRef names an index and the key that opens it.
+type IndexRef struct {
+	// Name is the index name.
+	Name string
+	// Key is the 32-byte index key.
+	Key []byte
+}
+
+// TrainAllResult is the outcome of training one index.
+type TrainAllResult struct {
+	// Index is the index name.
+	Index string
+	// State is TrainingSucceeded or TrainingFailed, or TrainingCanceled if
+	// ctx was done before the index was trained.
+	State TrainingState
+	// Duration is how long loading and training the index took.
+	Duration time.Duration
+	// Err is the load or training error.
+	Err error
+}
+
+// TrainAll trains every index in indexes with params, running up to
+// concurrency trainings at once. A failure does not stop the others; each
+// index gets its own result.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - indexes: The indexes to train
+//   - params: Training options applied to every index, as for Train
+//   - concurrency: Maximum trainings in flight (DefaultTrainAllConcurrency if <= 0)
+//
+// Returns:
+//   - []TrainAllResult: One result per index, in input order
+//   - error: nil, or a summary of the indexes that failed
+//
+// Example:
+//
+//	refs := []cyborgdb.IndexRef{{Name: "tenant-a", Key: keyA}, {Name: "tenant-b", Key: keyB}}
+//	results, err := client.TrainAll(ctx, refs, cyborgdb.TrainParams{}, 8)
+//	for _, r := range results {
+//		log.Printf("%s: %s in %s", r.Index, r.State, r.Duration)
+//	}
+func (c *Client) TrainAll(ctx context.Context, indexes []IndexRef, params TrainParams, concurrency int) ([]TrainAllResult, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultTrainAllConcurrency
+	}
+
+	results := make([]TrainAllResult, len(indexes))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(indexes); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = c.trainOne(ctx, indexes[i], params)
+			}
+		}()
+	}
+
+	for i := range indexes {
+		if ctx.Err() != nil {
+			results[i] = TrainAllResult{Index: indexes[i].Name, State: TrainingCanceled, Err: ctx.Err()}
+			continue
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var failed []TrainAllResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("%d of %d indexes failed to train; first: %q: %w",
+			len(failed), len(results), failed[0].Index, failed[0].Err)
+	}
+	return results, nil
+}
+
+// trainOne loads and trains one index for TrainAll.
+func (c *Client) trainOne(ctx context.Context, ref IndexRef, params TrainParams) TrainAllResult {
+	result := TrainAllResult{Index: ref.Name, State: TrainingSucceeded}
+	start := time.Now()
+
+	index, err := c.LoadIndex(ctx, ref.Name, ref.Key)
+	if err == nil {
+		err = index.Train(ctx, params)
+	}
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		result.State, result.Err = TrainingCanceled, err
+	default:
+		result.State, result.Err = TrainingFailed, err
+	}
+	result.Duration = time.Since(start)
+	return result
+}
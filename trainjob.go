@@ -0,0 +1,224 @@
+// trainjob.go implements asynchronous training with status, progress, and
+// cancellation.
+package cyborgdb
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TrainingState is the state of a TrainingJob.
+type TrainingState int
+
+const (
+	// TrainingRunning means the train request has not finished yet.
+	TrainingRunning TrainingState = iota
+	// TrainingSucceeded means training completed.
+	TrainingSucceeded
+	// TrainingFailed means the train request returned an error.
+	TrainingFailed
+	// TrainingCanceled means the job was canceled before it finished.
+	TrainingCanceled
+)
+
+// String returns the state name.
+func (s TrainingState) String() string {
+	switch s {
+	case TrainingRunning:
+		return "running"
+	case TrainingSucceeded:
+		return "succeeded"
+	case TrainingFailed:
+		return "failed"
+	case TrainingCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// TrainingProgress reports how far training has advanced, when the server
+// publishes it in its training status.
+type TrainingProgress struct {
+	// Iteration is the current training iteration.
+	Iteration int `json:"iteration"`
+	// MaxIters is the iteration limit.
+	MaxIters int `json:"max_iters"`
+	// Convergence is the latest change in the objective; training stops once
+	// it falls below the tolerance.
+	Convergence float64 `json:"convergence"`
+	// Reported is false if the server published no progress for this index;
+	// the other fields are then zero.
+	Reported bool `json:"reported"`
+}
+
+// TrainingStatus is a snapshot of a TrainingJob.
+type TrainingStatus struct {
+	// State is the job state.
+	State TrainingState
+	// ServerTraining reports whether the server lists the index as training.
+	ServerTraining bool
+	// Progress is the server-reported progress, if any.
+	Progress TrainingProgress
+	// Started is when the job started.
+	Started time.Time
+	// Err is the training error for TrainingFailed.
+	Err error
+}
+
+// TrainingJob is a handle to training started by TrainAsync.
+type TrainingJob struct {
+	index   *EncryptedIndex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	started time.Time
+
+	mu       sync.Mutex
+	state    TrainingState
+	err      error
+	canceled bool
+}
+
+// TrainAsync starts training in the background and returns immediately with
+// a handle to observe, wait for, or cancel it. The job runs until it
+// finishes, Cancel is called, or ctx is done.
+//
+// Parameters:
+//   - ctx: Context bounding the training request
+//   - params: Training options, as for Train
+//
+// Returns:
+//   - *TrainingJob: Handle for the running job
+//
+// Example:
+//
+//	job := index.TrainAsync(ctx, cyborgdb.TrainParams{})
+//	for {
+//		st, _ := job.Status(ctx)
+//		if st.State != cyborgdb.TrainingRunning {
+//			break
+//		}
+//		log.Printf("training: iteration %d/%d", st.Progress.Iteration, st.Progress.MaxIters)
+//		time.Sleep(5 * time.Second)
+//	}
+//	err := job.Wait(ctx)
+func (e *EncryptedIndex) TrainAsync(ctx context.Context, params TrainParams) *TrainingJob {
+	ctx, cancel := context.WithCancel(ctx)
+	job := &TrainingJob{
+		index:   e,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		started: time.Now(),
+	}
+
+	go func() {
+		defer close(job.done)
+		defer cancel()
+		err := e.Train(ctx, params)
+
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		switch {
+		case job.canceled:
+			job.state = TrainingCanceled
+			job.err = context.Canceled
+		case err != nil:
+			job.state = TrainingFailed
+			job.err = err
+		default:
+			job.state = TrainingSucceeded
+		}
+	}()
+	return job
+}
+
+// Done returns a channel closed when the job finishes.
+func (j *TrainingJob) Done() <-chan struct{} { return j.done }
+
+// Status returns the job state, and while it runs, whether the server lists
+// the index as training along with any progress it reports.
+func (j *TrainingJob) Status(ctx context.Context) (TrainingStatus, error) {
+	j.mu.Lock()
+	st := TrainingStatus{State: j.state, Started: j.started, Err: j.err}
+	j.mu.Unlock()
+	if st.State != TrainingRunning {
+		return st, nil
+	}
+
+	statusMap, err := j.index.trainingStatus(ctx)
+	if err != nil {
+		return st, err
+	}
+	if training, ok := statusMap["training_indexes"].([]interface{}); ok {
+		for _, name := range training {
+			if name == j.index.indexName {
+				st.ServerTraining = true
+				break
+			}
+		}
+	}
+	st.Progress = parseTrainingProgress(statusMap, j.index.indexName)
+	return st, nil
+}
+
+// Progress returns the server-reported training progress, if any.
+func (j *TrainingJob) Progress(ctx context.Context) (TrainingProgress, error) {
+	st, err := j.Status(ctx)
+	return st.Progress, err
+}
+
+// Wait blocks until the job finishes or ctx is done, and returns the
+// training error, if any.
+func (j *TrainingJob) Wait(ctx context.Context) error {
+	select {
+	case <-j.done:
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		return j.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Cancel aborts the train request and waits for the job to stop.
+//
+// The service has no endpoint to stop training, so training already running
+// on the server may continue to completion; use Status to observe it. The
+// index is unchanged if training had not started.
+func (j *TrainingJob) Cancel(ctx context.Context) error {
+	j.mu.Lock()
+	if j.state == TrainingRunning {
+		j.canceled = true
+	}
+	j.mu.Unlock()
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseTrainingProgress reads per-index progress from the training status,
+// accepting {"progress": {"<index>": {"iteration": .., "max_iters": ..,
+// "convergence": ..}}}.
+func parseTrainingProgress(statusMap map[string]interface{}, indexName string) TrainingProgress {
+	all, _ := statusMap["progress"].(map[string]interface{})
+	p, ok := all[indexName].(map[string]interface{})
+	if !ok {
+		return TrainingProgress{}
+	}
+	number := func(key string) float64 {
+		f, _ := p[key].(float64)
+		return f
+	}
+	return TrainingProgress{
+		Iteration:   int(number("iteration")),
+		MaxIters:    int(number("max_iters")),
+		Convergence: number("convergence"),
+		Reported:    true,
+	}
+}
@@ -0,0 +1,267 @@
+// transport.go builds the HTTP transport used by a Client.
+package cyborgdb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConns is the default total number of idle connections kept open.
+	DefaultMaxIdleConns = 100
+	// DefaultMaxIdleConnsPerHost is the default number of idle connections kept
+	// open to the CyborgDB service. net/http defaults to 2, which forces
+	// concurrent callers to re-dial constantly.
+	DefaultMaxIdleConnsPerHost = 32
+	// DefaultIdleConnTimeout is how long an idle connection is kept before closing.
+	DefaultIdleConnTimeout = 90 * time.Second
+	// DefaultKeepAlive is the TCP keep-alive period for service connections.
+	DefaultKeepAlive = 30 * time.Second
+	// DefaultDialTimeout bounds how long establishing a connection may take.
+	DefaultDialTimeout = 30 * time.Second
+)
+
+// DialContextFunc dials a network connection; see net.Dialer.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// transportOptions holds the connection settings applied by ClientOption values.
+type transportOptions struct {
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleConnTimeout     time.Duration
+	keepAlive           time.Duration
+	enableHTTP2         bool
+	dialContext         DialContextFunc
+	proxy               func(*http.Request) (*url.URL, error)
+	tlsConfig           *tls.Config
+	caCerts             [][]byte
+	httpClient          *http.Client
+}
+
+// defaultTransportOptions returns the connection settings used when none are given.
+func defaultTransportOptions() transportOptions {
+	return transportOptions{
+		maxIdleConns:        DefaultMaxIdleConns,
+		maxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		idleConnTimeout:     DefaultIdleConnTimeout,
+		keepAlive:           DefaultKeepAlive,
+		enableHTTP2:         true,
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections kept open
+// across all hosts. Zero means no limit.
+func WithMaxIdleConns(n int) ClientOption {
+	return func(o *clientOptions) { o.transport.maxIdleConns = n }
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections kept
+// open to the service. Raise it to roughly the number of concurrent callers.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(o *clientOptions) { o.transport.maxIdleConnsPerHost = n }
+}
+
+// WithMaxConnsPerHost caps the total number of connections (idle, active, and
+// dialing) to the service. Zero means no limit.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(o *clientOptions) { o.transport.maxConnsPerHost = n }
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before closing.
+// Zero means no limit.
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.transport.idleConnTimeout = d }
+}
+
+// WithKeepAlive sets the TCP keep-alive period. A negative value disables
+// keep-alive probes. Ignored when WithDialContext is used.
+func WithKeepAlive(d time.Duration) ClientOption {
+	return func(o *clientOptions) { o.transport.keepAlive = d }
+}
+
+// WithHTTP2 enables or disables HTTP/2 negotiation over TLS. It is enabled by
+// default; plain "http://" URLs always use HTTP/1.1.
+func WithHTTP2(enabled bool) ClientOption {
+	return func(o *clientOptions) { o.transport.enableHTTP2 = enabled }
+}
+
+// WithDialContext sets a custom dialer, e.g. for Unix sockets or instrumented
+// connections.
+func WithDialContext(dial DialContextFunc) ClientOption {
+	return func(o *clientOptions) { o.transport.dialContext = dial }
+}
+
+var (
+	// ErrInvalidCACert is returned when a PEM block passed to WithCACert holds no certificates.
+	ErrInvalidCACert = fmt.Errorf("no certificates found in CA PEM data")
+	// ErrCACertWithRootCAs is returned when WithCACert is combined with a
+	// WithTLSConfig whose RootCAs is set; add the certificates to that pool instead.
+	ErrCACertWithRootCAs = fmt.Errorf("WithCACert cannot be combined with a TLS config that sets RootCAs")
+	// ErrInvalidProxyURL is returned when the URL passed to WithProxy is invalid.
+	ErrInvalidProxyURL = fmt.Errorf("invalid proxy URL")
+)
+
+// WithProxy routes service requests through the proxy at proxyURL, e.g.
+// "http://proxy.corp.example:3128". An invalid URL makes client construction
+// fail.
+func WithProxy(proxyURL string) ClientOption {
+	return func(o *clientOptions) {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			o.setErr(fmt.Errorf("%w: %v", ErrInvalidProxyURL, err))
+			return
+		}
+		o.transport.proxy = http.ProxyURL(u)
+	}
+}
+
+// WithCACert trusts the PEM-encoded CA certificates in pem in addition to the
+// system roots, for services using a private CA. It may be given more than once.
+func WithCACert(pem []byte) ClientOption {
+	return func(o *clientOptions) { o.transport.caCerts = append(o.transport.caCerts, pem) }
+}
+
+// WithTLSConfig uses cfg as the base TLS configuration, e.g. for client
+// certificates or a custom RootCAs pool. The config is cloned and a false
+// WithVerifySSL is applied on top of it. WithCACert applies too unless cfg
+// sets RootCAs, in which case client construction fails with
+// ErrCACertWithRootCAs; add the certificates to that pool instead.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(o *clientOptions) { o.transport.tlsConfig = cfg }
+}
+
+// withProxyFunc routes service requests through the proxy chosen by fn.
+func withProxyFunc(fn func(*http.Request) (*url.URL, error)) ClientOption {
+	return func(o *clientOptions) { o.transport.proxy = fn }
+}
+
+// WithHTTPClient sends requests through the given http.Client. The connection
+// options above, including WithVerifySSL, are ignored; request options such
+// as WithCompression still apply.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) { o.transport.httpClient = client }
+}
+
+// newHTTPClient builds the http.Client described by o, wrapping its transport
+// with the request middleware (per-call headers, credentials, compression,
+// rate limiting) that o enables.
+func newHTTPClient(o *clientOptions, verifySSL bool) (*http.Client, error) {
+	var client http.Client
+	if o.transport.httpClient != nil {
+		client = *o.transport.httpClient
+	} else {
+		transport, err := newTransport(o.transport, verifySSL)
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = transport
+	}
+	if client.Transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+
+	client.Transport = &headerTransport{next: client.Transport}
+	if o.credentials != nil {
+		client.Transport = &credentialsTransport{next: client.Transport, creds: o.credentials}
+	}
+	// Inside compression, so limits apply to the body as sent.
+	client.Transport = &payloadTransport{next: client.Transport, limits: o.payloadLimits}
+
+	if o.compression != CompressionNone {
+		compress, err := lookupCompressor(o.compression)
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = &compressingTransport{
+			next:      client.Transport,
+			encoding:  o.compression,
+			compress:  compress,
+			threshold: DefaultCompressionThreshold,
+		}
+	}
+	if o.rateLimit > 0 {
+		client.Transport = &rateLimitTransport{
+			next:    client.Transport,
+			bucket:  newTokenBucket(o.rateLimit, o.rateBurst),
+			retries: DefaultRateLimitRetries,
+		}
+	}
+	if o.slowOpThreshold > 0 {
+		handler := o.slowOpHandler
+		if handler == nil {
+			handler = logSlowOp
+		}
+		client.Transport = &slowOpTransport{next: client.Transport, threshold: o.slowOpThreshold, handler: handler}
+	}
+	return &client, nil
+}
+
+// newTransport builds the http.Transport described by t.
+func newTransport(t transportOptions, verifySSL bool) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(t, verifySSL)
+	if err != nil {
+		return nil, err
+	}
+
+	dial := t.dialContext
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   DefaultDialTimeout,
+			KeepAlive: t.keepAlive,
+		}).DialContext
+	}
+
+	transport := &http.Transport{
+		Proxy:               t.proxy,
+		DialContext:         dial,
+		TLSClientConfig:     tlsConfig,
+		ForceAttemptHTTP2:   t.enableHTTP2,
+		MaxIdleConns:        t.maxIdleConns,
+		MaxIdleConnsPerHost: t.maxIdleConnsPerHost,
+		MaxConnsPerHost:     t.maxConnsPerHost,
+		IdleConnTimeout:     t.idleConnTimeout,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if !t.enableHTTP2 {
+		// A non-nil empty map disables HTTP/2 upgrades.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport, nil
+}
+
+// newTLSConfig builds the TLS configuration described by t.
+func newTLSConfig(t transportOptions, verifySSL bool) (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if t.tlsConfig != nil {
+		cfg = t.tlsConfig.Clone()
+	}
+	if !verifySSL {
+		cfg.InsecureSkipVerify = true
+	}
+
+	if len(t.caCerts) > 0 {
+		// A caller's pool cannot be copied before Go 1.19 and must not be
+		// modified in place, so the certificates go into a fresh system pool.
+		if cfg.RootCAs != nil {
+			return nil, ErrCACertWithRootCAs
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		for _, pem := range t.caCerts {
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, ErrInvalidCACert
+			}
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
@@ -0,0 +1,44 @@
+package cyborgdb_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cyborgdb "github.com/cyborginc/cyborgdb-go"
+)
+
+func TestCACertTrustsServer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"indexes":["docs"]}`))
+	}))
+	defer srv.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	client, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithCACert(caPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListIndexes(context.Background()); err != nil {
+		t.Errorf("request to a server signed by the CA: %v", err)
+	}
+
+	if _, err := cyborgdb.NewClientWithOptions(srv.URL, "key", cyborgdb.WithCACert([]byte("not a certificate"))); !errors.Is(err, cyborgdb.ErrInvalidCACert) {
+		t.Errorf("invalid PEM: err = %v, want ErrInvalidCACert", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	_, err = cyborgdb.NewClientWithOptions(srv.URL, "key",
+		cyborgdb.WithTLSConfig(&tls.Config{RootCAs: pool}),
+		cyborgdb.WithCACert(caPEM))
+	if !errors.Is(err, cyborgdb.ErrCACertWithRootCAs) {
+		t.Errorf("WithCACert with RootCAs: err = %v, want ErrCACertWithRootCAs", err)
+	}
+}
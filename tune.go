@@ -0,0 +1,218 @@
+// tune.go implements auto-tuning of query parameters on a live index.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// defaultTuneTopK is the TopK used by Tune when none is given.
+	defaultTuneTopK = 10
+	// defaultMaxNProbes bounds the sweep when the index's n_lists is unknown.
+	defaultMaxNProbes = 256
+)
+
+var (
+	// ErrNoSampleQueries is returned when Tune is called without sample queries.
+	ErrNoSampleQueries = fmt.Errorf("at least one sample query is required")
+	// ErrInvalidTargetRecall is returned when the target recall is outside (0, 1].
+	ErrInvalidTargetRecall = fmt.Errorf("target recall must be in (0, 1]")
+)
+
+// TunePoint is one measured n_probes setting in a Tune sweep.
+type TunePoint struct {
+	// NProbes is the number of IVF lists probed.
+	NProbes int32 `json:"n_probes"`
+	// Recall is the mean recall against the exhaustive reference results.
+	Recall float64 `json:"recall"`
+	// MeanLatency is the mean per-query latency.
+	MeanLatency time.Duration `json:"mean_latency"`
+}
+
+// TuneResult holds recommended query defaults and index build suggestions.
+type TuneResult struct {
+	// NProbes is the smallest swept n_probes reaching the target recall, or
+	// the best-recall setting if none did.
+	NProbes int32 `json:"n_probes"`
+	// Recall is the recall achieved at NProbes.
+	Recall float64 `json:"recall"`
+	// MeanLatency is the mean per-query latency at NProbes.
+	MeanLatency time.Duration `json:"mean_latency"`
+	// TargetMet reports whether Recall reached the requested target.
+	TargetMet bool `json:"target_met"`
+	// Sweep lists every measured setting in increasing n_probes order.
+	Sweep []TunePoint `json:"sweep"`
+
+	// SuggestedNLists is a rule-of-thumb n_lists (about 4·√N) for the current
+	// vector count, for use when retraining.
+	SuggestedNLists int32 `json:"suggested_n_lists"`
+	// SuggestedPQDim and SuggestedPQBits are IVFPQ suggestions, set only for
+	// IVFPQ indexes with a known dimension.
+	SuggestedPQDim  int32 `json:"suggested_pq_dim,omitempty"`
+	SuggestedPQBits int32 `json:"suggested_pq_bits,omitempty"`
+}
+
+// Options returns the recommended settings as query options.
+func (r *TuneResult) Options() []QueryOption {
+	return []QueryOption{WithNProbes(r.NProbes)}
+}
+
+// Tune sweeps n_probes on the live index and recommends the cheapest setting
+// that reaches targetRecall.
+//
+// Reference results are obtained by probing every list (an exhaustive search),
+// and recall for each swept setting is measured against them. Tune issues
+// roughly len(sampleQueries) × log2(n_lists) queries, so keep the sample small
+// (tens to a few hundred queries).
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - sampleQueries: Representative query vectors
+//   - targetRecall: Desired recall in (0, 1], e.g. 0.95
+//   - opts: Base query options (TopK defaults to 10; filters are honored)
+//
+// Returns:
+//   - *TuneResult: Recommended n_probes, the measured sweep, and build suggestions
+//   - error: Any error encountered
+//
+// Example:
+//
+//	result, err := index.Tune(ctx, samples, 0.95, cyborgdb.WithTopK(20))
+//	hits, err := index.QueryOne(ctx, q, append(result.Options(), cyborgdb.WithTopK(20))...)
+func (e *EncryptedIndex) Tune(
+	ctx context.Context,
+	sampleQueries [][]float32,
+	targetRecall float64,
+	opts ...QueryOption,
+) (*TuneResult, error) {
+	if len(sampleQueries) == 0 {
+		return nil, ErrNoSampleQueries
+	}
+	if targetRecall <= 0 || targetRecall > 1 {
+		return nil, ErrInvalidTargetRecall
+	}
+
+	base := QueryParams{TopK: defaultTuneTopK}
+	for _, opt := range opts {
+		opt(&base)
+	}
+	base.Include = nil
+
+	if e.GetNLists() == 0 {
+		_ = e.RefreshInfo(ctx)
+	}
+	maxProbes := e.GetNLists()
+	if maxProbes <= 0 {
+		maxProbes = defaultMaxNProbes
+	}
+
+	reference, _, err := e.tuneRun(ctx, sampleQueries, base, maxProbes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute reference results: %w", err)
+	}
+
+	result := &TuneResult{}
+	var best *TunePoint
+	for nProbes := int32(1); ; nProbes *= 2 {
+		if nProbes > maxProbes {
+			nProbes = maxProbes
+		}
+
+		results, latency, err := e.tuneRun(ctx, sampleQueries, base, nProbes)
+		if err != nil {
+			return nil, err
+		}
+		point := TunePoint{NProbes: nProbes, Recall: tuneRecall(reference, results), MeanLatency: latency}
+		result.Sweep = append(result.Sweep, point)
+
+		if best == nil || point.Recall > best.Recall {
+			p := point
+			best = &p
+		}
+		if point.Recall >= targetRecall {
+			best = &point
+			result.TargetMet = true
+			break
+		}
+		if nProbes == maxProbes {
+			break
+		}
+	}
+
+	result.NProbes = best.NProbes
+	result.Recall = best.Recall
+	result.MeanLatency = best.MeanLatency
+	e.suggestBuildParams(ctx, result)
+	return result, nil
+}
+
+// tuneRun runs every sample query at the given n_probes, returning the
+// result IDs per query and the mean latency.
+func (e *EncryptedIndex) tuneRun(ctx context.Context, queries [][]float32, base QueryParams, nProbes int32) ([][]string, time.Duration, error) {
+	ids := make([][]string, len(queries))
+	var total time.Duration
+	for i, q := range queries {
+		params := base
+		params.QueryVector = q
+		params.NProbes = &nProbes
+
+		start := time.Now()
+		resp, err := e.Query(ctx, params)
+		total += time.Since(start)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		flat := flattenQueryResults(resp, e.GetMetric())
+		if len(flat) > 0 {
+			for _, r := range flat[0] {
+				ids[i] = append(ids[i], r.ID)
+			}
+		}
+	}
+	return ids, total / time.Duration(len(queries)), nil
+}
+
+// tuneRecall is the mean fraction of reference IDs found in results.
+func tuneRecall(reference, results [][]string) float64 {
+	var sum float64
+	for i, ref := range reference {
+		if len(ref) == 0 {
+			sum++
+			continue
+		}
+		found := make(map[string]struct{}, len(results[i]))
+		for _, id := range results[i] {
+			found[id] = struct{}{}
+		}
+		hits := 0
+		for _, id := range ref {
+			if _, ok := found[id]; ok {
+				hits++
+			}
+		}
+		sum += float64(hits) / float64(len(ref))
+	}
+	return sum / float64(len(reference))
+}
+
+// suggestBuildParams fills in rule-of-thumb n_lists and PQ settings.
+func (e *EncryptedIndex) suggestBuildParams(ctx context.Context, result *TuneResult) {
+	if listed, err := e.ListIDs(ctx); err == nil && listed.Count > 0 {
+		result.SuggestedNLists = int32(math.Max(1, math.Round(4*math.Sqrt(float64(listed.Count)))))
+	}
+
+	if e.GetIndexType() == "ivfpq" {
+		if dim := e.configDimension(); dim > 0 {
+			pqDim := dim / 8
+			if pqDim < 1 {
+				pqDim = 1
+			}
+			result.SuggestedPQDim = pqDim
+			result.SuggestedPQBits = 8
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// typed.go implements TypedIndex, a generic wrapper that converts metadata
+// to and from a user-defined struct.
+package cyborgdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TypedItem is a vector item whose metadata is a value of type M.
+type TypedItem[M any] struct {
+	// ID is the item identifier.
+	ID string
+	// Vector is the embedding; it may be nil if Contents is embedded.
+	Vector []float32
+	// Contents is the optional text contents.
+	Contents *string
+	// Metadata is encoded to JSON through M's field tags.
+	Metadata M
+}
+
+// TypedResult is a query result whose metadata is decoded into M.
+type TypedResult[M any] struct {
+	ID       string
+	Distance float32
+	Score    float32
+	Vector   []float32
+	Metadata M
+}
+
+// TypedIndex wraps an EncryptedIndex so items are written and read with
+// metadata of type M, a struct (or map) encoded through encoding/json.
+// Filters still refer to the JSON field names.
+//
+// Example:
+//
+//	type Doc struct {
+//		Title string `json:"title"`
+//		Year  int    `json:"year"`
+//	}
+//	docs := cyborgdb.NewTypedIndex[Doc](index)
+//	err := docs.Upsert(ctx, []cyborgdb.TypedItem[Doc]{{ID: "a", Vector: vec, Metadata: Doc{"Go", 2009}}})
+//	results, err := docs.Query(ctx, vec, cyborgdb.WithTopK(5))
+//	fmt.Println(results[0].Metadata.Title)
+type TypedIndex[M any] struct {
+	index *EncryptedIndex
+}
+
+// NewTypedIndex wraps index for metadata of type M.
+func NewTypedIndex[M any](index *EncryptedIndex) *TypedIndex[M] {
+	return &TypedIndex[M]{index: index}
+}
+
+// Index returns the underlying EncryptedIndex.
+func (t *TypedIndex[M]) Index() *EncryptedIndex { return t.index }
+
+// Upsert encodes each item's metadata and upserts the items.
+func (t *TypedIndex[M]) Upsert(ctx context.Context, items []TypedItem[M]) error {
+	out := make([]VectorItem, len(items))
+	for i, item := range items {
+		metadata, err := encodeTypedMetadata(item.Metadata)
+		if err != nil {
+			return fmt.Errorf("item %q: %w", item.ID, err)
+		}
+		out[i] = VectorItem{Id: item.ID, Vector: item.Vector, Metadata: metadata}
+		if item.Contents != nil {
+			out[i].Contents = TextContents(*item.Contents)
+		}
+	}
+	return t.index.Upsert(ctx, out)
+}
+
+// Query runs a single-vector query and decodes each result's metadata into
+// M. "metadata" is added to the included fields if missing.
+func (t *TypedIndex[M]) Query(ctx context.Context, vector []float32, opts ...QueryOption) ([]TypedResult[M], error) {
+	opts = append(opts, func(p *QueryParams) {
+		switch {
+		case len(p.Include) == 0:
+			p.Include = append([]string(nil), defaultQueryInclude...)
+		case !containsString(p.Include, "metadata"):
+			p.Include = append(append([]string(nil), p.Include...), "metadata")
+		}
+	})
+	results, err := t.index.QueryOne(ctx, vector, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TypedResult[M], len(results))
+	for i, r := range results {
+		out[i] = TypedResult[M]{ID: r.ID, Distance: r.Distance, Score: r.Score, Vector: r.Vector}
+		if err := decodeTypedMetadata(r.Metadata, &out[i].Metadata); err != nil {
+			return nil, fmt.Errorf("result %q: %w", r.ID, err)
+		}
+	}
+	return out, nil
+}
+
+// Get retrieves items by ID with their vectors and decoded metadata.
+func (t *TypedIndex[M]) Get(ctx context.Context, ids []string) ([]TypedItem[M], error) {
+	resp, err := t.index.Get(ctx, ids, []string{"vector", "metadata", "contents"})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TypedItem[M], len(resp.Results))
+	for i, r := range resp.Results {
+		out[i] = TypedItem[M]{ID: r.Id, Vector: r.Vector}
+		if text, ok := contentsText(r.Contents); ok {
+			out[i].Contents = &text
+		}
+		if err := decodeTypedMetadata(r.Metadata, &out[i].Metadata); err != nil {
+			return nil, fmt.Errorf("item %q: %w", r.Id, err)
+		}
+	}
+	return out, nil
+}
+
+// Delete removes items by ID.
+func (t *TypedIndex[M]) Delete(ctx context.Context, ids []string) error {
+	return t.index.Delete(ctx, ids)
+}
+
+// encodeTypedMetadata converts m to a metadata map through JSON.
+func encodeTypedMetadata[M any](m M) (map[string]interface{}, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetadata, err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("%w: metadata must encode to a JSON object: %v", ErrInvalidMetadata, err)
+	}
+	return out, nil
+}
+
+// decodeTypedMetadata converts a metadata map into dst through JSON. Missing
+// metadata leaves dst at its zero value.
+func decodeTypedMetadata[M any](m map[string]interface{}, dst *M) error {
+	if m == nil {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	return nil
+}
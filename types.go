@@ -3,6 +3,9 @@
 package cyborgdb
 
 import (
+	"encoding/base64"
+	"fmt"
+
 	"github.com/cyborginc/cyborgdb-go/internal"
 )
 
@@ -12,18 +15,82 @@ import (
 // GetResponse represents the response from Get operations, containing retrieved vectors and metadata.
 type GetResponse = internal.GetResponseModel
 
+// GetResultItem represents a single item returned by Get, GetAll, or Scan.
+type GetResultItem = internal.GetResultItemModel
+
 // VectorItem represents a single vector with ID, vector data, and optional metadata.
 type VectorItem = internal.VectorItem
 
 // QueryResponse represents the response from similarity search operations.
+//
+// Its Results field is a union of a single result list and one list per
+// query vector; use Single, At, Len, and IsBatch instead of inspecting it.
+// Explain is set only when QueryParams.Explain was.
 type QueryResponse = internal.QueryResponse
 
+// QueryExplain is the diagnostic information returned in
+// QueryResponse.Explain. See QueryParams.Explain.
+type QueryExplain = internal.QueryExplain
+
+// QueryTiming is the time breakdown in QueryExplain.
+type QueryTiming = internal.QueryTiming
+
 // QueryResultItem represents a single result from a similarity search query.
 type QueryResultItem = internal.QueryResultItem
 
 // ListIDsResponse represents the response from ListIDs operations.
 type ListIDsResponse = internal.ListIDsResponse
 
+// NullableContents represents the optional text or binary contents of a vector item.
+type NullableContents = internal.NullableContents
+
+// Contents holds the text or binary contents of a vector item.
+type Contents = internal.Contents
+
+// TextContents wraps a string as NullableContents for use in VectorItem.Contents.
+func TextContents(text string) NullableContents {
+	return *internal.NewNullableContents(&internal.Contents{String: &text})
+}
+
+// ContentsText returns the text of contents and whether it holds text. It
+// saves unwrapping the nullable type returned by Get.
+func ContentsText(contents NullableContents) (string, bool) {
+	return contentsText(contents)
+}
+
+// BinaryContents wraps raw bytes as NullableContents for use in
+// VectorItem.Contents. The bytes are sent base64-encoded (standard alphabet),
+// so arbitrary binary data round-trips without depending on UTF-8 validity;
+// read it back with ContentsBytes. Binary contents are never embedded.
+func BinaryContents(data []byte) NullableContents {
+	return *internal.NewNullableContents(&internal.Contents{Bytes: append([]byte{}, data...)})
+}
+
+// ContentsBytes returns the bytes of contents stored with BinaryContents.
+// Contents returned by the server arrive as a base64 string, which is
+// decoded; contents built locally with BinaryContents are returned as is.
+//
+// Returns:
+//   - []byte: The raw bytes, or nil if contents is unset
+//   - error: An error if the string is not valid base64 (e.g. it was stored
+//     with TextContents)
+func ContentsBytes(contents *Contents) ([]byte, error) {
+	switch {
+	case contents == nil:
+		return nil, nil
+	case contents.Bytes != nil:
+		return contents.Bytes, nil
+	case contents.String != nil:
+		data, err := base64.StdEncoding.DecodeString(*contents.String)
+		if err != nil {
+			return nil, fmt.Errorf("contents are not base64-encoded binary: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, nil
+	}
+}
+
 // IndexModel is the interface implemented by all index configuration types.
 // It allows type-safe creation of different index configurations (IVF, IVFFlat, IVFPQ)
 // while maintaining compatibility with the internal OpenAPI models.
@@ -32,6 +99,66 @@ type IndexModel interface {
 	ToIndexConfig() *internal.IndexConfig
 }
 
+// IndexConfig describes the configuration of an existing index, as returned
+// by EncryptedIndex.GetIndexConfig. It implements IndexModel, so it can be
+// passed to CreateIndex to create an index with the same configuration.
+type IndexConfig struct {
+	// Type is "ivf", "ivfflat", or "ivfpq"; empty if the configuration is unknown.
+	Type string `json:"type,omitempty"`
+	// Dimension is the vector dimension, 0 if unknown.
+	Dimension int32 `json:"dimension,omitempty"`
+	// PQDim is the product quantization dimension ("ivfpq" only).
+	PQDim int32 `json:"pq_dim,omitempty"`
+	// PQBits is the number of bits per PQ code ("ivfpq" only).
+	PQBits int32 `json:"pq_bits,omitempty"`
+}
+
+// IsZero reports whether the configuration is unknown.
+func (c IndexConfig) IsZero() bool { return c.Type == "" }
+
+// ToIndexConfig converts the configuration to the internal IndexConfig format.
+// This method implements the IndexModel interface.
+func (c IndexConfig) ToIndexConfig() *internal.IndexConfig {
+	var dimension *int32
+	if c.Dimension > 0 {
+		dimension = &c.Dimension
+	}
+	indexType := c.Type
+	switch c.Type {
+	case "ivf":
+		return &internal.IndexConfig{IndexIVFModel: &internal.IndexIVFModel{
+			Dimension: *internal.NewNullableInt32(dimension), Type: &indexType,
+		}}
+	case "ivfpq":
+		return &internal.IndexConfig{IndexIVFPQModel: &internal.IndexIVFPQModel{
+			Dimension: *internal.NewNullableInt32(dimension), Type: &indexType, PqDim: c.PQDim, PqBits: c.PQBits,
+		}}
+	case "ivfflat":
+		return &internal.IndexConfig{IndexIVFFlatModel: &internal.IndexIVFFlatModel{
+			Dimension: *internal.NewNullableInt32(dimension), Type: &indexType,
+		}}
+	default:
+		return &internal.IndexConfig{}
+	}
+}
+
+// newIndexConfig converts an internal configuration to IndexConfig.
+func newIndexConfig(config *internal.IndexConfig) IndexConfig {
+	switch {
+	case config == nil:
+		return IndexConfig{}
+	case config.IndexIVFModel != nil:
+		return IndexConfig{Type: "ivf", Dimension: config.IndexIVFModel.GetDimension()}
+	case config.IndexIVFFlatModel != nil:
+		return IndexConfig{Type: "ivfflat", Dimension: config.IndexIVFFlatModel.GetDimension()}
+	case config.IndexIVFPQModel != nil:
+		m := config.IndexIVFPQModel
+		return IndexConfig{Type: "ivfpq", Dimension: m.GetDimension(), PQDim: m.GetPqDim(), PQBits: m.GetPqBits()}
+	default:
+		return IndexConfig{}
+	}
+}
+
 // CreateIndexParams defines the parameters for creating a new encrypted vector index.
 //
 // This type provides a more ergonomic interface than the internal CreateIndexRequest,
@@ -39,10 +166,13 @@ type IndexModel interface {
 //
 // Fields:
 //   - IndexName: Unique identifier for the index (required)
-//   - IndexKey: 64-character hex string of the 32-byte encryption key (required)
+//   - IndexKey: 64-character hex string of the 32-byte encryption key (required unless KeyProvider is set)
+//   - KeyProvider: Source of the index key, used when IndexKey is empty (optional)
 //   - IndexConfig: Index configuration specifying the index type and parameters (optional)
 //   - Metric: Distance metric for similarity calculations (optional, defaults to "euclidean")
 //   - EmbeddingModel: Name of embedding model to associate with the index (optional)
+//   - Embedder: Client-side embedder attached to the returned index (optional)
+//   - Labels: Labels recorded in the index catalog (optional)
 type CreateIndexParams struct {
 	// IndexName is the unique identifier for this index.
 	// Must be unique within your project and contain only alphanumeric characters,
@@ -51,8 +181,13 @@ type CreateIndexParams struct {
 
 	// IndexKey is the 32-byte encryption key used for end-to-end encryption of vector data.
 	// Generate using GenerateKey() or provide your own 32-byte key.
+	// May be left empty when KeyProvider is set.
 	IndexKey []byte `json:"index_key"`
 
+	// KeyProvider resolves the index key at call time (e.g. from a KMS).
+	// Used only when IndexKey is empty.
+	KeyProvider KeyProvider `json:"-"`
+
 	// IndexConfig specifies the index type and configuration.
 	// Can be created using IndexIVF(), IndexIVFFlat(), or IndexIVFPQ() functions.
 	// If nil, the server will use default configuration.
@@ -66,6 +201,14 @@ type CreateIndexParams struct {
 	// EmbeddingModel optionally associates an embedding model name with this index.
 	// This is for metadata purposes and doesn't affect index behavior.
 	EmbeddingModel *string `json:"embedding_model,omitempty"`
+
+	// Embedder optionally embeds Contents and QueryContents client-side.
+	// When set, it is attached to the returned EncryptedIndex.
+	Embedder Embedder `json:"-"`
+
+	// Labels optionally tag the index (e.g. team, env, purpose) in the index
+	// catalog, for filtering with ListIndexesDetailed. Requires WithIndexCatalog.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // TrainParams defines the parameters for training an encrypted vector index.
@@ -149,6 +292,44 @@ type QueryParams struct {
 	// Common values: ["metadata"], ["vector"], ["metadata", "vector"].
 	// An empty slice may return only IDs and distances.
 	Include []string `json:"include"`
+
+	// RerankExact fetches RerankCandidates approximate results with their
+	// vectors, recomputes exact distances client-side using the index metric,
+	// and returns the closest TopK. It improves precision for lossy indexes
+	// such as IVFPQ without retraining. Requires a query vector.
+	RerankExact bool `json:"-"`
+
+	// RerankCandidates is the number of candidates fetched for RerankExact
+	// and MMRLambda. Values below TopK default to DefaultRerankFactor times TopK.
+	RerankCandidates int32 `json:"-"`
+
+	// MMRLambda, if set, diversifies the TopK results with Maximal Marginal
+	// Relevance over RerankCandidates candidates: 1 ranks purely by relevance,
+	// 0 purely by diversity. See MMR.
+	MMRLambda *float64 `json:"-"`
+
+	// MinScore, if set, drops results whose normalized similarity (see
+	// Similarity) is below it. The cut-off is applied client-side after
+	// re-ranking, so fewer than TopK results may be returned.
+	MinScore *float32 `json:"-"`
+
+	// SparseVector, if set, adds a sparse query vector to a single query, for
+	// hybrid lexical-semantic retrieval. Requires server sparse support
+	// (Capabilities.SparseVectors); ErrSparseUnsupported is returned otherwise.
+	SparseVector *SparseVector `json:"-"`
+
+	// Offset skips that many results, for paging: the query returns results
+	// Offset through Offset+TopK-1, in the order the full query (including
+	// any re-ranking or MMR) would return them. The service has no
+	// offset, so Offset+TopK results are fetched and the first Offset are
+	// dropped client-side. See QueryPage for continuation tokens.
+	Offset int32 `json:"-"`
+
+	// Explain attaches a QueryExplain to the response: the effective
+	// n_probes and lists scanned, the candidates returned by the server before
+	// client-side filtering, and a time breakdown. The service reports no
+	// diagnostics, so they are derived client-side.
+	Explain bool `json:"-"`
 }
 
 // Index model wrapper types provide type-safe access to different index configurations.
@@ -264,3 +445,20 @@ func (m *indexIVFPQ) ToIndexConfig() *internal.IndexConfig {
 		IndexIVFPQModel: m.IndexIVFPQModel,
 	}
 }
+
+// indexModelFromConfig wraps an existing internal.IndexConfig as an IndexModel,
+// returning nil if the configuration is nil or empty.
+func indexModelFromConfig(config *internal.IndexConfig) IndexModel {
+	switch {
+	case config == nil:
+		return nil
+	case config.IndexIVFModel != nil:
+		return &indexIVF{IndexIVFModel: config.IndexIVFModel}
+	case config.IndexIVFFlatModel != nil:
+		return &indexIVFFlat{IndexIVFFlatModel: config.IndexIVFFlatModel}
+	case config.IndexIVFPQModel != nil:
+		return &indexIVFPQ{IndexIVFPQModel: config.IndexIVFPQModel}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+// upsertresult.go implements upserts that report what they changed.
+package cyborgdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cyborginc/cyborgdb-go/internal"
+)
+
+// UpsertResult reports what an upsert changed, for ingestion metrics.
+type UpsertResult struct {
+	// Inserted is the number of items whose ID did not exist before.
+	Inserted int `json:"inserted"`
+	// Updated is the number of items that replaced an existing item,
+	// including repeats of an ID within the same call.
+	Updated int `json:"updated"`
+	// TrainingTriggered reports whether the upsert started automatic training.
+	TrainingTriggered bool `json:"training_triggered"`
+	// Message is the server's status message.
+	Message string `json:"message,omitempty"`
+	// Warnings lists non-fatal conditions the server reported, such as a
+	// status other than "success" or a training message.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// newUpsertResult reads the server's part of an UpsertResult from resp.
+func newUpsertResult(resp *internal.CyborgdbServiceApiSchemasVectorsSuccessResponseModel) *UpsertResult {
+	result := &UpsertResult{}
+	if resp == nil {
+		return result
+	}
+	result.Message = resp.Message
+	result.TrainingTriggered = resp.HasTrainingTriggered() && resp.GetTrainingTriggered()
+	if status := resp.GetStatus(); status != "" && status != "success" {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("status %q: %s", status, resp.Message))
+	}
+	if msg := resp.GetTrainingMessage(); msg != "" {
+		result.Warnings = append(result.Warnings, msg)
+	}
+	return result
+}
+
+// UpsertWithResult is Upsert, additionally reporting how many items were
+// inserted or updated, whether training was triggered, and any warnings the
+// server returned.
+//
+// The service does not report inserted and updated counts, so they are
+// computed by checking which IDs exist before writing, at the cost of one
+// extra read request (no item data is fetched). A concurrent writer touching
+// the same IDs between the check and the write can skew the split; the total
+// is always len(items).
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - items: Items to insert or update
+//
+// Returns:
+//   - *UpsertResult: Counts, training state, and server warnings
+//   - error: Any error checking or writing; nothing is reported on error
+//
+// Example:
+//
+//	res, err := index.UpsertWithResult(ctx, items)
+//	if err == nil {
+//		metrics.Inserted.Add(float64(res.Inserted))
+//		metrics.Updated.Add(float64(res.Updated))
+//	}
+func (e *EncryptedIndex) UpsertWithResult(ctx context.Context, items []VectorItem) (*UpsertResult, error) {
+	if len(items) == 0 {
+		return &UpsertResult{}, nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+	// Request no fields; only the returned IDs matter.
+	existing, err := e.Get(ctx, ids, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing items: %w", err)
+	}
+	exists := make(map[string]bool, len(ids))
+	for _, item := range existing.Results {
+		exists[item.Id] = true
+	}
+
+	result, err := e.upsertItems(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	if result.TrainingTriggered {
+		e.setTrained(false)
+	}
+	for _, id := range ids {
+		if exists[id] {
+			result.Updated++
+		} else {
+			result.Inserted++
+			exists[id] = true
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,217 @@
+// Package vecmath provides the vector arithmetic needed around an encrypted
+// index: L2 normalization before upserting into cosine indexes, and the
+// distances the service uses, for checking or re-scoring results locally.
+//
+// The loops accumulate into four independent float32 partial sums, so the
+// compiler can keep them in registers and the CPU can overlap the
+// multiply-adds; results may differ from a sequential float64 sum in the last
+// bits. Functions taking two vectors panic if their lengths differ, like
+// indexing out of range would.
+//
+// Example:
+//
+//	for i := range items {
+//		vecmath.NormalizeL2InPlace(items[i].Vector)
+//	}
+//	err := index.Upsert(ctx, items)
+package vecmath
+
+import (
+	"fmt"
+	"math"
+)
+
+// Metric names, matching the index metrics of the service.
+const (
+	MetricCosine           = "cosine"
+	MetricEuclidean        = "euclidean"
+	MetricSquaredEuclidean = "squared_euclidean"
+)
+
+// checkLen panics if a and b differ in length.
+func checkLen(a, b []float32) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("vecmath: length mismatch: %d != %d", len(a), len(b)))
+	}
+}
+
+// Dot returns the dot product of a and b.
+func Dot(a, b []float32) float32 {
+	checkLen(a, b)
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		x, y := a[i:i+4:i+4], b[i:i+4:i+4]
+		s0 += x[0] * y[0]
+		s1 += x[1] * y[1]
+		s2 += x[2] * y[2]
+		s3 += x[3] * y[3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// Norm returns the L2 norm of v.
+func Norm(v []float32) float32 {
+	return float32(math.Sqrt(float64(Dot(v, v))))
+}
+
+// NormalizeL2 returns a copy of v scaled to unit L2 norm. A zero vector is
+// returned as a zero copy.
+func NormalizeL2(v []float32) []float32 {
+	out := make([]float32, len(v))
+	copy(out, v)
+	NormalizeL2InPlace(out)
+	return out
+}
+
+// NormalizeL2InPlace scales v to unit L2 norm and reports whether it could;
+// a zero vector is left unchanged and false is returned.
+func NormalizeL2InPlace(v []float32) bool {
+	norm := Norm(v)
+	if norm == 0 || math.IsNaN(float64(norm)) {
+		return false
+	}
+	Scale(v, 1/norm)
+	return true
+}
+
+// NormalizeBatch scales every vector in vs to unit L2 norm in place and
+// returns the number of zero vectors left unchanged.
+func NormalizeBatch(vs [][]float32) int {
+	zero := 0
+	for _, v := range vs {
+		if !NormalizeL2InPlace(v) {
+			zero++
+		}
+	}
+	return zero
+}
+
+// Scale multiplies every element of v by f in place.
+func Scale(v []float32, f float32) {
+	i := 0
+	for ; i+4 <= len(v); i += 4 {
+		x := v[i : i+4 : i+4]
+		x[0] *= f
+		x[1] *= f
+		x[2] *= f
+		x[3] *= f
+	}
+	for ; i < len(v); i++ {
+		v[i] *= f
+	}
+}
+
+// Cosine returns the cosine similarity of a and b, in [-1, 1], or 0 if
+// either is a zero vector.
+func Cosine(a, b []float32) float32 {
+	checkLen(a, b)
+	var d0, d1, na0, na1, nb0, nb1 float32
+	i := 0
+	for ; i+2 <= len(a); i += 2 {
+		x, y := a[i:i+2:i+2], b[i:i+2:i+2]
+		d0 += x[0] * y[0]
+		d1 += x[1] * y[1]
+		na0 += x[0] * x[0]
+		na1 += x[1] * x[1]
+		nb0 += y[0] * y[0]
+		nb1 += y[1] * y[1]
+	}
+	for ; i < len(a); i++ {
+		d0 += a[i] * b[i]
+		na0 += a[i] * a[i]
+		nb0 += b[i] * b[i]
+	}
+	na, nb := na0+na1, nb0+nb1
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return (d0 + d1) / float32(math.Sqrt(float64(na))*math.Sqrt(float64(nb)))
+}
+
+// CosineDistance returns 1 minus the cosine similarity of a and b, the
+// distance the service reports for cosine indexes.
+func CosineDistance(a, b []float32) float32 {
+	return 1 - Cosine(a, b)
+}
+
+// SquaredEuclidean returns the squared L2 distance between a and b.
+func SquaredEuclidean(a, b []float32) float32 {
+	checkLen(a, b)
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		x, y := a[i:i+4:i+4], b[i:i+4:i+4]
+		d0, d1, d2, d3 := x[0]-y[0], x[1]-y[1], x[2]-y[2], x[3]-y[3]
+		s0 += d0 * d0
+		s1 += d1 * d1
+		s2 += d2 * d2
+		s3 += d3 * d3
+	}
+	for ; i < len(a); i++ {
+		d := a[i] - b[i]
+		s0 += d * d
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// Euclidean returns the L2 distance between a and b.
+func Euclidean(a, b []float32) float32 {
+	return float32(math.Sqrt(float64(SquaredEuclidean(a, b))))
+}
+
+// DistanceFunc returns the distance function for metric (MetricCosine,
+// MetricEuclidean, or MetricSquaredEuclidean), or nil for an unknown metric.
+func DistanceFunc(metric string) func(a, b []float32) float32 {
+	switch metric {
+	case MetricCosine:
+		return CosineDistance
+	case MetricEuclidean:
+		return Euclidean
+	case MetricSquaredEuclidean:
+		return SquaredEuclidean
+	default:
+		return nil
+	}
+}
+
+// DotBatch computes the dot product of query with each vector, writing the
+// results to out, which is grown if shorter than vectors and returned.
+func DotBatch(query []float32, vectors [][]float32, out []float32) []float32 {
+	return batch(Dot, query, vectors, out)
+}
+
+// CosineBatch computes the cosine similarity of query with each vector. See
+// DotBatch. For many queries against the same vectors, normalize both sides
+// once with NormalizeBatch and use DotBatch instead.
+func CosineBatch(query []float32, vectors [][]float32, out []float32) []float32 {
+	return batch(Cosine, query, vectors, out)
+}
+
+// SquaredEuclideanBatch computes the squared L2 distance from query to each
+// vector. See DotBatch.
+func SquaredEuclideanBatch(query []float32, vectors [][]float32, out []float32) []float32 {
+	return batch(SquaredEuclidean, query, vectors, out)
+}
+
+// EuclideanBatch computes the L2 distance from query to each vector. See
+// DotBatch.
+func EuclideanBatch(query []float32, vectors [][]float32, out []float32) []float32 {
+	return batch(Euclidean, query, vectors, out)
+}
+
+// batch applies fn to query and each vector, reusing out when it is large
+// enough.
+func batch(fn func(a, b []float32) float32, query []float32, vectors [][]float32, out []float32) []float32 {
+	if cap(out) < len(vectors) {
+		out = make([]float32, len(vectors))
+	}
+	out = out[:len(vectors)]
+	for i, v := range vectors {
+		out[i] = fn(query, v)
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+// vectorconv.go converts vectors in other numeric formats (float64, float16)
+// to the float32 vectors used by the API.
+package cyborgdb
+
+import "math"
+
+// Float is the set of floating-point element types accepted by the
+// conversion helpers.
+type Float interface {
+	~float32 | ~float64
+}
+
+// ToFloat32 converts a vector of any float type to []float32.
+//
+// Example:
+//
+//	embedding := model.Embed(text) // []float64
+//	hits, err := index.QueryOne(ctx, cyborgdb.ToFloat32(embedding))
+func ToFloat32[T Float](v []T) []float32 {
+	if v == nil {
+		return nil
+	}
+	out := make([]float32, len(v))
+	for i, f := range v {
+		out[i] = float32(f)
+	}
+	return out
+}
+
+// ToFloat32Batch converts a batch of vectors of any float type to [][]float32.
+func ToFloat32Batch[T Float](vs [][]T) [][]float32 {
+	if vs == nil {
+		return nil
+	}
+	out := make([][]float32, len(vs))
+	for i, v := range vs {
+		out[i] = ToFloat32(v)
+	}
+	return out
+}
+
+// NewVectorItem returns a VectorItem for id with v converted to float32.
+//
+// Example:
+//
+//	item := cyborgdb.NewVectorItem("doc1", embedding64)
+//	item.Metadata = map[string]interface{}{"source": "wiki"}
+func NewVectorItem[T Float](id string, v []T) VectorItem {
+	return VectorItem{Id: id, Vector: ToFloat32(v)}
+}
+
+// Float16ToFloat32 converts IEEE 754 half-precision values, given as their
+// raw bits, to []float32. Use it for embeddings stored as float16.
+func Float16ToFloat32(v []uint16) []float32 {
+	out := make([]float32, len(v))
+	for i, h := range v {
+		out[i] = float16ToFloat32(h)
+	}
+	return out
+}
+
+// Float32ToFloat16 converts v to IEEE 754 half-precision bits, rounding to
+// nearest even. Values beyond the float16 range become infinities.
+func Float32ToFloat16(v []float32) []uint16 {
+	out := make([]uint16, len(v))
+	for i, f := range v {
+		out[i] = float32ToFloat16(f)
+	}
+	return out
+}
+
+// float16ToFloat32 decodes one half-precision value.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+
+	switch {
+	case exp == 0 && mant == 0: // zero
+		return math.Float32frombits(sign)
+	case exp == 0: // subnormal: normalize
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	case exp == 0x1f: // infinity or NaN
+		return math.Float32frombits(sign | 0xff<<23 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// float32ToFloat16 encodes one value as half precision.
+func float32ToFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+
+	switch {
+	case bits&0x7fffffff > 0x7f800000: // NaN
+		return sign | 0x7e00
+	case exp >= 0x1f: // overflow or infinity
+		return sign | 0x7c00
+	case exp <= 0: // subnormal or zero
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	default:
+		half := uint16(exp)<<10 | uint16(mant>>13)
+		rem := mant & 0x1fff
+		if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+			half++ // may carry into the exponent, which rounds up correctly
+		}
+		return sign | half
+	}
+}
@@ -0,0 +1,110 @@
+// visibility.go implements waiting for writes to become visible to reads, so
+// callers need not sleep after Upsert or Delete.
+package cyborgdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultVisibilityPollInterval is the delay between reads while waiting
+	// for writes to become visible.
+	DefaultVisibilityPollInterval = 200 * time.Millisecond
+	// DefaultVisibilityTimeout bounds the wait when ctx has no deadline.
+	DefaultVisibilityTimeout = 30 * time.Second
+)
+
+// ErrNotVisible is returned when writes are not visible before the wait ends.
+var ErrNotVisible = errors.New("writes not visible")
+
+// WaitForVisibility polls Get until every item in ids can be read, or the
+// wait ends. The wait is bounded by ctx, or DefaultVisibilityTimeout if ctx
+// has no deadline.
+//
+// Parameters:
+//   - ctx: Context bounding the total wait
+//   - ids: IDs that must be readable
+//
+// Returns:
+//   - error: nil once all IDs are visible, otherwise ErrNotVisible wrapped
+//     with the number still missing and the last read error
+//
+// Example:
+//
+//	if err := index.Upsert(ctx, items); err != nil {
+//		return err
+//	}
+//	err := index.WaitForVisibility(ctx, []string{"doc1", "doc2"})
+func (e *EncryptedIndex) WaitForVisibility(ctx context.Context, ids []string) error {
+	return e.waitForIDs(ctx, ids, true)
+}
+
+// WaitForDeletion polls Get until none of ids can be read, or the wait ends.
+// It is the counterpart of WaitForVisibility for Delete.
+func (e *EncryptedIndex) WaitForDeletion(ctx context.Context, ids []string) error {
+	return e.waitForIDs(ctx, ids, false)
+}
+
+// UpsertAndVerify upserts items and waits until all of them are visible.
+// See WaitForVisibility.
+func (e *EncryptedIndex) UpsertAndVerify(ctx context.Context, items []VectorItem) error {
+	if err := e.Upsert(ctx, items); err != nil {
+		return err
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Id
+	}
+	return e.WaitForVisibility(ctx, ids)
+}
+
+// DeleteAndVerify deletes ids and waits until none of them is visible.
+// See WaitForDeletion.
+func (e *EncryptedIndex) DeleteAndVerify(ctx context.Context, ids []string) error {
+	if err := e.Delete(ctx, ids); err != nil {
+		return err
+	}
+	return e.WaitForDeletion(ctx, ids)
+}
+
+// waitForIDs polls until every ID in ids is present (visible) or absent.
+func (e *EncryptedIndex) waitForIDs(ctx context.Context, ids []string, visible bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	pending := ids
+	err := Poll(ctx, func(ctx context.Context) (bool, error) {
+		// Request no fields; only the returned IDs matter.
+		resp, err := e.Get(ctx, pending, []string{})
+		if err != nil {
+			return false, err
+		}
+		found := make(map[string]bool, len(resp.Results))
+		for _, item := range resp.Results {
+			found[item.Id] = true
+		}
+		var next []string
+		for _, id := range pending {
+			if found[id] != visible {
+				next = append(next, id)
+			}
+		}
+		pending = next
+		return len(pending) == 0, nil
+	}, PollOptions{
+		InitialInterval: DefaultVisibilityPollInterval,
+		MaxInterval:     DefaultVisibilityPollInterval,
+		Timeout:         DefaultVisibilityTimeout,
+	})
+	if err != nil {
+		state := "missing"
+		if !visible {
+			state = "still present"
+		}
+		return fmt.Errorf("%w: %d of %d IDs %s: %v", ErrNotVisible, len(pending), len(ids), state, err)
+	}
+	return nil
+}